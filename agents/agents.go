@@ -17,7 +17,18 @@ type Agent interface {
 	GetTools() []tools.Tool
 }
 
+// FinalAnswerAgent is an Agent that can give a final answer based on the steps
+// taken so far, when the executor stops it early with EarlyStoppingGenerate.
+type FinalAnswerAgent interface {
+	Agent
+	FinalAnswer(ctx context.Context, intermediateSteps []schema.AgentStep, inputs map[string]string) (*schema.AgentFinish, error) //nolint:lll
+}
+
 // ConcurrentAgent is an interface that extends the Agent interface with concurrency methods.
+//
+// Deprecated: the Executor no longer calls these methods. It runs the actions
+// of a plan one at a time by default, and up to Executor.MaxConcurrency at a
+// time when it is raised with WithMaxConcurrency, whatever the agent.
 type ConcurrentAgent interface {
 	Agent
 	InitializeConcurrentActions(actions []schema.AgentAction)
@@ -0,0 +1,275 @@
+package agents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// _chatReActActionRegex matches an action in the text format of the
+// OneShotZeroAgent. The input can span several lines.
+var _chatReActActionRegex = regexp.MustCompile(`(?is)Action\s*:[ \t]*(.*?)\s*\n\s*Action\s*Input\s*:\s*(.*)`)
+
+// ChatReActAgent is an Agent using the ReAct framework with chat models. The
+// tools and the format are described in the system message, and the steps
+// taken are sent as alternating AI and human messages: the thought and action
+// of the model, followed by the observation. Stop sequences keep the model
+// from making up observations itself.
+//
+// The model is asked for actions as JSON blobs with an "action" and an
+// "action_input" key. The parser also accepts the "Action:" and "Action
+// Input:" lines of the OneShotZeroAgent, with inputs spanning several lines.
+type ChatReActAgent struct {
+	// LLM is the llm used to call with the values. The prompt should have a
+	// messages placeholder called "agent_scratchpad" for the steps taken.
+	LLM    llms.Model
+	Prompt prompts.FormatPrompter
+	// Tools is a list of the tools the agent can use.
+	Tools []tools.Tool
+	// StopSequences stop the generation of the model after an action.
+	StopSequences []string
+	// Output key is the key where the final output is placed.
+	OutputKey string
+	// CallbacksHandler is the handler for callbacks.
+	CallbacksHandler callbacks.Handler
+}
+
+var (
+	_ Agent            = (*ChatReActAgent)(nil)
+	_ FinalAnswerAgent = (*ChatReActAgent)(nil)
+)
+
+// NewChatReActAgent creates a new ChatReActAgent. The system message is made
+// of the prompt prefix, format instructions and suffix, which can be set with
+// the options. Extra messages can be added with OpenAIOption.WithExtraMessages.
+func NewChatReActAgent(llm llms.Model, tools []tools.Tool, opts ...Option) *ChatReActAgent {
+	options := chatReActDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &ChatReActAgent{
+		LLM:              llm,
+		Prompt:           createChatReActPrompt(tools, options),
+		Tools:            tools,
+		StopSequences:    []string{"Observation:"},
+		OutputKey:        options.outputKey,
+		CallbacksHandler: options.callbacksHandler,
+	}
+}
+
+// Plan decides what action to take or returns the final result of the input.
+func (a *ChatReActAgent) Plan(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	fullInputs := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		fullInputs[key] = value
+	}
+	fullInputs[agentScratchpad] = a.constructScratchPad(intermediateSteps)
+
+	prompt, err := a.Prompt.FormatPrompt(fullInputs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stream := streamingFunc(ctx, a.CallbacksHandler)
+
+	result, err := a.LLM.GenerateContent(ctx, chatMessagesToContent(prompt.Messages()),
+		llms.WithStopWords(a.StopSequences), llms.WithStreamingFunc(stream))
+	if err != nil {
+		return nil, nil, err
+	}
+	addResponseUsage(ctx, result)
+	if len(result.Choices) == 0 {
+		return nil, nil, fmt.Errorf("%w: no choices in response", ErrUnableToParseOutput)
+	}
+
+	return a.ParseOutput(result.Choices[0].Content)
+}
+
+// FinalAnswer asks the LLM for a final answer based on the steps taken so far.
+func (a *ChatReActAgent) FinalAnswer(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) (*schema.AgentFinish, error) {
+	fullInputs := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		fullInputs[key] = value
+	}
+	fullInputs[agentScratchpad] = a.constructScratchPad(intermediateSteps)
+
+	finish, err := generateMessagesFinalAnswer(ctx, a.LLM, a.Prompt, fullInputs, a.OutputKey,
+		streamingFunc(ctx, a.CallbacksHandler))
+	if err != nil {
+		return nil, err
+	}
+	if i := strings.LastIndex(finish.Log, _finalAnswerAction); i >= 0 {
+		finish.ReturnValues[a.OutputKey] = strings.TrimSpace(finish.Log[i+len(_finalAnswerAction):])
+	}
+	return finish, nil
+}
+
+// ParseOutput parses the output of the model into an action or a finish. An
+// action is taken from the first JSON blob with an "action" key, or else from
+// "Action:" and "Action Input:" lines. If the output has both an action and a
+// final answer, whichever comes first wins.
+func (a *ChatReActAgent) ParseOutput(output string) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	finalIndex := strings.Index(output, _finalAnswerAction)
+	tool, input, actionIndex, ok := parseChatReActAction(output)
+	if ok && (finalIndex < 0 || actionIndex < finalIndex) {
+		// Some models give their final answer as an action.
+		if strings.EqualFold(tool, strings.TrimSuffix(_finalAnswerAction, ":")) {
+			return nil, a.finish(input, output), nil
+		}
+		if json.Valid([]byte(input)) && strings.HasPrefix(input, "{") {
+			var err error
+			input, err = toolCallInput(findTool(a.Tools, tool), &llms.FunctionCall{Name: tool, Arguments: input})
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		return []schema.AgentAction{{Tool: tool, ToolInput: input, Log: output}}, nil, nil
+	}
+
+	if finalIndex >= 0 {
+		answer := output[strings.LastIndex(output, _finalAnswerAction)+len(_finalAnswerAction):]
+		return nil, a.finish(strings.TrimSpace(answer), output), nil
+	}
+
+	return nil, nil, fmt.Errorf("%w: %s", ErrUnableToParseOutput, output)
+}
+
+func (a *ChatReActAgent) finish(answer, log string) *schema.AgentFinish {
+	return &schema.AgentFinish{
+		ReturnValues: map[string]any{a.OutputKey: answer},
+		Log:          log,
+	}
+}
+
+// GetInputKeys returns the input keys of the agent, without the scratchpad.
+func (a *ChatReActAgent) GetInputKeys() []string {
+	chainInputs := a.Prompt.GetInputVariables()
+
+	agentInput := make([]string, 0, len(chainInputs))
+	for _, v := range chainInputs {
+		if v == agentScratchpad {
+			continue
+		}
+		agentInput = append(agentInput, v)
+	}
+
+	return agentInput
+}
+
+// GetOutputKeys returns the output keys of the agent.
+func (a *ChatReActAgent) GetOutputKeys() []string {
+	return []string{a.OutputKey}
+}
+
+// GetTools returns the tools of the agent.
+func (a *ChatReActAgent) GetTools() []tools.Tool {
+	return a.Tools
+}
+
+// constructScratchPad turns every step into an AI message holding the output
+// of the model, followed by a human message holding the observation. Steps
+// without an action, such as parser errors, only have the human message.
+func (a *ChatReActAgent) constructScratchPad(steps []schema.AgentStep) []llms.ChatMessage {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	messages := make([]llms.ChatMessage, 0, 2*len(steps))
+	for _, step := range steps {
+		if step.Action.Log != "" {
+			messages = append(messages, llms.AIChatMessage{Content: strings.TrimSpace(step.Action.Log)})
+		}
+		messages = append(messages, llms.HumanChatMessage{Content: _chatReActObservationPrefix + step.Observation})
+	}
+
+	return messages
+}
+
+// parseChatReActAction finds the action in the output of the model. It returns
+// the tool, its input and the index of the action in the output. Inputs given
+// as JSON objects are returned as compact JSON.
+func parseChatReActAction(output string) (string, string, int, bool) {
+	for i := strings.IndexByte(output, '{'); i >= 0; {
+		var blob map[string]json.RawMessage
+		if err := json.NewDecoder(strings.NewReader(output[i:])).Decode(&blob); err == nil {
+			if tool, ok := blobString(blob, "action"); ok {
+				input, _ := blobString(blob, "action_input")
+				return tool, input, i, true
+			}
+		}
+		next := strings.IndexByte(output[i+1:], '{')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+
+	if loc := _chatReActActionRegex.FindStringSubmatchIndex(output); loc != nil {
+		tool := trimChatReActInput(output[loc[2]:loc[3]])
+		// The input ends where the model went on with a made up observation or
+		// final answer.
+		input := output[loc[4]:loc[5]]
+		for _, marker := range []string{"\nObservation:", "\n" + _finalAnswerAction} {
+			input, _, _ = strings.Cut(input, marker)
+		}
+		return tool, trimChatReActInput(input), loc[0], tool != ""
+	}
+	return "", "", 0, false
+}
+
+// blobString returns the value of a key of a JSON blob, ignoring the case of
+// the key. Strings are unquoted and other values are returned as compact JSON.
+func blobString(blob map[string]json.RawMessage, key string) (string, bool) {
+	for k, raw := range blob {
+		if !strings.EqualFold(k, key) {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			return s, true
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, raw); err != nil {
+			return string(raw), true
+		}
+		return compact.String(), true
+	}
+	return "", false
+}
+
+// trimChatReActInput trims the spaces, code fences and quotes around a tool
+// or an input written in the text format.
+func trimChatReActInput(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "```") {
+		s = strings.TrimPrefix(s, "```")
+		// Drop the language of the code fence, if any.
+		if i := strings.IndexByte(s, '\n'); i >= 0 && !strings.ContainsAny(s[:i], " \t") {
+			s = s[i+1:]
+		}
+		s = strings.TrimSuffix(strings.TrimSpace(s), "```")
+		s = strings.TrimSpace(s)
+	}
+	if len(s) >= 2 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '`' && s[len(s)-1] == '`') {
+		s = s[1 : len(s)-1]
+	}
+	return s
+}
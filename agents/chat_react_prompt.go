@@ -0,0 +1,75 @@
+package agents
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/tools"
+)
+
+const (
+	_defaultChatReActPrefix = `Answer the following questions as best you can. You have access to the following tools:
+
+{{.tool_descriptions}}`
+
+	_defaultChatReActFormatInstructions = "To use a tool, respond with a thought followed by a JSON blob " +
+		`with the name of the tool as "action" and its input as "action_input", like this:
+
+Thought: you should always think about what to do
+Action:
+` + "```json" + `
+{"action": "the tool to use, one of [ {{.tool_names}} ]", "action_input": "the input of the tool"}
+` + "```" + `
+
+Use only one tool per response and stop after the JSON blob. The result of the tool is then sent to you in a message starting with "Observation:".
+Once you know the answer, respond with:
+
+Thought: I now know the final answer
+Final Answer: the final answer to the original input question`
+
+	_defaultChatReActSuffix = `Begin! Always use the exact format above.`
+
+	_chatReActObservationPrefix = "Observation: "
+)
+
+func createChatReActPrompt(tools []tools.Tool, opts Options) prompts.ChatPromptTemplate {
+	system := strings.Join([]string{opts.promptPrefix, opts.formatInstructions, opts.promptSuffix}, "\n\n")
+
+	messageFormatters := []prompts.MessageFormatter{prompts.SystemMessagePromptTemplate{
+		Prompt: prompts.PromptTemplate{
+			Template:       system,
+			TemplateFormat: prompts.TemplateFormatGoTemplate,
+			PartialVariables: map[string]any{
+				"tool_names":        toolNames(tools),
+				"tool_descriptions": chatReActToolDescriptions(tools),
+			},
+		},
+	}}
+	messageFormatters = append(messageFormatters, opts.extraMessages...)
+	messageFormatters = append(messageFormatters,
+		prompts.NewHumanMessagePromptTemplate("{{.input}}", []string{"input"}),
+		prompts.MessagesPlaceholder{VariableName: agentScratchpad},
+	)
+
+	return prompts.NewChatPromptTemplate(messageFormatters)
+}
+
+// chatReActToolDescriptions describes the tools like toolDescriptions, and adds
+// the JSON schema of the arguments of structured tools so that the model knows
+// what to put in the action input.
+func chatReActToolDescriptions(ts []tools.Tool) string {
+	var sb strings.Builder
+	for _, tool := range ts {
+		fmt.Fprintf(&sb, "- %s: %s", tool.Name(), tool.Description())
+		if st, ok := tool.(tools.StructuredTool); ok {
+			if schema, err := json.Marshal(st.Schema()); err == nil {
+				fmt.Fprintf(&sb, " The action input is a JSON object with the schema %s", schema)
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,145 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestChatReActAgentParseOutput(t *testing.T) {
+	t.Parallel()
+
+	type repeatArgs struct {
+		Text string `json:"text"`
+	}
+	repeat, err := tools.NewFunc("repeat", "Repeats a text.", func(_ context.Context, args repeatArgs) (string, error) {
+		return args.Text + args.Text, nil
+	})
+	require.NoError(t, err)
+	agent := NewChatReActAgent(&toolCallingLLM{}, []tools.Tool{upperTool{}, repeat})
+
+	tests := []struct {
+		name       string
+		output     string
+		wantTool   string
+		wantInput  string
+		wantAnswer string
+		wantErr    error
+	}{
+		{
+			name:      "json blob",
+			output:    "Thought: I need to upper-case it.\nAction:\n```json\n{\"action\": \"upper\", \"action_input\": \"a\"}\n```",
+			wantTool:  "upper",
+			wantInput: "a",
+		},
+		{
+			name:      "json blob with object input",
+			output:    "Action: {\n  \"action\": \"upper\",\n  \"action_input\": {\"input\": \"a b\"}\n}",
+			wantTool:  "upper",
+			wantInput: "a b",
+		},
+		{
+			name:      "json blob for structured tool",
+			output:    `Thought: {not json} Action: {"Action": "repeat", "Action_Input": {"text": "ab"}}`,
+			wantTool:  "repeat",
+			wantInput: `{"text":"ab"}`,
+		},
+		{
+			name:      "text with multi-line input",
+			output:    "Thought: go\nAction: upper\nAction Input: \"first line\nsecond line\"\n",
+			wantTool:  "upper",
+			wantInput: "first line\nsecond line",
+		},
+		{
+			name:      "text with fenced input",
+			output:    "Action: `repeat`\nAction Input:\n```json\n{\"text\": \"ab\"}\n```",
+			wantTool:  "repeat",
+			wantInput: `{"text": "ab"}`,
+		},
+		{
+			name:       "final answer",
+			output:     "Thought: I now know the final answer\nFinal Answer: A\nB",
+			wantAnswer: "A\nB",
+		},
+		{
+			name:       "final answer as action",
+			output:     `{"action": "Final Answer", "action_input": "A"}`,
+			wantAnswer: "A",
+		},
+		{
+			name:      "action before made up final answer",
+			output:    "Action: upper\nAction Input: a\nFinal Answer: A",
+			wantTool:  "upper",
+			wantInput: "a",
+		},
+		{
+			name:    "no action",
+			output:  "I am not sure what to do.",
+			wantErr: ErrUnableToParseOutput,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actions, finish, err := agent.ParseOutput(tc.output)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tc.wantAnswer != "" {
+				require.NotNil(t, finish)
+				assert.Equal(t, tc.wantAnswer, finish.ReturnValues["output"])
+				return
+			}
+			require.Len(t, actions, 1)
+			assert.Equal(t, tc.wantTool, actions[0].Tool)
+			assert.Equal(t, tc.wantInput, actions[0].ToolInput)
+			assert.Equal(t, tc.output, actions[0].Log)
+		})
+	}
+}
+
+func TestChatReActAgentScratchpad(t *testing.T) {
+	t.Parallel()
+
+	action := "Thought: I need to upper-case it.\nAction:\n```json\n{\"action\": \"upper\", \"action_input\": \"a\"}\n```"
+	llm := &toolCallingLLM{responses: textResponses(
+		"I will just guess.",
+		action,
+		"Thought: I now know the final answer\nFinal Answer: A",
+	)}
+	executor := NewExecutor(NewChatReActAgent(llm, []tools.Tool{upperTool{}}),
+		WithParserErrorHandler(NewParserErrorHandler(nil)), WithReturnIntermediateSteps())
+
+	outputs, err := chains.Call(context.Background(), executor, map[string]any{"input": "upper-case a"})
+	require.NoError(t, err)
+	assert.Equal(t, "A", outputs["output"])
+	steps, ok := outputs[_intermediateStepsOutputKey].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 2)
+
+	require.Len(t, llm.calls, 3)
+	system := llm.calls[0][0]
+	assert.Equal(t, llms.ChatMessageTypeSystem, system.Role)
+	assert.Contains(t, system.Parts[0].(llms.TextContent).Text, "- upper: Upper-cases the input.")
+	assert.Equal(t, []string{"Observation:"}, llm.options[0].StopWords)
+
+	// The parser error is sent as an observation, the action as an AI message
+	// followed by its observation.
+	last := llm.calls[2][2:]
+	require.Len(t, last, 3)
+	assert.Equal(t, llms.ChatMessageTypeHuman, last[0].Role)
+	assert.True(t, strings.HasPrefix(last[0].Parts[0].(llms.TextContent).Text, "Observation: "))
+	assert.Equal(t, llms.TextParts(llms.ChatMessageTypeAI, action), last[1])
+	assert.Equal(t, llms.TextParts(llms.ChatMessageTypeHuman, "Observation: A"), last[2])
+}
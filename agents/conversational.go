@@ -38,7 +38,10 @@ type ConversationalAgent struct {
 	CallbacksHandler callbacks.Handler
 }
 
-var _ Agent = (*ConversationalAgent)(nil)
+var (
+	_ Agent            = (*ConversationalAgent)(nil)
+	_ FinalAnswerAgent = (*ConversationalAgent)(nil)
+)
 
 func NewConversationalAgent(llm llms.Model, tools []tools.Tool, opts ...Option) *ConversationalAgent {
 	options := conversationalDefaultOptions()
@@ -48,7 +51,7 @@ func NewConversationalAgent(llm llms.Model, tools []tools.Tool, opts ...Option)
 
 	return &ConversationalAgent{
 		Chain: chains.NewLLMChain(
-			llm,
+			usageReportingModel{Model: llm},
 			options.getConversationalPrompt(tools),
 			chains.WithCallback(options.callbacksHandler),
 		),
@@ -71,14 +74,7 @@ func (a *ConversationalAgent) Plan(
 
 	fullInputs["agent_scratchpad"] = constructScratchPad(intermediateSteps)
 
-	var stream func(ctx context.Context, chunk []byte) error
-
-	if a.CallbacksHandler != nil {
-		stream = func(ctx context.Context, chunk []byte) error {
-			a.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
-			return nil
-		}
-	}
+	stream := streamingFunc(ctx, a.CallbacksHandler)
 
 	output, err := chains.Predict(
 		ctx,
@@ -94,6 +90,22 @@ func (a *ConversationalAgent) Plan(
 	return a.parseOutput(output)
 }
 
+// FinalAnswer asks the LLM for a final answer based on the steps taken so far.
+func (a *ConversationalAgent) FinalAnswer(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) (*schema.AgentFinish, error) {
+	fullInputs := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		fullInputs[key] = value
+	}
+	fullInputs["agent_scratchpad"] = constructScratchPad(intermediateSteps)
+
+	return generateChainFinalAnswer(ctx, a.Chain, fullInputs, a.parseOutput, a.OutputKey,
+		streamingFunc(ctx, a.CallbacksHandler))
+}
+
 func (a *ConversationalAgent) GetInputKeys() []string {
 	chainInputs := a.Chain.GetInputKeys()
 
@@ -10,7 +10,10 @@
 // Package agents provides and implementation of the agent interface called
 // OneShotZeroAgent. This agent uses the ReAct Framework (based on the
 // descriptions of tools) to decide what action to take. This agent is
-// optimized to be used with LLMs.
+// optimized to be used with LLMs. The ToolCallingAgent instead relies on the
+// native tool calling of chat models, and can ask for several tools at once.
+// The ChatReActAgent uses the ReAct Framework with chat models that have no
+// tool calling, sending the steps taken as chat messages.
 //
 // To make agents more powerful we need to make them iterative, i.e. call the
 // model multiple times until they arrive at the final answer. That's the job of
@@ -18,5 +21,14 @@
 // responsible for calling the agent, getting back and action and action input,
 // calling the tool that the action references with the corresponding input,
 // getting the output of the tool, and then passing all that information back
-// into the Agent to get the next action it should take.
+// into the Agent to get the next action it should take. Executor.Stream runs
+// the same loop and emits an Event for every step as it happens. Guardrails
+// given to the Executor check every action before its tool is called, and can
+// deny or rewrite it.
+//
+// For long tasks PlanAndExecute first has an LLM write a plan, then runs an
+// agent in an Executor for every step of the plan and revises the steps left
+// after each of them. The plan and the results of its steps are returned with
+// the final answer. Reflection has a critic review the output of an agent, or
+// of any chain, and the agent revise it until the critic accepts it.
 package agents
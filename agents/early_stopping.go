@@ -0,0 +1,175 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// EarlyStoppingMethod tells what the executor does when it runs out of
+// iterations or budget before the agent finished.
+type EarlyStoppingMethod string
+
+const (
+	// EarlyStoppingForce stops the run with ErrNotFinished.
+	EarlyStoppingForce EarlyStoppingMethod = "force"
+	// EarlyStoppingGenerate asks the agent for a final answer based on the
+	// steps taken so far. The agent must implement FinalAnswerAgent, otherwise
+	// the run stops like with EarlyStoppingForce.
+	EarlyStoppingGenerate EarlyStoppingMethod = "generate"
+)
+
+const (
+	_finalAnswerThought = "\n\nI now need to return a final answer based on the previous steps:"
+	_finalAnswerMessage = "You have run out of steps. Give your final answer to the request now, " +
+		"based on the results of the tools so far, without calling any more tools."
+)
+
+// budget holds the limits of one run of an executor and what was used of them.
+type budget struct {
+	maxDuration  time.Duration
+	maxTokens    int
+	maxToolCalls int
+
+	start     time.Time
+	tokens    atomic.Int64
+	toolCalls int
+}
+
+type budgetContextKey struct{}
+
+func newBudget(e *Executor) *budget {
+	return &budget{
+		maxDuration:  e.MaxDuration,
+		maxTokens:    e.MaxTokens,
+		maxToolCalls: e.MaxToolCalls,
+		start:        time.Now(),
+	}
+}
+
+// exceeded returns an error wrapping ErrBudgetExceeded if any limit is reached.
+func (b *budget) exceeded() error {
+	if b.maxDuration > 0 && time.Since(b.start) >= b.maxDuration {
+		return fmt.Errorf("%w: ran for more than %s", ErrBudgetExceeded, b.maxDuration)
+	}
+	if tokens := b.tokens.Load(); b.maxTokens > 0 && tokens >= int64(b.maxTokens) {
+		return fmt.Errorf("%w: used %d of %d tokens", ErrBudgetExceeded, tokens, b.maxTokens)
+	}
+	if b.maxToolCalls > 0 && b.toolCalls >= b.maxToolCalls {
+		return fmt.Errorf("%w: made %d tool calls", ErrBudgetExceeded, b.toolCalls)
+	}
+	return nil
+}
+
+// takeToolCalls returns the actions that fit in the tool call budget and
+// counts them.
+func (b *budget) takeToolCalls(actions []schema.AgentAction) []schema.AgentAction {
+	if b.maxToolCalls > 0 && len(actions) > b.maxToolCalls-b.toolCalls {
+		actions = actions[:b.maxToolCalls-b.toolCalls]
+	}
+	b.toolCalls += len(actions)
+	return actions
+}
+
+// AddTokenUsage adds the tokens used by an LLM call to the budget of the run of
+// the executor ctx belongs to. The agents of this package report the Usage of
+// the responses of their LLM. Other agents can call it to take part in
+// Executor.MaxTokens.
+func AddTokenUsage(ctx context.Context, tokens int) {
+	if b, ok := ctx.Value(budgetContextKey{}).(*budget); ok {
+		b.tokens.Add(int64(tokens))
+	}
+}
+
+// addResponseUsage reports the tokens used for a response.
+func addResponseUsage(ctx context.Context, resp *llms.ContentResponse) {
+	if resp == nil {
+		return
+	}
+	AddTokenUsage(ctx, resp.Usage.TotalTokens)
+}
+
+// usageReportingModel is an LLM reporting the tokens of its responses, for the
+// agents calling their LLM through a chain.
+type usageReportingModel struct {
+	llms.Model
+}
+
+func (m usageReportingModel) GenerateContent(
+	ctx context.Context,
+	messages []llms.MessageContent,
+	options ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	resp, err := m.Model.GenerateContent(ctx, messages, options...)
+	if err != nil {
+		return nil, err
+	}
+	addResponseUsage(ctx, resp)
+	return resp, nil
+}
+
+// generateChainFinalAnswer asks the chain of a text based agent for a final
+// answer. If the output cannot be parsed as a final answer the whole output is
+// the answer.
+func generateChainFinalAnswer(
+	ctx context.Context,
+	chain chains.Chain,
+	fullInputs map[string]any,
+	parse func(string) ([]schema.AgentAction, *schema.AgentFinish, error),
+	outputKey string,
+	stream func(context.Context, []byte) error,
+) (*schema.AgentFinish, error) {
+	scratchpad, _ := fullInputs[agentScratchpad].(string)
+	fullInputs[agentScratchpad] = scratchpad + _finalAnswerThought
+
+	output, err := chains.Predict(ctx, chain, fullInputs, chains.WithStreamingFunc(stream))
+	if err != nil {
+		return nil, err
+	}
+	if _, finish, err := parse(output); err == nil && finish != nil {
+		return finish, nil
+	}
+	return &schema.AgentFinish{
+		ReturnValues: map[string]any{outputKey: strings.TrimSpace(output)},
+		Log:          output,
+	}, nil
+}
+
+// generateMessagesFinalAnswer asks the LLM of a chat based agent for a final
+// answer, without offering it any tools.
+func generateMessagesFinalAnswer(
+	ctx context.Context,
+	llm llms.Model,
+	prompt prompts.FormatPrompter,
+	fullInputs map[string]any,
+	outputKey string,
+	stream func(context.Context, []byte) error,
+) (*schema.AgentFinish, error) {
+	value, err := prompt.FormatPrompt(fullInputs)
+	if err != nil {
+		return nil, err
+	}
+	messages := append(chatMessagesToContent(value.Messages()),
+		llms.TextParts(llms.ChatMessageTypeHuman, _finalAnswerMessage))
+
+	resp, err := llm.GenerateContent(ctx, messages, llms.WithStreamingFunc(stream))
+	if err != nil {
+		return nil, err
+	}
+	addResponseUsage(ctx, resp)
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("%w: no choices in response", ErrUnableToParseOutput)
+	}
+	content := resp.Choices[0].Content
+	return &schema.AgentFinish{
+		ReturnValues: map[string]any{outputKey: content},
+		Log:          content,
+	}, nil
+}
@@ -0,0 +1,132 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// upperCallResponse calls the upper tool twice and reports 10 tokens.
+func upperCallResponse() *llms.ContentResponse {
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			ToolCalls: []llms.ToolCall{
+				toolCall("call_1", "upper", `{"input": "a"}`),
+				toolCall("call_2", "upper", `{"input": "b"}`),
+			},
+		}},
+		Usage: llms.Usage{TotalTokens: 10},
+	}
+}
+
+func TestExecutorEarlyStopping(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		planned   int
+		wantCalls int
+		wantSteps int
+		wantErr   error
+	}{
+		{
+			name:      "force",
+			opts:      []Option{WithMaxIterations(2)},
+			planned:   2,
+			wantCalls: 2,
+			wantSteps: 4,
+			wantErr:   ErrNotFinished,
+		},
+		{
+			name:      "generate",
+			opts:      []Option{WithMaxIterations(2), WithEarlyStoppingMethod(EarlyStoppingGenerate)},
+			planned:   2,
+			wantCalls: 3,
+			wantSteps: 4,
+		},
+		{
+			name:      "max tokens",
+			opts:      []Option{WithMaxTokens(10), WithEarlyStoppingMethod(EarlyStoppingGenerate)},
+			planned:   1,
+			wantCalls: 2,
+			wantSteps: 2,
+		},
+		{
+			name:      "max tool calls",
+			opts:      []Option{WithMaxToolCalls(3), WithEarlyStoppingMethod(EarlyStoppingGenerate)},
+			planned:   2,
+			wantCalls: 3,
+			wantSteps: 3,
+		},
+		{
+			name:      "max tool calls force",
+			opts:      []Option{WithMaxToolCalls(2)},
+			planned:   1,
+			wantCalls: 1,
+			wantSteps: 2,
+			wantErr:   ErrBudgetExceeded,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			llm := &toolCallingLLM{}
+			for i := 0; i < tc.planned; i++ {
+				llm.responses = append(llm.responses, upperCallResponse())
+			}
+			llm.responses = append(llm.responses, &llms.ContentResponse{
+				Choices: []*llms.ContentChoice{{Content: "A and B"}},
+			})
+			executor := NewExecutor(NewToolCallingAgent(llm, []tools.Tool{upperTool{}}),
+				append(tc.opts, WithReturnIntermediateSteps())...)
+
+			outputs, err := chains.Call(context.Background(), executor, map[string]any{"input": "a and b"})
+			assert.Len(t, llm.calls, tc.wantCalls)
+			assert.Len(t, outputs[_intermediateStepsOutputKey], tc.wantSteps)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, ErrNotFinished)
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "A and B", outputs["output"])
+
+			last := llm.calls[len(llm.calls)-1]
+			assert.Equal(t, llms.TextParts(llms.ChatMessageTypeHuman, _finalAnswerMessage), last[len(last)-1])
+		})
+	}
+}
+
+func TestExecutorMaxTokensMRKLAgent(t *testing.T) {
+	t.Parallel()
+
+	llm := &toolCallingLLM{responses: []*llms.ContentResponse{
+		{
+			Choices: []*llms.ContentChoice{{Content: "Thought: I should upper-case it.\nAction: upper\nAction Input: a"}},
+			Usage:   llms.Usage{TotalTokens: 10},
+		},
+		{
+			Choices: []*llms.ContentChoice{{Content: "Final Answer: A"}},
+		},
+	}}
+	executor := NewExecutor(NewOneShotAgent(llm, []tools.Tool{upperTool{}}),
+		WithMaxTokens(10), WithEarlyStoppingMethod(EarlyStoppingGenerate), WithReturnIntermediateSteps())
+
+	outputs, err := chains.Call(context.Background(), executor, map[string]any{"input": "a"})
+	require.NoError(t, err)
+	assert.Len(t, llm.calls, 2)
+	assert.Len(t, outputs[_intermediateStepsOutputKey], 1)
+	assert.Equal(t, "A", strings.TrimSpace(outputs["output"].(string)))
+
+	last := llm.calls[len(llm.calls)-1]
+	assert.Contains(t, last[0].Parts[0].(llms.TextContent).Text, _finalAnswerThought)
+}
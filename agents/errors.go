@@ -1,6 +1,11 @@
 package agents
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/schema"
+)
 
 var (
 	// ErrExecutorInputNotString is returned if an input to the executor call function is not a string.
@@ -20,6 +25,15 @@ var (
 	// ErrInvalidChainReturnType is returned if the internal chain of the agent returns a value in the
 	// "text" filed that is not a string.
 	ErrInvalidChainReturnType = errors.New("agent chain did not return a string")
+	// ErrBudgetExceeded is returned, wrapped in ErrNotFinished, if the executor ran out of time,
+	// tokens or tool calls before the agent finished.
+	ErrBudgetExceeded = errors.New("agent budget exceeded")
+	// ErrToolDisabled is given to the formatter of a ToolErrorHandler for calls of a tool that
+	// failed too often.
+	ErrToolDisabled = errors.New("tool disabled after repeated failures")
+	// ErrActionDenied is returned by a Guardrail to deny an action. The error is given to the agent as
+	// observation instead of ending the run.
+	ErrActionDenied = errors.New("action denied")
 )
 
 // ParserErrorHandler is the struct used to handle parse errors from the agent in the executor. If
@@ -38,3 +52,44 @@ func NewParserErrorHandler(formatFunc func(string) string) *ParserErrorHandler {
 		Formatter: formatFunc,
 	}
 }
+
+// ToolErrorHandler is the struct used to handle errors of tools in the executor. If an executor
+// have a ToolErrorHandler, failing tool calls are retried and their last error is formatted using
+// the formatter function and added as an observation, instead of ending the run. In the next
+// executor step the agent can then react to the error.
+type ToolErrorHandler struct {
+	// The formatter function can be used to format the error of a tool call. If nil the observation
+	// is the name of the tool followed by the error.
+	Formatter func(action schema.AgentAction, err error) string
+	// MaxRetries is the number of times a failing tool call is retried before its error is given
+	// as observation.
+	MaxRetries int
+	// ToolRetries overrides MaxRetries for the tools with the given names.
+	ToolRetries map[string]int
+	// MaxFailures is the number of failed calls in a row after which a tool is disabled for the
+	// rest of the run. Calls of a disabled tool are not run and get ErrToolDisabled as error. If
+	// zero tools are never disabled.
+	MaxFailures int
+}
+
+// NewToolErrorHandler creates a new tool error handler. Tool calls are not retried and tools are
+// never disabled unless the fields of the handler are set.
+func NewToolErrorHandler(formatFunc func(schema.AgentAction, error) string) *ToolErrorHandler {
+	return &ToolErrorHandler{
+		Formatter: formatFunc,
+	}
+}
+
+func (h *ToolErrorHandler) retries(tool string) int {
+	if retries, ok := h.ToolRetries[tool]; ok {
+		return retries
+	}
+	return h.MaxRetries
+}
+
+func (h *ToolErrorHandler) format(action schema.AgentAction, err error) string {
+	if h.Formatter != nil {
+		return h.Formatter(action, err)
+	}
+	return fmt.Sprintf("%s failed: %s", action.Tool, err)
+}
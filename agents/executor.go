@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/chains"
@@ -21,9 +22,31 @@ type Executor struct {
 	Memory           schema.Memory
 	CallbacksHandler callbacks.Handler
 	ErrorHandler     *ParserErrorHandler
+	// ToolErrorHandler turns the errors of tools into observations. If nil the
+	// first error of a tool ends the run.
+	ToolErrorHandler *ToolErrorHandler
+	// Guardrails check every action before its tool is called, in order. See
+	// Guardrail and ContextWithAllowedTools.
+	Guardrails []Guardrail
 
 	MaxIterations           int
 	ReturnIntermediateSteps bool
+	// MaxConcurrency is the number of actions of one plan that run at the same
+	// time. The default is 1: the actions run one at a time, in the order of the
+	// plan. Raise it only when the tools are safe for concurrent use.
+	MaxConcurrency int
+
+	// EarlyStoppingMethod tells what happens when the run reaches MaxIterations
+	// or one of the budgets below before the agent finished.
+	EarlyStoppingMethod EarlyStoppingMethod
+	// MaxDuration, MaxTokens and MaxToolCalls limit the wall time, the tokens
+	// used by the LLM and the number of tool calls of a run. They are checked
+	// before every iteration, and actions beyond MaxToolCalls are not run. Zero
+	// means no limit. Tokens are counted as reported with AddTokenUsage, which
+	// the agents of this package do with the Usage of the LLM responses.
+	MaxDuration  time.Duration
+	MaxTokens    int
+	MaxToolCalls int
 }
 
 var (
@@ -42,9 +65,16 @@ func NewExecutor(agent Agent, opts ...Option) *Executor {
 		Agent:                   agent,
 		Memory:                  options.memory,
 		MaxIterations:           options.maxIterations,
+		MaxConcurrency:          options.maxConcurrency,
 		ReturnIntermediateSteps: options.returnIntermediateSteps,
 		CallbacksHandler:        options.callbacksHandler,
 		ErrorHandler:            options.errorHandler,
+		ToolErrorHandler:        options.toolErrorHandler,
+		Guardrails:              options.guardrails,
+		EarlyStoppingMethod:     options.earlyStoppingMethod,
+		MaxDuration:             options.maxDuration,
+		MaxTokens:               options.maxTokens,
+		MaxToolCalls:            options.maxToolCalls,
 	}
 }
 
@@ -53,82 +83,68 @@ func (e *Executor) Call(ctx context.Context, inputValues map[string]any, _ ...ch
 	if err != nil {
 		return nil, err
 	}
-	nameToTool := getNameToTool(e.Agent.GetTools())
+	tb := &toolbox{nameToTool: getNameToTool(e.Agent.GetTools())}
+	b := newBudget(e)
+	ctx = context.WithValue(ctx, budgetContextKey{}, b)
 
+	em := emitterFromContext(ctx)
 	steps := make([]schema.AgentStep, 0)
-	if concurrentAgent, ok := e.Agent.(ConcurrentAgent); ok {
-		return e.callConcurrent(ctx, concurrentAgent, steps, nameToTool, inputs)
-	}
-
+	stopErr := ErrNotFinished
 	for i := 0; i < e.MaxIterations; i++ {
+		if err := b.exceeded(); err != nil {
+			stopErr = fmt.Errorf("%w: %w", ErrNotFinished, err)
+			break
+		}
+		em.setIteration(i + 1)
 		var finish map[string]any
-		steps, finish, err = e.doIteration(ctx, steps, nameToTool, inputs)
+		steps, finish, err = e.doIteration(ctx, steps, tb, inputs)
 		if finish != nil || err != nil {
 			return finish, err
 		}
 	}
 
-	if e.CallbacksHandler != nil {
-		e.CallbacksHandler.HandleAgentFinish(ctx, schema.AgentFinish{
-			ReturnValues: map[string]any{"output": ErrNotFinished.Error()},
-		})
-	}
-	return e.getReturn(
-		&schema.AgentFinish{ReturnValues: make(map[string]any)},
-		steps,
-	), ErrNotFinished
+	return e.stopEarly(ctx, steps, inputs, stopErr)
 }
 
-func (e *Executor) callConcurrent(
+// stopEarly ends a run that ran out of iterations or budget, as told by the
+// EarlyStoppingMethod.
+func (e *Executor) stopEarly(
 	ctx context.Context,
-	concurrentAgent ConcurrentAgent,
 	steps []schema.AgentStep,
-	nameToTool map[string]tools.Tool,
 	inputs map[string]string,
+	stopErr error,
 ) (map[string]any, error) {
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var finalResult map[string]any
-	var finalError error
-
-	actions, finish, err := concurrentAgent.Plan(ctx, steps, inputs)
-	if err != nil {
-		return nil, err
-	}
-	if finish != nil {
+	if agent, ok := e.Agent.(FinalAnswerAgent); ok && e.EarlyStoppingMethod == EarlyStoppingGenerate {
+		finish, err := agent.FinalAnswer(ctx, steps, inputs)
+		if err != nil {
+			return nil, err
+		}
+		if e.CallbacksHandler != nil {
+			e.CallbacksHandler.HandleAgentFinish(ctx, *finish)
+		}
+		emitterFromContext(ctx).emit(Event{Type: EventFinalAnswer, Finish: finish})
 		return e.getReturn(finish, steps), nil
 	}
 
-	concurrentAgent.InitializeConcurrentActions(actions)
-	concurrentAgent.ExecuteConcurrentActions()
-
-	for _, action := range actions {
-		wg.Add(1)
-		go func(action schema.AgentAction) {
-			defer wg.Done()
-			steps, err := e.doAction(ctx, steps, nameToTool, action)
-			if err != nil {
-				mu.Lock()
-				finalError = err
-				mu.Unlock()
-				return
-			}
-			mu.Lock()
-			finalResult = e.getReturn(&schema.AgentFinish{ReturnValues: make(map[string]any)}, steps)
-			mu.Unlock()
-		}(action)
+	if e.CallbacksHandler != nil {
+		e.CallbacksHandler.HandleAgentFinish(ctx, schema.AgentFinish{
+			ReturnValues: map[string]any{"output": stopErr.Error()},
+		})
 	}
-
-	wg.Wait()
-	return finalResult, finalError
+	return e.getReturn(
+		&schema.AgentFinish{ReturnValues: make(map[string]any)},
+		steps,
+	), stopErr
 }
 
 func (e *Executor) doIteration( // nolint
 	ctx context.Context,
 	steps []schema.AgentStep,
-	nameToTool map[string]tools.Tool,
+	tb *toolbox,
 	inputs map[string]string,
 ) ([]schema.AgentStep, map[string]any, error) {
+	em := emitterFromContext(ctx)
+	em.emit(Event{Type: EventPlanStart})
 	actions, finish, err := e.Agent.Plan(ctx, steps, inputs)
 	if errors.Is(err, ErrUnableToParseOutput) && e.ErrorHandler != nil {
 		formattedObservation := err.Error()
@@ -152,46 +168,154 @@ func (e *Executor) doIteration( // nolint
 		if e.CallbacksHandler != nil {
 			e.CallbacksHandler.HandleAgentFinish(ctx, *finish)
 		}
+		em.emit(Event{Type: EventFinalAnswer, Finish: finish})
 		return steps, e.getReturn(finish, steps), nil
 	}
 
-	for _, action := range actions {
-		steps, err = e.doAction(ctx, steps, nameToTool, action)
-		if err != nil {
-			return steps, nil, err
+	if b, ok := ctx.Value(budgetContextKey{}).(*budget); ok {
+		actions = b.takeToolCalls(actions)
+	}
+	actionSteps, err := e.doActions(ctx, tb, actions)
+	return append(steps, actionSteps...), nil, err
+}
+
+// doActions runs the actions of one plan, up to MaxConcurrency at a time. The
+// steps are returned in the order of the actions. If some of the actions fail
+// the steps of the others are returned along with the joined errors.
+func (e *Executor) doActions(
+	ctx context.Context,
+	tb *toolbox,
+	actions []schema.AgentAction,
+) ([]schema.AgentStep, error) {
+	em := emitterFromContext(ctx)
+	for i, action := range actions {
+		if e.CallbacksHandler != nil {
+			e.CallbacksHandler.HandleAgentAction(ctx, action)
+		}
+		em.emit(Event{Type: EventAction, Action: &actions[i]})
+	}
+
+	steps := make([]schema.AgentStep, len(actions))
+	errs := make([]error, len(actions))
+	do := func(i int) {
+		steps[i], errs[i] = e.doAction(ctx, tb, actions[i])
+		if errs[i] == nil {
+			em.emit(Event{Type: EventToolOutput, Action: &actions[i], Observation: steps[i].Observation})
 		}
 	}
+	if e.MaxConcurrency <= 1 || len(actions) == 1 {
+		for i := range actions {
+			do(i)
+		}
+	} else {
+		sem := make(chan struct{}, e.MaxConcurrency)
+		var wg sync.WaitGroup
+		for i := range actions {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+					errs[i] = ctx.Err()
+					return
+				}
+				do(i)
+			}(i)
+		}
+		wg.Wait()
+	}
 
-	return steps, nil, nil
+	finished := make([]schema.AgentStep, 0, len(actions))
+	for i, err := range errs {
+		if err != nil {
+			errs[i] = fmt.Errorf("tool %s: %w", actions[i].Tool, err)
+			continue
+		}
+		finished = append(finished, steps[i])
+	}
+	return finished, errors.Join(errs...)
 }
 
 func (e *Executor) doAction(
 	ctx context.Context,
-	steps []schema.AgentStep,
-	nameToTool map[string]tools.Tool,
+	tb *toolbox,
 	action schema.AgentAction,
-) ([]schema.AgentStep, error) {
-	if e.CallbacksHandler != nil {
-		e.CallbacksHandler.HandleAgentAction(ctx, action)
+) (schema.AgentStep, error) {
+	// Denied actions are reported back to the agent so that it can do
+	// something else.
+	action, err := checkGuardrails(ctx, e.Guardrails, action)
+	if errors.Is(err, ErrActionDenied) {
+		return schema.AgentStep{
+			Action:      action,
+			Observation: err.Error(),
+		}, nil
+	}
+	if err != nil {
+		return schema.AgentStep{}, err
 	}
 
-	tool, ok := nameToTool[strings.ToUpper(action.Tool)]
+	tool, ok := tb.nameToTool[strings.ToUpper(action.Tool)]
 	if !ok {
-		return append(steps, schema.AgentStep{
+		return schema.AgentStep{
 			Action:      action,
 			Observation: fmt.Sprintf("%s is not a valid tool, try another one", action.Tool),
-		}), nil
+		}, nil
 	}
 
-	observation, err := tool.Call(ctx, action.ToolInput)
+	// Invalid arguments are reported back to the agent so that it can fix them.
+	if err := tools.Validate(tool, action.ToolInput); err != nil {
+		return schema.AgentStep{
+			Action:      action,
+			Observation: err.Error(),
+		}, nil
+	}
+
+	observation, err := e.callTool(ctx, tb, tool, action)
 	if err != nil {
-		return nil, err
+		return schema.AgentStep{}, err
 	}
 
-	return append(steps, schema.AgentStep{
+	return schema.AgentStep{
 		Action:      action,
 		Observation: observation,
-	}), nil
+	}, nil
+}
+
+// callTool calls the tool of an action. With a ToolErrorHandler failing calls
+// are retried and the last error is returned as observation, unless the run
+// was canceled.
+func (e *Executor) callTool(
+	ctx context.Context,
+	tb *toolbox,
+	tool tools.Tool,
+	action schema.AgentAction,
+) (string, error) {
+	h := e.ToolErrorHandler
+	if h == nil {
+		return tool.Call(ctx, action.ToolInput)
+	}
+
+	name := tool.Name()
+	if failures := tb.failures(name); h.MaxFailures > 0 && failures >= h.MaxFailures {
+		return h.format(action, fmt.Errorf("%w: %s failed %d times in a row", ErrToolDisabled, name, failures)), nil
+	}
+
+	var err error
+	for attempt := 0; attempt <= h.retries(name); attempt++ {
+		var observation string
+		observation, err = tool.Call(ctx, action.ToolInput)
+		if err == nil {
+			tb.recordFailure(name, false)
+			return observation, nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+	}
+	tb.recordFailure(name, true)
+	return h.format(action, err), nil
 }
 
 func (e *Executor) getReturn(finish *schema.AgentFinish, steps []schema.AgentStep) map[string]any {
@@ -235,6 +359,34 @@ func inputsToString(inputValues map[string]any) (map[string]string, error) {
 	return inputs, nil
 }
 
+// toolbox holds the tools of one run of an executor by their upper-cased name,
+// and counts the failed calls in a row of every tool.
+type toolbox struct {
+	nameToTool map[string]tools.Tool
+
+	mu          sync.Mutex
+	failedCalls map[string]int
+}
+
+func (tb *toolbox) failures(tool string) int {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	return tb.failedCalls[tool]
+}
+
+func (tb *toolbox) recordFailure(tool string, failed bool) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	if !failed {
+		delete(tb.failedCalls, tool)
+		return
+	}
+	if tb.failedCalls == nil {
+		tb.failedCalls = make(map[string]int)
+	}
+	tb.failedCalls[tool]++
+}
+
 func getNameToTool(t []tools.Tool) map[string]tools.Tool {
 	if len(t) == 0 {
 		return nil
@@ -2,9 +2,12 @@ package agents_test
 
 import (
 	"context"
+	"errors"
 	"os"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/tmc/langchaingo/agents"
@@ -141,3 +144,188 @@ func TestExecutorWithOpenAIFunctionAgent(t *testing.T) {
 	require.True(t, strings.Contains(result, "47") || strings.Contains(result, "49"),
 		"correct answer 47 or 49 not in response")
 }
+
+// plannedAgent returns the actions of the first plan, then finishes.
+type plannedAgent struct {
+	testAgent
+	tools []tools.Tool
+}
+
+func (a *plannedAgent) Plan(
+	_ context.Context,
+	intermediateSteps []schema.AgentStep,
+	_ map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	if len(intermediateSteps) == 0 {
+		return a.actions, nil, nil
+	}
+	return nil, &schema.AgentFinish{ReturnValues: map[string]any{"output": "done"}}, nil
+}
+
+func (a *plannedAgent) GetTools() []tools.Tool {
+	return a.tools
+}
+
+// sleepTool records how many of its calls run at the same time.
+type sleepTool struct {
+	running, maxRunning *atomic.Int32
+}
+
+func (sleepTool) Name() string        { return "sleep" }
+func (sleepTool) Description() string { return "Sleeps." }
+func (t sleepTool) Call(_ context.Context, input string) (string, error) {
+	running := t.running.Add(1)
+	defer t.running.Add(-1)
+	for {
+		maxRunning := t.maxRunning.Load()
+		if running <= maxRunning || t.maxRunning.CompareAndSwap(maxRunning, running) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if input == "fail" {
+		return "", errors.New("failed")
+	}
+	return "slept " + input, nil
+}
+
+func TestExecutorConcurrentActions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		maxConcurrency int
+		wantMax        int32
+	}{
+		{name: "default", wantMax: 1},
+		{name: "sequential", maxConcurrency: 1, wantMax: 1},
+		{name: "bounded", maxConcurrency: 2, wantMax: 2},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tool := sleepTool{running: &atomic.Int32{}, maxRunning: &atomic.Int32{}}
+			a := &plannedAgent{tools: []tools.Tool{tool}}
+			for _, input := range []string{"1", "2", "3", "4", "5"} {
+				a.actions = append(a.actions, schema.AgentAction{Tool: "sleep", ToolInput: input})
+			}
+			opts := []agents.Option{agents.WithReturnIntermediateSteps()}
+			if tc.maxConcurrency > 0 {
+				opts = append(opts, agents.WithMaxConcurrency(tc.maxConcurrency))
+			}
+			executor := agents.NewExecutor(a, opts...)
+
+			outputs, err := chains.Call(context.Background(), executor, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantMax, tool.maxRunning.Load())
+
+			steps, ok := outputs["intermediateSteps"].([]schema.AgentStep)
+			require.True(t, ok)
+			observations := make([]string, len(steps))
+			for i, step := range steps {
+				observations[i] = step.Observation
+			}
+			require.Equal(t, []string{"slept 1", "slept 2", "slept 3", "slept 4", "slept 5"}, observations)
+		})
+	}
+}
+
+func TestExecutorConcurrentActionErrors(t *testing.T) {
+	t.Parallel()
+
+	tool := sleepTool{running: &atomic.Int32{}, maxRunning: &atomic.Int32{}}
+	a := &plannedAgent{tools: []tools.Tool{tool}, testAgent: testAgent{actions: []schema.AgentAction{
+		{Tool: "sleep", ToolInput: "fail"},
+		{Tool: "sleep", ToolInput: "1"},
+		{Tool: "sleep", ToolInput: "fail"},
+	}}}
+
+	_, err := chains.Call(context.Background(), agents.NewExecutor(a), nil)
+	require.EqualError(t, err, "tool sleep: failed\ntool sleep: failed")
+}
+
+// flakyTool fails its first calls.
+type flakyTool struct {
+	failures int
+	calls    *atomic.Int32
+}
+
+func (flakyTool) Name() string        { return "flaky" }
+func (flakyTool) Description() string { return "Fails at first." }
+func (t flakyTool) Call(_ context.Context, _ string) (string, error) {
+	if int(t.calls.Add(1)) <= t.failures {
+		return "", errors.New("unavailable")
+	}
+	return "ok", nil
+}
+
+func TestExecutorWithToolErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	actions := []schema.AgentAction{{Tool: "flaky"}, {Tool: "flaky"}, {Tool: "flaky"}}
+	tests := []struct {
+		name             string
+		handler          *agents.ToolErrorHandler
+		failures         int
+		wantObservations []string
+		wantCalls        int32
+	}{
+		{
+			name:             "observation",
+			handler:          agents.NewToolErrorHandler(nil),
+			failures:         1,
+			wantObservations: []string{"flaky failed: unavailable", "ok", "ok"},
+			wantCalls:        3,
+		},
+		{
+			name:             "retries",
+			handler:          &agents.ToolErrorHandler{ToolRetries: map[string]int{"flaky": 2}},
+			failures:         2,
+			wantObservations: []string{"ok", "ok", "ok"},
+			wantCalls:        5,
+		},
+		{
+			name: "circuit breaker",
+			handler: &agents.ToolErrorHandler{
+				MaxFailures: 2,
+				Formatter: func(_ schema.AgentAction, err error) string {
+					if errors.Is(err, agents.ErrToolDisabled) {
+						return "disabled"
+					}
+					return "failed"
+				},
+			},
+			failures:         3,
+			wantObservations: []string{"failed", "failed", "disabled"},
+			wantCalls:        2,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tool := flakyTool{failures: tc.failures, calls: &atomic.Int32{}}
+			a := &plannedAgent{tools: []tools.Tool{tool}, testAgent: testAgent{actions: actions}}
+			executor := agents.NewExecutor(a,
+				agents.WithMaxConcurrency(1),
+				agents.WithToolErrorHandler(tc.handler),
+				agents.WithReturnIntermediateSteps(),
+			)
+
+			outputs, err := chains.Call(context.Background(), executor, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantCalls, tool.calls.Load())
+
+			steps, ok := outputs["intermediateSteps"].([]schema.AgentStep)
+			require.True(t, ok)
+			observations := make([]string, len(steps))
+			for i, step := range steps {
+				observations[i] = step.Observation
+			}
+			require.Equal(t, tc.wantObservations, observations)
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Guardrail checks the actions of an agent before the executor calls their
+// tool. It returns the action to run, which it can rewrite but not turn into
+// an action of another tool, or an error wrapping ErrActionDenied to deny it. A denied action is not run and the
+// error is given to the agent as observation. Any other error ends the run.
+//
+// With Executor.MaxConcurrency above 1 the actions of one plan are checked
+// concurrently, so guardrails must be safe for concurrent use.
+type Guardrail interface {
+	Check(ctx context.Context, action schema.AgentAction) (schema.AgentAction, error)
+}
+
+// GuardrailFunc is an adapter to use a function as a Guardrail.
+type GuardrailFunc func(ctx context.Context, action schema.AgentAction) (schema.AgentAction, error)
+
+// Check calls f.
+func (f GuardrailFunc) Check(ctx context.Context, action schema.AgentAction) (schema.AgentAction, error) {
+	return f(ctx, action)
+}
+
+// checkGuardrails runs an action through the tool lists of ctx and then
+// through the guardrails in order. An action whose tool is changed by a
+// guardrail is denied, so that it cannot escape the tool lists and the
+// guardrails that ran before.
+func checkGuardrails(
+	ctx context.Context,
+	guardrails []Guardrail,
+	action schema.AgentAction,
+) (schema.AgentAction, error) {
+	if lists, ok := ctx.Value(toolListsContextKey{}).(toolLists); ok {
+		if err := lists.check(action.Tool); err != nil {
+			return action, err
+		}
+	}
+	tool := action.Tool
+	for _, g := range guardrails {
+		var err error
+		if action, err = g.Check(ctx, action); err != nil {
+			return action, err
+		}
+		if !strings.EqualFold(action.Tool, tool) {
+			return action, fmt.Errorf("%w: a guardrail changed the tool of %s to %s", ErrActionDenied, tool, action.Tool)
+		}
+	}
+	return action, nil
+}
+
+type toolListsContextKey struct{}
+
+// toolLists are the tools allowed and denied for one request.
+type toolLists struct {
+	allowed []string
+	denied  []string
+}
+
+func (l toolLists) check(tool string) error {
+	if l.allowed != nil && !containsFold(l.allowed, tool) {
+		return fmt.Errorf("%w: %s is not allowed for this request", ErrActionDenied, tool)
+	}
+	if containsFold(l.denied, tool) {
+		return fmt.Errorf("%w: %s is denied for this request", ErrActionDenied, tool)
+	}
+	return nil
+}
+
+// ContextWithAllowedTools returns a context for a run of an executor in which
+// only the given tools can be called. Calling it again narrows the tools down.
+func ContextWithAllowedTools(ctx context.Context, tools ...string) context.Context {
+	lists, _ := ctx.Value(toolListsContextKey{}).(toolLists)
+	allowed := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		if lists.allowed == nil || containsFold(lists.allowed, tool) {
+			allowed = append(allowed, tool)
+		}
+	}
+	lists.allowed = allowed
+	return context.WithValue(ctx, toolListsContextKey{}, lists)
+}
+
+// ContextWithDeniedTools returns a context for a run of an executor in which
+// the given tools cannot be called, on top of those already denied.
+func ContextWithDeniedTools(ctx context.Context, tools ...string) context.Context {
+	lists, _ := ctx.Value(toolListsContextKey{}).(toolLists)
+	lists.denied = append(append([]string(nil), lists.denied...), tools...)
+	return context.WithValue(ctx, toolListsContextKey{}, lists)
+}
+
+// AllowTools returns a guardrail denying the actions of all tools but the
+// given ones.
+func AllowTools(tools ...string) Guardrail { //nolint:ireturn
+	lists := toolLists{allowed: append([]string{}, tools...)}
+	return GuardrailFunc(func(_ context.Context, action schema.AgentAction) (schema.AgentAction, error) {
+		return action, lists.check(action.Tool)
+	})
+}
+
+// DenyTools returns a guardrail denying the actions of the given tools.
+func DenyTools(tools ...string) Guardrail { //nolint:ireturn
+	lists := toolLists{denied: tools}
+	return GuardrailFunc(func(_ context.Context, action schema.AgentAction) (schema.AgentAction, error) {
+		return action, lists.check(action.Tool)
+	})
+}
+
+// ValidateInput returns a guardrail checking the input of the actions of a
+// tool with a validator, such as ReadOnlySQL or AllowedDomains. Actions with
+// an invalid input are denied with the error of the validator.
+func ValidateInput(tool string, validate func(input string) error) Guardrail { //nolint:ireturn
+	return GuardrailFunc(func(_ context.Context, action schema.AgentAction) (schema.AgentAction, error) {
+		if !strings.EqualFold(action.Tool, tool) {
+			return action, nil
+		}
+		if err := validate(action.ToolInput); err != nil {
+			return action, fmt.Errorf("%w: invalid input for %s: %w", ErrActionDenied, action.Tool, err)
+		}
+		return action, nil
+	})
+}
+
+var (
+	_sqlCommentRegex = regexp.MustCompile(`(?s)--[^\n]*|/\*.*?\*/`)
+	_sqlStringRegex  = regexp.MustCompile(`'(?:[^']|'')*'|"(?:[^"]|"")*"`)
+	_sqlWordRegex    = regexp.MustCompile(`[A-Za-z_]+`)
+
+	_sqlReadOnlyStatements = []string{"SELECT", "WITH", "EXPLAIN", "SHOW", "DESCRIBE", "DESC", "VALUES"}
+	_sqlWriteKeywords      = []string{
+		"INSERT", "UPDATE", "DELETE", "MERGE", "UPSERT", "CREATE", "ALTER", "DROP", "TRUNCATE",
+		"GRANT", "REVOKE", "INTO",
+	}
+)
+
+// ReadOnlySQL validates that an input only holds SQL statements that read
+// data, such as SELECT, and no keyword that writes data or changes the
+// database. It is meant as a safety net for tools running queries written by
+// the LLM, and not as a replacement for read-only database credentials.
+func ReadOnlySQL(input string) error {
+	query := _sqlStringRegex.ReplaceAllString(_sqlCommentRegex.ReplaceAllString(input, " "), "''")
+
+	statements := 0
+	for _, statement := range strings.Split(query, ";") {
+		words := _sqlWordRegex.FindAllString(statement, -1)
+		if len(words) == 0 {
+			continue
+		}
+		statements++
+		if !containsFold(_sqlReadOnlyStatements, words[0]) {
+			return fmt.Errorf("%s statements are not allowed", strings.ToUpper(words[0]))
+		}
+		for _, word := range words[1:] {
+			if containsFold(_sqlWriteKeywords, word) {
+				return fmt.Errorf("%s is not allowed", strings.ToUpper(word))
+			}
+		}
+	}
+	if statements == 0 {
+		return errors.New("no SQL statement")
+	}
+	return nil
+}
+
+var _urlRegex = regexp.MustCompile(`(?i)\bhttps?://[^\s"'<>]+`)
+
+// AllowedDomains returns a validator accepting inputs whose URLs all point
+// to one of the domains or their subdomains. Inputs without a URL are
+// rejected.
+func AllowedDomains(domains ...string) func(input string) error {
+	return func(input string) error {
+		urls := _urlRegex.FindAllString(input, -1)
+		if len(urls) == 0 {
+			return errors.New("no URL in input")
+		}
+		for _, rawURL := range urls {
+			u, err := url.Parse(rawURL)
+			if err != nil {
+				return err
+			}
+			if !domainAllowed(u.Hostname(), domains) {
+				return fmt.Errorf("domain %s is not allowed", u.Hostname())
+			}
+		}
+		return nil
+	}
+}
+
+func domainAllowed(host string, domains []string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.ToLower(domain), ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimit returns a guardrail denying the actions of a tool beyond the given
+// number of calls in a sliding window. The limit is shared by all the runs of
+// the executors using the guardrail.
+func RateLimit(tool string, calls int, per time.Duration) Guardrail { //nolint:ireturn
+	var (
+		mu    sync.Mutex
+		times []time.Time
+	)
+	return GuardrailFunc(func(_ context.Context, action schema.AgentAction) (schema.AgentAction, error) {
+		if !strings.EqualFold(action.Tool, tool) {
+			return action, nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := time.Now()
+		kept := times[:0]
+		for _, t := range times {
+			if now.Sub(t) < per {
+				kept = append(kept, t)
+			}
+		}
+		times = kept
+		if len(times) >= calls {
+			return action, fmt.Errorf("%w: %s was called %d times in the last %s, try again later",
+				ErrActionDenied, action.Tool, len(times), per)
+		}
+		times = append(times, now)
+		return action, nil
+	})
+}
+
+// ConfirmFunc is asked to confirm an action before it runs. It returns the
+// action to run, which it can rewrite but not give another tool, and whether
+// the action is approved.
+type ConfirmFunc func(ctx context.Context, action schema.AgentAction) (schema.AgentAction, bool, error)
+
+// Confirm returns a guardrail asking confirm for every action, for instance
+// to have a human approve them. The calls of confirm are serialized, even for
+// actions running concurrently. Rejected actions are denied, and an error of
+// confirm ends the run.
+func Confirm(confirm ConfirmFunc) Guardrail { //nolint:ireturn
+	var mu sync.Mutex
+	return GuardrailFunc(func(ctx context.Context, action schema.AgentAction) (schema.AgentAction, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		confirmed, ok, err := confirm(ctx, action)
+		if err != nil {
+			return action, err
+		}
+		if !ok {
+			return action, fmt.Errorf("%w: %s was rejected", ErrActionDenied, action.Tool)
+		}
+		return confirmed, nil
+	})
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
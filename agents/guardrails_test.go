@@ -0,0 +1,199 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// echoTool returns its name followed by its input.
+type echoTool struct {
+	name string
+}
+
+func (t echoTool) Name() string        { return t.name }
+func (t echoTool) Description() string { return "Echoes the input." }
+func (t echoTool) Call(_ context.Context, input string) (string, error) {
+	return t.name + ": " + input, nil
+}
+
+func runGuarded(
+	ctx context.Context,
+	t *testing.T,
+	actions []schema.AgentAction,
+	guardrails ...agents.Guardrail,
+) ([]string, error) {
+	t.Helper()
+
+	a := &plannedAgent{
+		tools:     []tools.Tool{echoTool{name: "sql"}, echoTool{name: "scrape"}, echoTool{name: "shell"}},
+		testAgent: testAgent{actions: actions},
+	}
+	executor := agents.NewExecutor(a, agents.WithGuardrails(guardrails...), agents.WithReturnIntermediateSteps())
+	outputs, err := chains.Call(ctx, executor, nil)
+	if err != nil {
+		return nil, err
+	}
+	steps, ok := outputs["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	observations := make([]string, len(steps))
+	for i, step := range steps {
+		observations[i] = step.Observation
+	}
+	return observations, nil
+}
+
+func TestExecutorGuardrails(t *testing.T) {
+	t.Parallel()
+
+	confirm := agents.Confirm(func(_ context.Context, action schema.AgentAction) (schema.AgentAction, bool, error) {
+		if action.Tool == "sql" {
+			action.ToolInput += " LIMIT 10"
+		}
+		return action, action.ToolInput != "rm -rf /", nil
+	})
+	observations, err := runGuarded(context.Background(), t, []schema.AgentAction{
+		{Tool: "sql", ToolInput: "DROP TABLE users"},
+		{Tool: "sql", ToolInput: "SELECT name FROM users"},
+		{Tool: "scrape", ToolInput: "https://evil.example.org/"},
+		{Tool: "scrape", ToolInput: "https://docs.example.com/page"},
+		{Tool: "shell", ToolInput: "rm -rf /"},
+		{Tool: "shell", ToolInput: "ls"},
+	},
+		agents.ValidateInput("sql", agents.ReadOnlySQL),
+		agents.ValidateInput("scrape", agents.AllowedDomains("example.com")),
+		confirm,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"action denied: invalid input for sql: DROP statements are not allowed",
+		"sql: SELECT name FROM users LIMIT 10",
+		"action denied: invalid input for scrape: domain evil.example.org is not allowed",
+		"scrape: https://docs.example.com/page",
+		"action denied: shell was rejected",
+		"shell: ls",
+	}, observations)
+}
+
+func TestExecutorGuardrailToolLists(t *testing.T) {
+	t.Parallel()
+
+	actions := []schema.AgentAction{{Tool: "sql"}, {Tool: "scrape"}, {Tool: "shell"}}
+
+	observations, err := runGuarded(context.Background(), t, actions, agents.DenyTools("shell"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sql: ", "scrape: ", "action denied: shell is denied for this request"}, observations)
+
+	ctx := agents.ContextWithAllowedTools(context.Background(), "sql", "scrape")
+	ctx = agents.ContextWithAllowedTools(ctx, "SQL", "shell")
+	observations, err = runGuarded(ctx, t, actions)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"sql: ",
+		"action denied: scrape is not allowed for this request",
+		"action denied: shell is not allowed for this request",
+	}, observations)
+
+	ctx = agents.ContextWithDeniedTools(context.Background(), "sql")
+	observations, err = runGuarded(ctx, t, actions, agents.AllowTools("sql", "scrape"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"action denied: sql is denied for this request",
+		"scrape: ",
+		"action denied: shell is not allowed for this request",
+	}, observations)
+}
+
+func TestExecutorGuardrailChangedTool(t *testing.T) {
+	t.Parallel()
+
+	confirm := agents.Confirm(func(_ context.Context, action schema.AgentAction) (schema.AgentAction, bool, error) {
+		if action.Tool == "sql" {
+			action.Tool = "shell"
+		}
+		return action, true, nil
+	})
+	actions := []schema.AgentAction{{Tool: "sql"}, {Tool: "scrape"}}
+
+	ctx := agents.ContextWithDeniedTools(context.Background(), "shell")
+	observations, err := runGuarded(ctx, t, actions, confirm)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"action denied: a guardrail changed the tool of sql to shell", "scrape: "}, observations)
+
+	observations, err = runGuarded(context.Background(), t, actions, agents.AllowTools("sql", "scrape"), confirm)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"action denied: a guardrail changed the tool of sql to shell", "scrape: "}, observations)
+}
+
+func TestExecutorGuardrailRateLimit(t *testing.T) {
+	t.Parallel()
+
+	limit := agents.RateLimit("sql", 2, time.Hour)
+	actions := []schema.AgentAction{{Tool: "sql", ToolInput: "1"}, {Tool: "shell"}, {Tool: "sql", ToolInput: "2"}}
+	observations, err := runGuarded(context.Background(), t, actions, limit)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sql: 1", "shell: ", "sql: 2"}, observations)
+
+	// The limit is shared between runs.
+	observations, err = runGuarded(context.Background(), t, actions[:1], limit)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"action denied: sql was called 2 times in the last 1h0m0s, try again later"}, observations)
+}
+
+func TestExecutorGuardrailError(t *testing.T) {
+	t.Parallel()
+
+	errConfirm := errors.New("no one to ask")
+	confirm := agents.Confirm(func(_ context.Context, action schema.AgentAction) (schema.AgentAction, bool, error) {
+		return action, false, errConfirm
+	})
+	_, err := runGuarded(context.Background(), t, []schema.AgentAction{{Tool: "sql"}}, confirm)
+	require.ErrorIs(t, err, errConfirm)
+}
+
+func TestReadOnlySQL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query   string
+		wantErr string
+	}{
+		{query: "SELECT * FROM users WHERE name = 'DROP TABLE users'"},
+		{query: "-- delete old rows\nselect count(*) from t;"},
+		{query: "WITH recent AS (SELECT * FROM orders) SELECT REPLACE(name, 'a', 'b') FROM recent"},
+		{query: "EXPLAIN SELECT 1"},
+		{query: "SELECT 1; DELETE FROM users", wantErr: "DELETE statements are not allowed"},
+		{query: "WITH gone AS (DELETE FROM users RETURNING *) SELECT * FROM gone", wantErr: "DELETE is not allowed"},
+		{query: "SELECT * INTO backup FROM users", wantErr: "INTO is not allowed"},
+		{query: "/* read */ UPDATE users SET admin = true", wantErr: "UPDATE statements are not allowed"},
+		{query: " ; ", wantErr: "no SQL statement"},
+	}
+	for _, tc := range tests {
+		err := agents.ReadOnlySQL(tc.query)
+		if tc.wantErr == "" {
+			require.NoError(t, err, tc.query)
+			continue
+		}
+		require.EqualError(t, err, tc.wantErr, tc.query)
+	}
+}
+
+func TestAllowedDomains(t *testing.T) {
+	t.Parallel()
+
+	validate := agents.AllowedDomains("example.com", ".golang.org")
+	require.NoError(t, validate("https://example.com/a"))
+	require.NoError(t, validate(`{"url": "http://blog.golang.org/x"}`))
+	require.EqualError(t, validate("https://example.com.evil.org"), "domain example.com.evil.org is not allowed")
+	require.EqualError(t, validate("see https://example.com and https://notexample.com"),
+		"domain notexample.com is not allowed")
+	require.EqualError(t, validate("example.com"), "no URL in input")
+}
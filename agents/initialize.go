@@ -5,7 +5,10 @@ import (
 	"github.com/tmc/langchaingo/tools"
 )
 
-const _defaultMaxIterations = 5
+const (
+	_defaultMaxIterations  = 5
+	_defaultMaxConcurrency = 1
+)
 
 // AgentType is a string type representing the type of agent to create.
 type AgentType string
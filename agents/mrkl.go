@@ -36,7 +36,10 @@ type OneShotZeroAgent struct {
 	CallbacksHandler callbacks.Handler
 }
 
-var _ Agent = (*OneShotZeroAgent)(nil)
+var (
+	_ Agent            = (*OneShotZeroAgent)(nil)
+	_ FinalAnswerAgent = (*OneShotZeroAgent)(nil)
+)
 
 // NewOneShotAgent creates a new OneShotZeroAgent with the given LLM model, tools,
 // and options. It returns a pointer to the created agent. The opts parameter
@@ -49,7 +52,7 @@ func NewOneShotAgent(llm llms.Model, tools []tools.Tool, opts ...Option) *OneSho
 
 	return &OneShotZeroAgent{
 		Chain: chains.NewLLMChain(
-			llm,
+			usageReportingModel{Model: llm},
 			options.getMrklPrompt(tools),
 			chains.WithCallback(options.callbacksHandler),
 		),
@@ -73,14 +76,7 @@ func (a *OneShotZeroAgent) Plan(
 	fullInputs["agent_scratchpad"] = constructScratchPad(intermediateSteps)
 	fullInputs["today"] = time.Now().Format("January 02, 2006")
 
-	var stream func(ctx context.Context, chunk []byte) error
-
-	if a.CallbacksHandler != nil {
-		stream = func(ctx context.Context, chunk []byte) error {
-			a.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
-			return nil
-		}
-	}
+	stream := streamingFunc(ctx, a.CallbacksHandler)
 
 	output, err := chains.Predict(
 		ctx,
@@ -96,6 +92,23 @@ func (a *OneShotZeroAgent) Plan(
 	return a.parseOutput(output)
 }
 
+// FinalAnswer asks the LLM for a final answer based on the steps taken so far.
+func (a *OneShotZeroAgent) FinalAnswer(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) (*schema.AgentFinish, error) {
+	fullInputs := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		fullInputs[key] = value
+	}
+	fullInputs["agent_scratchpad"] = constructScratchPad(intermediateSteps)
+	fullInputs["today"] = time.Now().Format("January 02, 2006")
+
+	return generateChainFinalAnswer(ctx, a.Chain, fullInputs, a.parseOutput, a.OutputKey,
+		streamingFunc(ctx, a.CallbacksHandler))
+}
+
 func (a *OneShotZeroAgent) GetInputKeys() []string {
 	chainInputs := a.Chain.GetInputKeys()
 
@@ -0,0 +1,22 @@
+// Package multiagent contains ready-made topologies for teams of agents built
+// on the graphs runtime.
+//
+// Every member of a team is a Worker: a named agents.Agent, together with the
+// tools returned by its GetTools method. Workers share a scratchpad holding a
+// Turn for every time a worker acted, so each worker sees what the others did
+// before it. Every turn of a worker runs in an agents.Executor, configured
+// WithExecutorOptions, so guardrails, tool error handling, concurrency and
+// budgets apply to the workers as to any agent.
+//
+// A Supervisor lets an LLM route between the workers. After every turn the
+// supervisor picks the worker that acts next and what it should do, until it
+// gives the final answer. With Handoff the workers route between themselves:
+// a worker given a HandoffTool can transfer control to another worker, passing
+// on the task and the context it needs. A worker that answers without handing
+// off ends the run.
+//
+// Both topologies stop with ErrMaxTurns once the workers took the number of
+// turns set WithMaxTurns. They are chains, so they can be run with chains.Call
+// and friends, and expose their graph so they can be embedded in a larger
+// graph with graphs.Graph.AddSubgraph.
+package multiagent
@@ -0,0 +1,18 @@
+package multiagent
+
+import "errors"
+
+var (
+	// ErrInvalidWorker is returned when a team is created with a worker that
+	// has no name or agent, or with two workers of the same name.
+	ErrInvalidWorker = errors.New("invalid worker")
+	// ErrUnknownWorker is returned when control is passed to a worker that is
+	// not part of the team.
+	ErrUnknownWorker = errors.New("unknown worker")
+	// ErrMaxTurns is returned when the workers of a team took the maximum
+	// number of turns without finishing.
+	ErrMaxTurns = errors.New("team not finished before max turns")
+	// ErrUnableToParseRoute is returned if the output of the supervisor LLM can
+	// not be parsed.
+	ErrUnableToParseRoute = errors.New("unable to parse supervisor output")
+)
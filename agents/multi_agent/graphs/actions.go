@@ -0,0 +1,53 @@
+package graphs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// newActionGraph returns a graph that runs the given actions concurrently.
+// Every node calls the tool named by its action and stores the observation in
+// the state under the tool name.
+func newActionGraph(ts []tools.Tool, actions []schema.AgentAction) *Graph {
+	g := NewGraph()
+	for i, action := range actions {
+		name := fmt.Sprintf("action_%d", i)
+		// Errors can only come from invalid names, which can not happen here;
+		// anything else surfaces when the graph is compiled.
+		_ = g.AddNode(name, actionNode(ts, action))
+		_ = g.SetEntryPoint(name)
+		_ = g.SetFinishPoint(name)
+	}
+	return g
+}
+
+func actionNode(ts []tools.Tool, action schema.AgentAction) NodeFunc {
+	return func(ctx context.Context, _ State) (State, error) {
+		for _, tool := range ts {
+			if !strings.EqualFold(tool.Name(), action.Tool) {
+				continue
+			}
+			result, err := callTool(ctx, tool, action.ToolInput)
+			if err != nil {
+				result = err.Error()
+			}
+			return State{action.Tool: result}, nil
+		}
+		return State{action.Tool: fmt.Sprintf("%s is not a valid tool, try another one", action.Tool)}, nil
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[M ~map[string]V, V any](m M) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
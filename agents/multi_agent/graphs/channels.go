@@ -0,0 +1,123 @@
+package graphs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Reducer combines the current value of a state key with an update written by
+// a node. Every key of the state is a channel with a reducer; keys without an
+// explicitly added channel use LastValue.
+type Reducer func(current, update any) (any, error)
+
+// LastValue is a Reducer where the update replaces the current value. When
+// several nodes write the same key in one step the write of the node whose name
+// sorts last wins.
+func LastValue(_, update any) (any, error) {
+	return update, nil
+}
+
+// Append is a Reducer that appends the update to the current value. The update
+// can be a slice with elements assignable to those of the current value or a
+// single such element. The result is always a new slice so earlier states are
+// never modified. A single element written to a missing key starts a slice of
+// its type.
+func Append(current, update any) (any, error) {
+	if update == nil {
+		return current, nil
+	}
+	if current == nil {
+		upd := reflect.ValueOf(update)
+		if upd.Kind() == reflect.Slice {
+			return update, nil
+		}
+		out := reflect.MakeSlice(reflect.SliceOf(upd.Type()), 0, 1)
+		return reflect.Append(out, upd).Interface(), nil
+	}
+
+	cur := reflect.ValueOf(current)
+	if cur.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("%w: can not append to %T", ErrInvalidUpdate, current)
+	}
+	elem := cur.Type().Elem()
+
+	upd := reflect.ValueOf(update)
+	var items []reflect.Value
+	switch {
+	case upd.Kind() == reflect.Slice && upd.Type().Elem().AssignableTo(elem):
+		for i := 0; i < upd.Len(); i++ {
+			items = append(items, upd.Index(i))
+		}
+	case upd.Type().AssignableTo(elem):
+		items = []reflect.Value{upd}
+	default:
+		return nil, fmt.Errorf("%w: can not append %T to %T", ErrInvalidUpdate, update, current)
+	}
+
+	out := reflect.MakeSlice(cur.Type(), 0, cur.Len()+len(items))
+	out = reflect.AppendSlice(out, cur)
+	out = reflect.Append(out, items...)
+	return out.Interface(), nil
+}
+
+// MergeFunc adapts a typed merge function to a Reducer. A missing current value
+// is passed to fn as the zero value of T. Values decoded from a checkpoint are
+// converted to T like Get does.
+func MergeFunc[T any](fn func(current, update T) (T, error)) Reducer {
+	return func(current, update any) (any, error) {
+		var cur T
+		if current != nil {
+			var ok bool
+			if cur, ok = convert[T](current); !ok {
+				return nil, fmt.Errorf("%w: current value is %T, not %T", ErrInvalidUpdate, current, cur)
+			}
+		}
+		upd, ok := convert[T](update)
+		if !ok {
+			return nil, fmt.Errorf("%w: update is %T, not %T", ErrInvalidUpdate, update, upd)
+		}
+		return fn(cur, upd)
+	}
+}
+
+// AddChannel sets the reducer used to merge updates to the given state key.
+func (g *Graph) AddChannel(key string, reducer Reducer) error {
+	if key == "" || reducer == nil {
+		return fmt.Errorf("%w: %q", ErrInvalidChannel, key)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, exists := g.channels[key]; exists {
+		return fmt.Errorf("%w: %q already exists", ErrInvalidChannel, key)
+	}
+	g.channels[key] = reducer
+	return nil
+}
+
+// write is the update one node made during a step.
+type write struct {
+	node   string
+	update State
+}
+
+// apply merges the writes of a step into state through the channel reducers.
+// Writes must be ordered by node name so that merging is deterministic.
+func (c *CompiledGraph) apply(state State, writes []write) (State, error) {
+	next := state.Clone()
+	for _, w := range writes {
+		for _, key := range sortedKeys(w.update) {
+			reducer, ok := c.channels[key]
+			if !ok {
+				reducer = LastValue
+			}
+			v, err := reducer(next[key], w.update[key])
+			if err != nil {
+				return state, fmt.Errorf("node %q writing %q: %w", w.node, key, err)
+			}
+			next[key] = v
+		}
+	}
+	return next, nil
+}
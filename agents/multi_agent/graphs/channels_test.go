@@ -0,0 +1,192 @@
+package graphs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppend(t *testing.T) {
+	t.Parallel()
+
+	v, err := Append(nil, []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, v)
+
+	base := make([]string, 1, 10)
+	base[0] = "a"
+	v1, err := Append(base, []string{"b", "c"})
+	require.NoError(t, err)
+	v2, err := Append(base, "d")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, v1)
+	assert.Equal(t, []string{"a", "d"}, v2)
+
+	v, err = Append([]any{1}, []string{"x"})
+	require.NoError(t, err)
+	assert.Equal(t, []any{1, "x"}, v)
+
+	v, err = Append(nil, "a")
+	require.NoError(t, err)
+	v, err = Append(v, "b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, v)
+
+	_, err = Append([]int{1}, "x")
+	require.ErrorIs(t, err, ErrInvalidUpdate)
+	_, err = Append("x", "y")
+	require.ErrorIs(t, err, ErrInvalidUpdate)
+}
+
+func TestMergeFunc(t *testing.T) {
+	t.Parallel()
+
+	sum := MergeFunc(func(current, update int) (int, error) {
+		return current + update, nil
+	})
+	v, err := sum(nil, 2)
+	require.NoError(t, err)
+	v, err = sum(v, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 5, v)
+
+	_, err = sum(v, "x")
+	require.ErrorIs(t, err, ErrInvalidUpdate)
+}
+
+func TestAddChannelErrors(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph()
+	require.ErrorIs(t, g.AddChannel("", Append), ErrInvalidChannel)
+	require.ErrorIs(t, g.AddChannel("k", nil), ErrInvalidChannel)
+	require.NoError(t, g.AddChannel("k", Append))
+	require.ErrorIs(t, g.AddChannel("k", Append), ErrInvalidChannel)
+}
+
+func TestGraphFanOutFanIn(t *testing.T) {
+	t.Parallel()
+
+	var running, maxRunning atomic.Int32
+	branch := func(name string) NodeFunc {
+		return func(context.Context, State) (State, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return State{"results": []string{name}, "last": name}, nil
+		}
+	}
+
+	g := NewGraph()
+	require.NoError(t, g.AddChannel("results", Append))
+	require.NoError(t, g.AddNode("c", branch("c")))
+	require.NoError(t, g.AddNode("a", branch("a")))
+	require.NoError(t, g.AddNode("b", branch("b")))
+	require.NoError(t, g.AddNode("join", func(_ context.Context, s State) (State, error) {
+		results, _ := Get[[]string](s, "results")
+		return State{"count": len(results)}, nil
+	}))
+	for _, name := range []string{"a", "b", "c"} {
+		require.NoError(t, g.SetEntryPoint(name))
+		require.NoError(t, g.AddEdge(name, "join"))
+	}
+	require.NoError(t, g.SetFinishPoint("join"))
+
+	state, err := g.Execute(context.Background(), State{"results": []string{"start"}})
+	require.NoError(t, err)
+	assert.Equal(t, State{
+		"results": []string{"start", "a", "b", "c"},
+		"last":    "c",
+		"count":   4,
+	}, state)
+	assert.Equal(t, int32(3), maxRunning.Load())
+}
+
+func TestGraphAppendSingleElements(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph()
+	require.NoError(t, g.AddChannel("path", Append))
+	require.NoError(t, g.AddNode("first", constNode("path", "first")))
+	require.NoError(t, g.AddNode("second", constNode("path", "second")))
+	require.NoError(t, g.SetEntryPoint("first"))
+	require.NoError(t, g.AddEdge("first", "second"))
+	require.NoError(t, g.SetFinishPoint("second"))
+
+	state, err := g.Execute(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, state["path"])
+}
+
+func TestGraphAppendSingleElementsInParallel(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph()
+	require.NoError(t, g.AddChannel("results", Append))
+	require.NoError(t, g.AddNode("a", constNode("results", "a")))
+	require.NoError(t, g.AddNode("b", constNode("results", "b")))
+	for _, name := range []string{"a", "b"} {
+		require.NoError(t, g.SetEntryPoint(name))
+		require.NoError(t, g.SetFinishPoint(name))
+	}
+
+	state, err := g.Execute(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, state["results"])
+}
+
+func TestGraphJoinEdge(t *testing.T) {
+	t.Parallel()
+
+	var joins atomic.Int32
+	g := NewGraph()
+	require.NoError(t, g.AddChannel("path", Append))
+	require.NoError(t, g.AddNode("short", constNode("path", "short")))
+	require.NoError(t, g.AddNode("long1", constNode("path", "long1")))
+	require.NoError(t, g.AddNode("long2", constNode("path", "long2")))
+	require.NoError(t, g.AddNode("join", func(_ context.Context, s State) (State, error) {
+		joins.Add(1)
+		return State{"path": "join"}, nil
+	}))
+	require.NoError(t, g.SetEntryPoint("short"))
+	require.NoError(t, g.SetEntryPoint("long1"))
+	require.NoError(t, g.AddEdge("long1", "long2"))
+	require.NoError(t, g.AddJoinEdge([]string{"short", "long2"}, "join"))
+	require.NoError(t, g.SetFinishPoint("join"))
+
+	state, err := g.Execute(context.Background(), State{"path": []string{}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"long1", "short", "long2", "join"}, state["path"])
+	assert.Equal(t, int32(1), joins.Load())
+}
+
+func TestGraphStepErrorCancelsSiblings(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph()
+	require.NoError(t, g.AddNode("fail", func(context.Context, State) (State, error) {
+		return nil, assert.AnError
+	}))
+	require.NoError(t, g.AddNode("wait", func(ctx context.Context, _ State) (State, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+	for _, name := range []string{"fail", "wait"} {
+		require.NoError(t, g.SetEntryPoint(name))
+		require.NoError(t, g.SetFinishPoint(name))
+	}
+
+	state, err := g.Execute(context.Background(), State{"input": "x"})
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, State{"input": "x"}, state)
+}
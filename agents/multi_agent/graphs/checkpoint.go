@@ -0,0 +1,164 @@
+package graphs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Checkpoint is a snapshot of a graph run taken after a superstep. Step 0 is
+// taken once the input has been written to the state, before any node ran.
+type Checkpoint struct {
+	ThreadID string `json:"thread_id"`
+	Step     int    `json:"step"`
+	// State is the state after the step.
+	State State `json:"state"`
+	// Next holds the nodes scheduled for the following step. It is empty once
+	// the run has finished.
+	Next []string `json:"next"`
+	// Writes holds the update each node wrote in the step. The input of the
+	// run is stored as the write of START in step 0.
+	Writes map[string]State `json:"writes,omitempty"`
+	// Joins holds, for every join edge in the order the edges were added, the
+	// sources that have run since the edge was last followed.
+	Joins map[int][]string `json:"joins,omitempty"`
+	// Interrupt is set if the run is paused at an interrupt point.
+	Interrupt *Interrupt `json:"interrupt,omitempty"`
+	// PendingWrites holds the updates of the step in progress for runs paused
+	// after the nodes of the step ran.
+	PendingWrites map[string]State `json:"pending_writes,omitempty"`
+	// ForkedFrom is set on checkpoints created by Fork and points to the
+	// checkpoint they were copied from.
+	ForkedFrom *CheckpointRef `json:"forked_from,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// CheckpointRef points to the checkpoint of a thread at a step.
+type CheckpointRef struct {
+	ThreadID string `json:"thread_id"`
+	Step     int    `json:"step"`
+}
+
+// Checkpointer stores the checkpoints of graph runs keyed by thread ID and
+// step number.
+type Checkpointer interface {
+	// Put stores a checkpoint, replacing any checkpoint with the same thread
+	// ID and step.
+	Put(ctx context.Context, cp Checkpoint) error
+	// Get returns the checkpoint of a thread at the given step.
+	Get(ctx context.Context, threadID string, step int) (Checkpoint, error)
+	// Latest returns the checkpoint of a thread with the highest step.
+	Latest(ctx context.Context, threadID string) (Checkpoint, error)
+	// List returns all checkpoints of a thread ordered by step.
+	List(ctx context.Context, threadID string) ([]Checkpoint, error)
+}
+
+// MemoryCheckpointer is a Checkpointer that keeps checkpoints in memory.
+type MemoryCheckpointer struct {
+	mu      sync.Mutex
+	threads map[string]map[int]Checkpoint
+}
+
+var _ Checkpointer = (*MemoryCheckpointer)(nil)
+
+// NewMemoryCheckpointer creates a new MemoryCheckpointer.
+func NewMemoryCheckpointer() *MemoryCheckpointer {
+	return &MemoryCheckpointer{threads: make(map[string]map[int]Checkpoint)}
+}
+
+// Put stores a checkpoint.
+func (m *MemoryCheckpointer) Put(_ context.Context, cp Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.threads[cp.ThreadID] == nil {
+		m.threads[cp.ThreadID] = make(map[int]Checkpoint)
+	}
+	m.threads[cp.ThreadID][cp.Step] = cp.clone()
+	return nil
+}
+
+// Get returns the checkpoint of a thread at the given step.
+func (m *MemoryCheckpointer) Get(_ context.Context, threadID string, step int) (Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cp, ok := m.threads[threadID][step]
+	if !ok {
+		return Checkpoint{}, fmt.Errorf("%w: thread %q step %d", ErrCheckpointNotFound, threadID, step)
+	}
+	return cp.clone(), nil
+}
+
+// Latest returns the checkpoint of a thread with the highest step.
+func (m *MemoryCheckpointer) Latest(ctx context.Context, threadID string) (Checkpoint, error) {
+	cps, err := m.List(ctx, threadID)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	if len(cps) == 0 {
+		return Checkpoint{}, fmt.Errorf("%w: thread %q", ErrCheckpointNotFound, threadID)
+	}
+	return cps[len(cps)-1], nil
+}
+
+// List returns all checkpoints of a thread ordered by step.
+func (m *MemoryCheckpointer) List(_ context.Context, threadID string) ([]Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cps := make([]Checkpoint, 0, len(m.threads[threadID]))
+	for _, cp := range m.threads[threadID] {
+		cps = append(cps, cp.clone())
+	}
+	sort.Slice(cps, func(i, j int) bool { return cps[i].Step < cps[j].Step })
+	return cps, nil
+}
+
+func (cp Checkpoint) clone() Checkpoint {
+	c := cp
+	c.State = cp.State.Clone()
+	c.Next = append([]string(nil), cp.Next...)
+	c.Interrupt = cp.Interrupt.clone()
+	c.Writes = cloneWrites(cp.Writes)
+	c.PendingWrites = cloneWrites(cp.PendingWrites)
+	if cp.ForkedFrom != nil {
+		ref := *cp.ForkedFrom
+		c.ForkedFrom = &ref
+	}
+	if cp.Joins != nil {
+		c.Joins = make(map[int][]string, len(cp.Joins))
+		for i, from := range cp.Joins {
+			c.Joins[i] = append([]string(nil), from...)
+		}
+	}
+	return c
+}
+
+func (i *Interrupt) clone() *Interrupt {
+	if i == nil {
+		return nil
+	}
+	c := *i
+	c.Nodes = append([]string(nil), i.Nodes...)
+	if i.Subgraphs != nil {
+		c.Subgraphs = make(map[string]*Interrupt, len(i.Subgraphs))
+		for name, sub := range i.Subgraphs {
+			c.Subgraphs[name] = sub.clone()
+		}
+	}
+	return &c
+}
+
+func cloneWrites(writes map[string]State) map[string]State {
+	if writes == nil {
+		return nil
+	}
+	c := make(map[string]State, len(writes))
+	for node, update := range writes {
+		c[node] = update.Clone()
+	}
+	return c
+}
@@ -0,0 +1,148 @@
+package graphs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FileCheckpointer is a Checkpointer that stores every checkpoint as a JSON
+// file named after its step in a directory per thread.
+//
+// State values are stored as JSON, so after loading they hold the generic JSON
+// types. Use Get to read them back as typed values.
+type FileCheckpointer struct {
+	// Dir is the directory the thread directories are created in.
+	Dir string
+}
+
+var _ Checkpointer = (*FileCheckpointer)(nil)
+
+// NewFileCheckpointer creates a new FileCheckpointer storing checkpoints in dir.
+// The directory is created if it does not exist.
+func NewFileCheckpointer(dir string) (*FileCheckpointer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileCheckpointer{Dir: dir}, nil
+}
+
+// Put stores a checkpoint. The file is written to a temporary file first and
+// renamed so that a crash never leaves a partially written checkpoint.
+func (f *FileCheckpointer) Put(_ context.Context, cp Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("encoding checkpoint: %w", err)
+	}
+
+	dir := f.threadDir(cp.ThreadID)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, stepFileName(cp.Step)))
+}
+
+// Get returns the checkpoint of a thread at the given step.
+func (f *FileCheckpointer) Get(_ context.Context, threadID string, step int) (Checkpoint, error) {
+	cp, err := readCheckpoint(filepath.Join(f.threadDir(threadID), stepFileName(step)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Checkpoint{}, fmt.Errorf("%w: thread %q step %d", ErrCheckpointNotFound, threadID, step)
+	}
+	return cp, err
+}
+
+// Latest returns the checkpoint of a thread with the highest step.
+func (f *FileCheckpointer) Latest(ctx context.Context, threadID string) (Checkpoint, error) {
+	steps, err := f.steps(threadID)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	if len(steps) == 0 {
+		return Checkpoint{}, fmt.Errorf("%w: thread %q", ErrCheckpointNotFound, threadID)
+	}
+	return f.Get(ctx, threadID, steps[len(steps)-1])
+}
+
+// List returns all checkpoints of a thread ordered by step.
+func (f *FileCheckpointer) List(ctx context.Context, threadID string) ([]Checkpoint, error) {
+	steps, err := f.steps(threadID)
+	if err != nil {
+		return nil, err
+	}
+	cps := make([]Checkpoint, 0, len(steps))
+	for _, step := range steps {
+		cp, err := f.Get(ctx, threadID, step)
+		if err != nil {
+			return nil, err
+		}
+		cps = append(cps, cp)
+	}
+	return cps, nil
+}
+
+func (f *FileCheckpointer) threadDir(threadID string) string {
+	return filepath.Join(f.Dir, url.PathEscape(threadID))
+}
+
+// steps returns the sorted steps stored for a thread.
+func (f *FileCheckpointer) steps(threadID string) ([]int, error) {
+	entries, err := os.ReadDir(f.threadDir(threadID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]int, 0, len(entries))
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		step, err := strconv.Atoi(name)
+		if err != nil {
+			continue
+		}
+		steps = append(steps, step)
+	}
+	sort.Ints(steps)
+	return steps, nil
+}
+
+func stepFileName(step int) string {
+	return fmt.Sprintf("%08d.json", step)
+}
+
+func readCheckpoint(path string) (Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return Checkpoint{}, fmt.Errorf("decoding checkpoint %s: %w", path, err)
+	}
+	return cp, nil
+}
@@ -0,0 +1,205 @@
+package graphs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointers(t *testing.T) {
+	t.Parallel()
+
+	fileCheckpointer, err := NewFileCheckpointer(t.TempDir())
+	require.NoError(t, err)
+
+	for name, c := range map[string]Checkpointer{
+		"memory": NewMemoryCheckpointer(),
+		"file":   fileCheckpointer,
+	} {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			_, err := c.Latest(ctx, "a/thread")
+			require.ErrorIs(t, err, ErrCheckpointNotFound)
+			_, err = c.Get(ctx, "a/thread", 0)
+			require.ErrorIs(t, err, ErrCheckpointNotFound)
+
+			for step := 0; step < 12; step++ {
+				require.NoError(t, c.Put(ctx, Checkpoint{
+					ThreadID: "a/thread",
+					Step:     step,
+					State:    State{"step": step},
+					Next:     []string{"node"},
+					Joins:    map[int][]string{0: {"x"}},
+				}))
+			}
+
+			latest, err := c.Latest(ctx, "a/thread")
+			require.NoError(t, err)
+			assert.Equal(t, 11, latest.Step)
+			step, ok := Get[int](latest.State, "step")
+			assert.True(t, ok)
+			assert.Equal(t, 11, step)
+			assert.Equal(t, map[int][]string{0: {"x"}}, latest.Joins)
+
+			cps, err := c.List(ctx, "a/thread")
+			require.NoError(t, err)
+			require.Len(t, cps, 12)
+			for i, cp := range cps {
+				assert.Equal(t, i, cp.Step)
+			}
+
+			cps, err = c.List(ctx, "other")
+			require.NoError(t, err)
+			assert.Empty(t, cps)
+		})
+	}
+}
+
+func newCountingGraph(t *testing.T, failAt int) *Graph {
+	t.Helper()
+
+	g := NewGraph()
+	require.NoError(t, g.AddChannel("visited", Append))
+	require.NoError(t, g.AddNode("count", func(_ context.Context, s State) (State, error) {
+		n, _ := Get[int](s, "n")
+		if n+1 == failAt {
+			return nil, assert.AnError
+		}
+		return State{"n": n + 1, "visited": []string{"count"}}, nil
+	}))
+	require.NoError(t, g.SetEntryPoint("count"))
+	require.NoError(t, g.AddConditionalEdges("count", func(_ context.Context, s State) (string, error) {
+		if n, _ := Get[int](s, "n"); n < 4 {
+			return "count", nil
+		}
+		return END, nil
+	}, nil))
+	return g
+}
+
+func TestGraphCheckpointing(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	checkpointer := NewMemoryCheckpointer()
+	c, err := newCountingGraph(t, 0).Compile(WithCheckpointer(checkpointer))
+	require.NoError(t, err)
+
+	_, err = c.Invoke(ctx, nil)
+	require.ErrorIs(t, err, ErrNoThreadID)
+
+	state, err := c.Invoke(ctx, nil, WithThreadID("thread"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, state["n"])
+
+	cps, err := checkpointer.List(ctx, "thread")
+	require.NoError(t, err)
+	require.Len(t, cps, 5)
+	assert.Equal(t, []string{"count"}, cps[0].Next)
+	assert.Empty(t, cps[4].Next)
+	assert.Equal(t, 4, cps[4].State["n"])
+
+	// Resuming a finished run returns its final state.
+	state, err = c.Resume(ctx, "thread")
+	require.NoError(t, err)
+	assert.Equal(t, 4, state["n"])
+}
+
+func TestGraphResume(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	checkpointer, err := NewFileCheckpointer(t.TempDir())
+	require.NoError(t, err)
+
+	failing, err := newCountingGraph(t, 3).Compile(WithCheckpointer(checkpointer))
+	require.NoError(t, err)
+	state, err := failing.Invoke(ctx, State{"visited": []string{}}, WithThreadID("thread"))
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 2, state["n"])
+
+	fixed, err := newCountingGraph(t, 0).Compile(WithCheckpointer(checkpointer))
+	require.NoError(t, err)
+	state, err = fixed.Resume(ctx, "thread")
+	require.NoError(t, err)
+	n, _ := Get[int](state, "n")
+	assert.Equal(t, 4, n)
+	visited, _ := Get[[]string](state, "visited")
+	assert.Equal(t, []string{"count", "count", "count", "count"}, visited)
+
+	_, err = fixed.Resume(ctx, "missing")
+	require.ErrorIs(t, err, ErrCheckpointNotFound)
+
+	uncheckpointed, err := newCountingGraph(t, 0).Compile()
+	require.NoError(t, err)
+	_, err = uncheckpointed.Resume(ctx, "thread")
+	require.ErrorIs(t, err, ErrNoCheckpointer)
+}
+
+func TestGraphResumeTypedReducer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	checkpointer, err := NewFileCheckpointer(t.TempDir())
+	require.NoError(t, err)
+
+	fail := true
+	g := NewGraph()
+	require.NoError(t, g.AddChannel("n", MergeFunc(func(current, update int) (int, error) {
+		return current + update, nil
+	})))
+	require.NoError(t, g.AddNode("a", constNode("n", 1)))
+	require.NoError(t, g.AddNode("b", func(context.Context, State) (State, error) {
+		if fail {
+			return nil, assert.AnError
+		}
+		return State{"n": 2}, nil
+	}))
+	require.NoError(t, g.SetEntryPoint("a"))
+	require.NoError(t, g.AddEdge("a", "b"))
+	require.NoError(t, g.SetFinishPoint("b"))
+	c, err := g.Compile(WithCheckpointer(checkpointer))
+	require.NoError(t, err)
+
+	_, err = c.Invoke(ctx, nil, WithThreadID("thread"))
+	require.ErrorIs(t, err, assert.AnError)
+
+	fail = false
+	state, err := c.Resume(ctx, "thread")
+	require.NoError(t, err)
+	assert.Equal(t, 3, state["n"])
+}
+
+func TestGetConvertsDecodedValues(t *testing.T) {
+	t.Parallel()
+
+	type point struct{ X, Y int }
+	s := State{
+		"n":     float64(3),
+		"list":  []any{"a", "b"},
+		"point": map[string]any{"X": float64(1), "Y": float64(2)},
+		"nil":   nil,
+	}
+
+	n, ok := Get[int](s, "n")
+	assert.True(t, ok)
+	assert.Equal(t, 3, n)
+	list, ok := Get[[]string](s, "list")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"a", "b"}, list)
+	p, ok := Get[point](s, "point")
+	assert.True(t, ok)
+	assert.Equal(t, point{1, 2}, p)
+
+	_, ok = Get[string](s, "n")
+	assert.False(t, ok)
+	_, ok = Get[int](s, "nil")
+	assert.False(t, ok)
+	_, ok = Get[int](s, "missing")
+	assert.False(t, ok)
+}
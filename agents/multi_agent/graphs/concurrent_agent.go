@@ -1,8 +1,7 @@
-package multi_agent
+package graphs
 
 import (
 	"context"
-	"sync"
 
 	"github.com/tmc/langchaingo/agents"
 	"github.com/tmc/langchaingo/schema"
@@ -10,13 +9,23 @@ import (
 )
 
 // ConcurrentAgent is an implementation of the Agent interface with concurrency features.
+// The agents.Executor runs its actions like those of any agent: one at a time
+// unless agents.WithMaxConcurrency is raised. Outside of an executor
+// InitializeConcurrentActions and ExecuteConcurrentActions run them as a graph.
 type ConcurrentAgent struct {
 	Graph *Graph
 	Tools []tools.Tool
+	// Results holds the observations of the last executed actions keyed by
+	// tool name.
+	Results State
+	// Err is the error of the last execution, if any.
+	Err error
 }
 
-var _ agents.Agent = (*ConcurrentAgent)(nil)
-var _ agents.ConcurrentAgent = (*ConcurrentAgent)(nil)
+var (
+	_ agents.Agent           = (*ConcurrentAgent)(nil)
+	_ agents.ConcurrentAgent = (*ConcurrentAgent)(nil)
+)
 
 // NewConcurrentAgent creates a new ConcurrentAgent.
 func NewConcurrentAgent() *ConcurrentAgent {
@@ -28,45 +37,29 @@ func NewConcurrentAgent() *ConcurrentAgent {
 
 // Plan decides what action to take or returns the final result of the input.
 func (a *ConcurrentAgent) Plan(
-	ctx context.Context,
+	_ context.Context,
 	intermediateSteps []schema.AgentStep,
 	inputs map[string]string,
 ) ([]schema.AgentAction, *schema.AgentFinish, error) {
 	if len(intermediateSteps) == 0 {
 		// Initial planning phase
-		actions := make([]schema.AgentAction, 0)
-		for toolName, input := range inputs {
-			action := schema.AgentAction{
-				Tool:  toolName,
-				Input: input,
-				Log:   "Initial action from input",
-			}
-			actions = append(actions, action)
+		actions := make([]schema.AgentAction, 0, len(inputs))
+		for _, toolName := range sortedKeys(inputs) {
+			actions = append(actions, schema.AgentAction{
+				Tool:      toolName,
+				ToolInput: inputs[toolName],
+				Log:       "Initial action from input",
+			})
 		}
 		return actions, nil, nil
 	}
 
-	// Analyze intermediate steps and decide next actions
-	allCompleted := true
+	// All steps completed, return final result
+	result := make(map[string]any, len(intermediateSteps))
 	for _, step := range intermediateSteps {
-		if step.Action != nil && !step.Observation.IsEmpty() {
-			continue
-		}
-		allCompleted = false
-		break
-	}
-
-	if allCompleted {
-		// All steps completed, return final result
-		result := make(map[string]any)
-		for _, step := range intermediateSteps {
-			result[step.Action.Tool] = step.Observation.String()
-		}
-		return nil, &schema.AgentFinish{ReturnValues: result}, nil
+		result[step.Action.Tool] = step.Observation
 	}
-
-	// Continue with more actions if needed
-	return []schema.AgentAction{}, nil, nil
+	return nil, &schema.AgentFinish{ReturnValues: result}, nil
 }
 
 // GetInputKeys returns the input keys for the agent.
@@ -94,56 +87,10 @@ func (a *ConcurrentAgent) GetTools() []tools.Tool {
 
 // InitializeConcurrentActions initializes the concurrent actions for the agent.
 func (a *ConcurrentAgent) InitializeConcurrentActions(actions []schema.AgentAction) {
-	for _, action := range actions {
-		node := &Node{
-			ID:      len(a.Graph.Nodes),
-			Value:   action,
-			State:   "initialized",
-			Actions: []interface{}{action},
-		}
-		a.Graph.AddNode(node)
-	}
+	a.Graph = newActionGraph(a.Tools, actions)
 }
 
 // ExecuteConcurrentActions executes the concurrent actions for the agent.
 func (a *ConcurrentAgent) ExecuteConcurrentActions() {
-	var wg sync.WaitGroup
-	for _, node := range a.Graph.Nodes {
-		wg.Add(1)
-		go func(n *Node) {
-			defer wg.Done()
-			for _, action := range n.Actions {
-				agentAction, ok := action.(schema.AgentAction)
-				if !ok {
-					n.mu.Lock()
-					n.State = "error"
-					n.mu.Unlock()
-					continue
-				}
-
-				n.mu.Lock()
-				n.State = "executing"
-				n.mu.Unlock()
-
-				// Execute the tool
-				for _, tool := range a.Tools {
-					if tool.Name() == agentAction.Tool {
-						result, err := tool.Call(agentAction.Input)
-						n.mu.Lock()
-						if err != nil {
-							n.State = "error"
-							n.Result = err.Error()
-						} else {
-							n.State = "completed"
-							n.Result = result
-						}
-						n.mu.Unlock()
-						break
-					}
-				}
-			}
-		}(node)
-	}
-	wg.Wait()
-	a.Graph.Execute()
+	a.Results, a.Err = a.Graph.Execute(context.Background(), nil)
 }
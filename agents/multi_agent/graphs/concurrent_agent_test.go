@@ -1,7 +1,8 @@
-package multi_agent
+package graphs
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,54 +10,66 @@ import (
 	"github.com/tmc/langchaingo/tools"
 )
 
+type upperTool struct{}
+
+func (upperTool) Name() string        { return "upper" }
+func (upperTool) Description() string { return "Converts the input to upper case." }
+func (upperTool) Call(_ context.Context, input string) (string, error) {
+	return strings.ToUpper(input), nil
+}
+
 func TestConcurrentAgent_Plan(t *testing.T) {
+	t.Parallel()
 	agent := NewConcurrentAgent()
 
-	// Mock inputs and expected outputs
 	inputs := map[string]string{"input": "test"}
-	expectedActions := []schema.AgentAction{}
-	expectedFinish := &schema.AgentFinish{ReturnValues: map[string]any{"output": "test"}}
-
 	actions, finish, err := agent.Plan(context.Background(), nil, inputs)
 	assert.NoError(t, err)
-	assert.Equal(t, expectedActions, actions)
-	assert.Equal(t, expectedFinish, finish)
+	assert.Nil(t, finish)
+	assert.Equal(t, []schema.AgentAction{
+		{Tool: "input", ToolInput: "test", Log: "Initial action from input"},
+	}, actions)
+
+	steps := []schema.AgentStep{{Action: actions[0], Observation: "done"}}
+	actions, finish, err = agent.Plan(context.Background(), steps, inputs)
+	assert.NoError(t, err)
+	assert.Empty(t, actions)
+	assert.Equal(t, &schema.AgentFinish{ReturnValues: map[string]any{"input": "done"}}, finish)
 }
 
 func TestConcurrentAgent_GetInputKeys(t *testing.T) {
+	t.Parallel()
 	agent := NewConcurrentAgent()
 	expectedKeys := []string{}
 	assert.Equal(t, expectedKeys, agent.GetInputKeys())
 }
 
 func TestConcurrentAgent_GetOutputKeys(t *testing.T) {
+	t.Parallel()
 	agent := NewConcurrentAgent()
 	expectedKeys := []string{}
 	assert.Equal(t, expectedKeys, agent.GetOutputKeys())
 }
 
 func TestConcurrentAgent_GetTools(t *testing.T) {
+	t.Parallel()
 	agent := NewConcurrentAgent()
 	expectedTools := []tools.Tool{}
 	assert.Equal(t, expectedTools, agent.GetTools())
 }
 
-func TestConcurrentAgent_InitializeConcurrentActions(t *testing.T) {
-	agent := NewConcurrentAgent()
-	actions := []schema.AgentAction{
-		{Tool: "testTool", ToolInput: "testInput"},
-	}
-	agent.InitializeConcurrentActions(actions)
-	assert.Equal(t, 1, len(agent.Graph.Nodes))
-	assert.Equal(t, "initialized", agent.Graph.Nodes[0].State)
-}
-
 func TestConcurrentAgent_ExecuteConcurrentActions(t *testing.T) {
+	t.Parallel()
 	agent := NewConcurrentAgent()
-	actions := []schema.AgentAction{
-		{Tool: "testTool", ToolInput: "testInput"},
-	}
-	agent.InitializeConcurrentActions(actions)
+	agent.Tools = []tools.Tool{upperTool{}}
+	agent.InitializeConcurrentActions([]schema.AgentAction{
+		{Tool: "upper", ToolInput: "testInput"},
+		{Tool: "missing", ToolInput: "testInput"},
+	})
 	agent.ExecuteConcurrentActions()
-	assert.Equal(t, "completed", agent.Graph.Nodes[0].State)
+	assert.NoError(t, agent.Err)
+	assert.Equal(t, State{
+		"upper":   "TESTINPUT",
+		"missing": "missing is not a valid tool, try another one",
+	}, agent.Results)
 }
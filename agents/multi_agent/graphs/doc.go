@@ -0,0 +1,66 @@
+// Package graphs contains a state-machine runtime for building multi-agent
+// workflows.
+//
+// A Graph is made of named nodes and the edges between them. Every node is a
+// NodeFunc that receives the current State and returns an update holding the
+// keys it wants to change. Edges are either normal edges, which always lead to
+// the same node, or conditional edges, where a RouterFunc picks the next node
+// from the state. Runs begin at the virtual START node and finish once every
+// branch has reached the END node.
+//
+// Runs proceed in supersteps. Every node scheduled for a step runs
+// concurrently on the same state, and the step ends at a barrier once all of
+// them have returned. A node with several outgoing edges fans out to all of its
+// successors, and AddJoinEdge fans branches back in by waiting for all of its
+// sources. Every key of the state is a channel with a Reducer that merges the
+// updates written to it: LastValue by default, Append, or a custom MergeFunc.
+// Updates are merged in the order of the node names so results do not depend
+// on which branch finished first.
+//
+// Graphs may contain cycles, such as an agent node and a tool node calling
+// each other. To keep runaway loops in check every call of Invoke or Resume
+// stops after DefaultRecursionLimit steps, or the limit set
+// WithRecursionLimit, with a RecursionLimitError holding the state reached so
+// far. Nodes can also be given a Timeout and a RetryPolicy with Retry.
+//
+// A Graph must be compiled before it can run. Compile validates the topology,
+// rejecting edges that point to nodes that do not exist and nodes that can
+// never be reached from START, and returns an immutable CompiledGraph.
+//
+// A graph compiled WithCheckpointer saves a Checkpoint after every step, keyed
+// by the thread ID of the run and the step number. Resume continues the run of
+// a thread from its latest checkpoint, for example after the process crashed.
+// History and GetState show the state, next nodes and writes of every step of
+// a thread, and Fork copies a thread up to any step, optionally changing the
+// state, into a new thread that Resume runs from there.
+// MemoryCheckpointer and FileCheckpointer are provided here; the sqlite3
+// subpackage stores checkpoints in a database.
+//
+// Nodes added with InterruptBefore or InterruptAfter pause the run at that
+// point. The run is saved and returns an *Interrupt describing the pending
+// step, including the tool calls waiting under ActionsKey. Calling Resume
+// WithDecision continues the run: a Decision approves the step, rejects it
+// with a reason, or edits the state or the pending tool calls first. ToolNode
+// and RejectToolCalls make it easy to let a reviewer vet tool calls before
+// they run.
+//
+// Stream runs a graph and returns an EventSeq over the events of the run, so
+// progress can be shown while it is in progress. The StreamMode picks the
+// events: the state after every step, the update of every node, the chunks of
+// LLMs called with StreamingFunc or CallbacksHandler, and the start and end of
+// tool calls. Every event carries the node and step it comes from. Stopping
+// the iteration or canceling the context cancels the run. Nodes also run in a
+// usage scope named after them, so a callbacks.UsageTracker reports the token
+// usage and cost of every node.
+//
+// AddSubgraph embeds a compiled graph as a node of another graph, so teams of
+// agents can be built from smaller graphs. WithInputKeys and WithOutputKeys
+// map keys between the parent state and the subgraph state. Subgraphs share
+// the checkpointer of the parent graph, and their events and interrupts carry
+// namespaced node paths such as "team/tools".
+//
+// DrawMermaid and DrawDOT render a compiled graph as a Mermaid flowchart or a
+// Graphviz digraph, with labeled conditional edges, subgraphs as clusters and
+// interrupt points. WithHistory overlays a run: the edges it followed and the
+// status of every node.
+package graphs
@@ -0,0 +1,388 @@
+package graphs
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// NodeStatus is the status of a node in a diagram drawn WithHistory.
+type NodeStatus string
+
+const (
+	// NodeStatusDone is used for nodes that ran at least once.
+	NodeStatusDone NodeStatus = "done"
+	// NodeStatusPending is used for nodes scheduled for the next step.
+	NodeStatusPending NodeStatus = "pending"
+	// NodeStatusInterrupted is used for nodes the run is paused at.
+	NodeStatusInterrupted NodeStatus = "interrupted"
+)
+
+// DrawOption is a function that configures a diagram.
+type DrawOption func(*drawOptions)
+
+type drawOptions struct {
+	history []Snapshot
+}
+
+// WithHistory is an option for DrawMermaid and DrawDOT overlaying a run on the
+// diagram, as returned by History. The edges the run followed are highlighted
+// and the nodes are colored by their status in the latest snapshot.
+func WithHistory(history []Snapshot) DrawOption {
+	return func(o *drawOptions) {
+		o.history = history
+	}
+}
+
+// DrawMermaid returns a Mermaid flowchart of the graph. Conditional edges are
+// dotted and labeled with their route, subgraphs are drawn as nested
+// subgraphs and nodes with an interrupt say so in their label.
+func (c *CompiledGraph) DrawMermaid(opts ...DrawOption) string {
+	d := c.diagram(opts)
+	ids := d.mermaidIDs()
+
+	var sb strings.Builder
+	sb.WriteString("flowchart TD\n")
+	d.writeMermaidNodes(&sb, "\t", ids)
+
+	var visited []string
+	for i, e := range d.edges {
+		arrow := "-->"
+		if e.conditional {
+			arrow = "-.->"
+		}
+		if e.label != "" {
+			arrow += `|"` + mermaidText(e.label) + `"|`
+		}
+		fmt.Fprintf(&sb, "\t%s %s %s\n", ids[e.from], arrow, ids[e.to])
+		if e.visited {
+			visited = append(visited, fmt.Sprint(i))
+		}
+	}
+
+	if len(visited) > 0 {
+		fmt.Fprintf(&sb, "\tlinkStyle %s stroke:#2e7d32,stroke-width:3px\n", strings.Join(visited, ","))
+	}
+	for _, status := range []NodeStatus{NodeStatusDone, NodeStatusPending, NodeStatusInterrupted} {
+		nodes := d.nodesWithStatus(status)
+		if len(nodes) == 0 {
+			continue
+		}
+		nodeIDs := make([]string, len(nodes))
+		for i, n := range nodes {
+			nodeIDs[i] = ids[n]
+		}
+		fmt.Fprintf(&sb, "\tclassDef %s %s\n", status, _statusStyles[status].mermaid)
+		fmt.Fprintf(&sb, "\tclass %s %s\n", strings.Join(nodeIDs, ","), status)
+	}
+	return sb.String()
+}
+
+// DrawDOT returns a Graphviz DOT digraph of the graph. Conditional edges are
+// dashed and labeled with their route, subgraphs are drawn as clusters and
+// nodes with an interrupt say so in their label.
+func (c *CompiledGraph) DrawDOT(opts ...DrawOption) string {
+	d := c.diagram(opts)
+
+	var sb strings.Builder
+	sb.WriteString("digraph {\n\tcompound=true;\n")
+	d.writeDOTNodes(&sb, "\t")
+
+	for _, e := range d.edges {
+		from, to := e.from, e.to
+		var attrs []string
+		if e.conditional {
+			attrs = append(attrs, "style=dashed")
+		}
+		if e.label != "" {
+			attrs = append(attrs, "label="+dotQuote(e.label))
+		}
+		if e.visited {
+			attrs = append(attrs, `color="#2e7d32"`, "penwidth=3")
+		}
+		// Edges from and to clusters are drawn from their END and to their
+		// START node, clipped at the border of the cluster.
+		if d.clusters[e.from] {
+			from = e.from + "/" + END
+			attrs = append(attrs, "ltail="+dotQuote("cluster_"+e.from))
+		}
+		if d.clusters[e.to] {
+			to = e.to + "/" + START
+			attrs = append(attrs, "lhead="+dotQuote("cluster_"+e.to))
+		}
+
+		fmt.Fprintf(&sb, "\t%s -> %s", dotQuote(from), dotQuote(to))
+		if len(attrs) > 0 {
+			fmt.Fprintf(&sb, " [%s]", strings.Join(attrs, ", "))
+		}
+		sb.WriteString(";\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+var _statusStyles = map[NodeStatus]struct{ mermaid, dot string }{
+	NodeStatusDone:        {"fill:#c8e6c9,stroke:#2e7d32", `style=filled, fillcolor="#c8e6c9"`},
+	NodeStatusPending:     {"fill:#fff9c4,stroke:#f9a825", `style=filled, fillcolor="#fff9c4"`},
+	NodeStatusInterrupted: {"fill:#ffcdd2,stroke:#c62828", `style=filled, fillcolor="#ffcdd2"`},
+}
+
+// diagram is the format independent layout of a drawn graph.
+type diagram struct {
+	cluster
+	edges []diagramEdge
+	// clusters holds the paths of the subgraph nodes.
+	clusters map[string]bool
+	status   map[string]NodeStatus
+}
+
+// cluster holds the nodes of a graph or subgraph.
+type cluster struct {
+	path     string
+	nodes    []diagramNode
+	clusters []cluster
+}
+
+type diagramNode struct {
+	path  string
+	label string
+}
+
+type diagramEdge struct {
+	from, to    string
+	label       string
+	conditional bool
+	visited     bool
+}
+
+func (c *CompiledGraph) diagram(opts []DrawOption) *diagram {
+	var o drawOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	d := &diagram{clusters: make(map[string]bool)}
+	d.cluster = d.add(c, "")
+	d.overlay(c, o.history)
+	return d
+}
+
+// add adds the edges of the graph c and of its subgraphs, drawn under the
+// given path prefix, and returns its nodes.
+func (d *diagram) add(c *CompiledGraph, prefix string) cluster {
+	path := func(name string) string { return prefix + name }
+	cl := cluster{path: strings.TrimSuffix(prefix, "/")}
+	cl.nodes = append(cl.nodes, diagramNode{path: path(START), label: START})
+
+	var subgraphs []string
+	for _, name := range c.NodeNames() {
+		node := c.nodes[name]
+		if node.Subgraph() != nil {
+			d.clusters[path(name)] = true
+			subgraphs = append(subgraphs, name)
+			continue
+		}
+		label := name
+		switch {
+		case node.InterruptBefore && node.InterruptAfter:
+			label += " (interrupt before and after)"
+		case node.InterruptBefore:
+			label += " (interrupt before)"
+		case node.InterruptAfter:
+			label += " (interrupt after)"
+		}
+		cl.nodes = append(cl.nodes, diagramNode{path: path(name), label: label})
+	}
+	cl.nodes = append(cl.nodes, diagramNode{path: path(END), label: END})
+
+	for _, from := range append([]string{START}, c.NodeNames()...) {
+		for _, to := range c.edges[from] {
+			d.edges = append(d.edges, diagramEdge{from: path(from), to: path(to)})
+		}
+		if b := c.branches[from]; b != nil {
+			for _, e := range branchEdges(c, b) {
+				d.edges = append(d.edges, diagramEdge{
+					from: path(from), to: path(e.to), label: e.label, conditional: true,
+				})
+			}
+		}
+	}
+	for _, j := range c.joins {
+		for _, from := range j.from {
+			d.edges = append(d.edges, diagramEdge{from: path(from), to: path(j.to), label: "join"})
+		}
+	}
+
+	for _, name := range subgraphs {
+		cl.clusters = append(cl.clusters, d.add(c.nodes[name].Subgraph(), path(name)+"/"))
+	}
+	return cl
+}
+
+// branchEdges returns the routes of a conditional edge sorted by label. Without
+// a path map every node and END can be picked, and the routes have no label.
+func branchEdges(c *CompiledGraph, b *branch) []diagramEdge {
+	if b.paths == nil {
+		var edges []diagramEdge
+		for _, to := range append(c.NodeNames(), END) {
+			edges = append(edges, diagramEdge{to: to})
+		}
+		return edges
+	}
+	edges := make([]diagramEdge, 0, len(b.paths))
+	for _, label := range sortedKeys(b.paths) {
+		edges = append(edges, diagramEdge{to: b.paths[label], label: label})
+	}
+	return edges
+}
+
+// overlay marks the edges the run followed and the status of the nodes of c.
+func (d *diagram) overlay(c *CompiledGraph, history []Snapshot) {
+	if len(history) == 0 {
+		return
+	}
+	d.status = make(map[string]NodeStatus)
+
+	var prev []string
+	for _, snapshot := range history {
+		ran := sortedKeys(snapshot.Writes)
+		for _, name := range ran {
+			if _, ok := c.nodes[name]; ok {
+				d.status[name] = NodeStatusDone
+			}
+		}
+		d.visit(prev, ran)
+		prev = ran
+	}
+
+	last := history[len(history)-1]
+	if len(last.Next) == 0 && last.Interrupt == nil {
+		d.visit(prev, []string{END})
+	}
+	d.visit(prev, last.Next)
+	for _, name := range last.Next {
+		d.status[name] = NodeStatusPending
+	}
+	if last.Interrupt != nil {
+		for _, name := range last.Interrupt.Nodes {
+			// Interrupts of subgraphs are drawn on the subgraph node.
+			name, _, _ = strings.Cut(name, "/")
+			d.status[name] = NodeStatusInterrupted
+		}
+	}
+}
+
+// visit marks the edges leading from the nodes of one step to the next.
+func (d *diagram) visit(from, to []string) {
+	for i, e := range d.edges {
+		if slices.Contains(from, e.from) && slices.Contains(to, e.to) {
+			d.edges[i].visited = true
+		}
+	}
+}
+
+// nodesWithStatus returns the sorted paths of the nodes with the given status.
+func (d *diagram) nodesWithStatus(status NodeStatus) []string {
+	var nodes []string
+	for _, name := range sortedKeys(d.status) {
+		if d.status[name] == status {
+			nodes = append(nodes, name)
+		}
+	}
+	return nodes
+}
+
+func (cl *cluster) writeMermaidNodes(sb *strings.Builder, indent string, ids map[string]string) {
+	for _, n := range cl.nodes {
+		shape := `["%s"]`
+		if n.label == START || n.label == END {
+			shape = `(["%s"])`
+		}
+		fmt.Fprintf(sb, "%s%s"+shape+"\n", indent, ids[n.path], mermaidText(n.label))
+	}
+	for _, sub := range cl.clusters {
+		fmt.Fprintf(sb, "%ssubgraph %s [\"%s\"]\n", indent, ids[sub.path], mermaidText(sub.path))
+		sub.writeMermaidNodes(sb, indent+"\t", ids)
+		fmt.Fprintf(sb, "%send\n", indent)
+	}
+}
+
+func (d *diagram) writeDOTNodes(sb *strings.Builder, indent string) {
+	d.cluster.writeDOTNodes(sb, indent, d.status)
+}
+
+func (cl *cluster) writeDOTNodes(sb *strings.Builder, indent string, status map[string]NodeStatus) {
+	for _, n := range cl.nodes {
+		attrs := []string{"label=" + dotQuote(n.label)}
+		if n.label == START || n.label == END {
+			attrs = append(attrs, "shape=oval")
+		} else {
+			attrs = append(attrs, "shape=box")
+		}
+		if s, ok := status[n.path]; ok {
+			attrs = append(attrs, _statusStyles[s].dot)
+		}
+		fmt.Fprintf(sb, "%s%s [%s];\n", indent, dotQuote(n.path), strings.Join(attrs, ", "))
+	}
+	for _, sub := range cl.clusters {
+		fmt.Fprintf(sb, "%ssubgraph %s {\n", indent, dotQuote("cluster_"+sub.path))
+		fmt.Fprintf(sb, "%s\tlabel=%s;\n", indent, dotQuote(sub.path))
+		if s, ok := status[sub.path]; ok {
+			fmt.Fprintf(sb, "%s\t%s;\n", indent, strings.ReplaceAll(_statusStyles[s].dot, ", ", "; "))
+		}
+		sub.writeDOTNodes(sb, indent+"\t", status)
+		fmt.Fprintf(sb, "%s}\n", indent)
+	}
+}
+
+var _mermaidIDRegexp = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// _mermaidKeywords are the words of the Mermaid flowchart syntax that cannot
+// be used as IDs.
+var _mermaidKeywords = map[string]bool{
+	"end": true, "graph": true, "flowchart": true, "subgraph": true, "direction": true,
+	"style": true, "classdef": true, "class": true, "linkstyle": true, "click": true,
+	"call": true, "href": true, "default": true,
+}
+
+// mermaidIDs returns a valid and unique Mermaid ID for the path of every node
+// and subgraph of the diagram. The characters Mermaid does not accept are
+// replaced with _, keywords get a _ suffix, and IDs that are still taken get a
+// numeric suffix, assigned in drawing order.
+func (d *diagram) mermaidIDs() map[string]string {
+	ids := make(map[string]string)
+	taken := make(map[string]bool)
+	assign := func(path string) {
+		id := _mermaidIDRegexp.ReplaceAllString(path, "_")
+		if _mermaidKeywords[strings.ToLower(id)] {
+			id += "_"
+		}
+		for base, n := id, 2; taken[id]; n++ {
+			id = fmt.Sprintf("%s_%d", base, n)
+		}
+		taken[id] = true
+		ids[path] = id
+	}
+
+	var walk func(cl *cluster)
+	walk = func(cl *cluster) {
+		for _, n := range cl.nodes {
+			assign(n.path)
+		}
+		for i := range cl.clusters {
+			assign(cl.clusters[i].path)
+			walk(&cl.clusters[i])
+		}
+	}
+	walk(&d.cluster)
+	return ids
+}
+
+// mermaidText escapes text for use in a quoted Mermaid label.
+func mermaidText(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
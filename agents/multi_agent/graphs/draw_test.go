@@ -0,0 +1,127 @@
+package graphs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDrawGraph(t *testing.T) *CompiledGraph {
+	t.Helper()
+
+	g := NewGraph()
+	require.NoError(t, g.AddNode("agent", constNode("done", true)))
+	require.NoError(t, g.AddNode("tools", constNode("tools", 1), InterruptAfter()))
+	require.NoError(t, g.SetEntryPoint("agent"))
+	require.NoError(t, g.AddConditionalEdges("agent", func(context.Context, State) (string, error) {
+		return "finish", nil
+	}, map[string]string{"continue": "tools", "finish": END}))
+	require.NoError(t, g.AddEdge("tools", "agent"))
+
+	c, err := g.Compile(WithCheckpointer(NewMemoryCheckpointer()))
+	require.NoError(t, err)
+	return c
+}
+
+func TestDrawMermaid(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `flowchart TD
+	__start__(["__start__"])
+	agent["agent"]
+	tools["tools (interrupt after)"]
+	__end__(["__end__"])
+	__start__ --> agent
+	agent -.->|"continue"| tools
+	agent -.->|"finish"| __end__
+	tools --> agent
+`, newDrawGraph(t).DrawMermaid())
+}
+
+func TestDrawMermaidIDs(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph()
+	for _, name := range []string{"a-b", "a_b", "end"} {
+		require.NoError(t, g.AddNode(name, constNode(name, 1)))
+	}
+	require.NoError(t, g.SetEntryPoint("a-b"))
+	require.NoError(t, g.AddEdge("a-b", "a_b"))
+	require.NoError(t, g.AddEdge("a_b", "end"))
+	require.NoError(t, g.SetFinishPoint("end"))
+	c, err := g.Compile()
+	require.NoError(t, err)
+
+	assert.Equal(t, `flowchart TD
+	__start__(["__start__"])
+	a_b["a-b"]
+	a_b_2["a_b"]
+	end_["end"]
+	__end__(["__end__"])
+	__start__ --> a_b
+	a_b --> a_b_2
+	a_b_2 --> end_
+	end_ --> __end__
+`, c.DrawMermaid())
+}
+
+func TestDrawDOT(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `digraph {
+	compound=true;
+	"__start__" [label="__start__", shape=oval];
+	"agent" [label="agent", shape=box];
+	"tools" [label="tools (interrupt after)", shape=box];
+	"__end__" [label="__end__", shape=oval];
+	"__start__" -> "agent";
+	"agent" -> "tools" [style=dashed, label="continue"];
+	"agent" -> "__end__" [style=dashed, label="finish"];
+	"tools" -> "agent";
+}
+`, newDrawGraph(t).DrawDOT())
+}
+
+func TestDrawWithHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := newDrawGraph(t)
+	_, err := c.Invoke(ctx, nil, WithThreadID("thread"))
+	require.NoError(t, err)
+	history, err := c.History(ctx, "thread")
+	require.NoError(t, err)
+
+	mermaid := c.DrawMermaid(WithHistory(history))
+	assert.Contains(t, mermaid, "linkStyle 0,2 stroke:#2e7d32,stroke-width:3px\n")
+	assert.Contains(t, mermaid, "class agent done\n")
+	assert.NotContains(t, mermaid, "class tools")
+
+	dot := c.DrawDOT(WithHistory(history))
+	assert.Contains(t, dot, `"agent" -> "__end__" [style=dashed, label="finish", color="#2e7d32", penwidth=3];`)
+	assert.Contains(t, dot, `"agent" [label="agent", shape=box, style=filled, fillcolor="#c8e6c9"];`)
+}
+
+func TestDrawSubgraph(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := newTeamGraph(t, NewMemoryCheckpointer(), InterruptBefore())
+	_, err := c.Invoke(ctx, nil, WithThreadID("thread"))
+	require.ErrorIs(t, err, ErrInterrupted)
+	history, err := c.History(ctx, "thread")
+	require.NoError(t, err)
+
+	mermaid := c.DrawMermaid(WithHistory(history))
+	assert.Contains(t, mermaid, "\tsubgraph team [\"team\"]\n\t\tteam___start__([\"__start__\"])\n")
+	assert.Contains(t, mermaid, "\t\tteam_tools[\"tools (interrupt before)\"]\n")
+	assert.Contains(t, mermaid, "\tplan --> team\n")
+	assert.Contains(t, mermaid, "class team interrupted\n")
+
+	dot := c.DrawDOT()
+	assert.Contains(t, dot, "\tsubgraph \"cluster_team\" {\n\t\tlabel=\"team\";\n")
+	assert.Contains(t, dot, `"plan" -> "team/__start__" [lhead="cluster_team"];`)
+	assert.Contains(t, dot, `"team/__end__" -> "report" [ltail="cluster_team"];`)
+}
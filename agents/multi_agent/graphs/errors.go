@@ -0,0 +1,65 @@
+package graphs
+
+import "errors"
+
+var (
+	// ErrInvalidNodeName is returned when a node is added with an empty or
+	// reserved name.
+	ErrInvalidNodeName = errors.New("invalid node name")
+	// ErrDuplicateNode is returned when a node is added twice.
+	ErrDuplicateNode = errors.New("node already exists")
+	// ErrInvalidEdge is returned when an edge leaves END, enters START or has
+	// no router.
+	ErrInvalidEdge = errors.New("invalid edge")
+	// ErrInvalidChannel is returned when a channel is added with an empty key,
+	// a nil reducer or for a key that already has a channel.
+	ErrInvalidChannel = errors.New("invalid channel")
+
+	// ErrNoEntryPoint is returned by Compile if no edge leaves START.
+	ErrNoEntryPoint = errors.New("graph has no entry point")
+	// ErrDanglingEdge is returned by Compile if an edge points to or from a node
+	// that does not exist.
+	ErrDanglingEdge = errors.New("edge references unknown node")
+	// ErrUnreachableNode is returned by Compile if a node can not be reached
+	// from START.
+	ErrUnreachableNode = errors.New("node is unreachable")
+	// ErrDeadEnd is returned by Compile if a node has no outgoing edges.
+	ErrDeadEnd = errors.New("node has no outgoing edges")
+
+	// ErrUnknownNode is returned during a run if a router picks a node that does
+	// not exist.
+	ErrUnknownNode = errors.New("unknown node")
+	// ErrInvalidUpdate is returned during a run if a reducer can not merge an
+	// update into the state.
+	ErrInvalidUpdate = errors.New("invalid state update")
+	// ErrRecursionLimit is matched by the RecursionLimitError returned from
+	// runs that did not finish within their recursion limit.
+	ErrRecursionLimit = errors.New("graph recursion limit reached")
+	// ErrNodeTimeout is returned during a run if a node runs longer than its
+	// timeout.
+	ErrNodeTimeout = errors.New("node timed out")
+
+	// ErrNoThreadID is returned when a graph with a checkpointer is run without
+	// a thread ID.
+	ErrNoThreadID = errors.New("no thread ID given")
+	// ErrNoCheckpointer is returned when a run is resumed on a graph compiled
+	// without a checkpointer, or by Compile if a node has an interrupt and no
+	// checkpointer is given.
+	ErrNoCheckpointer = errors.New("graph has no checkpointer")
+	// ErrCheckpointNotFound is returned by a Checkpointer if there is no
+	// checkpoint for the requested thread or step.
+	ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+	// ErrInterrupted is matched by the Interrupt returned from runs that paused
+	// at an interrupt point.
+	ErrInterrupted = errors.New("graph run interrupted")
+	// ErrDecisionRequired is returned when an interrupted run is resumed
+	// without a decision.
+	ErrDecisionRequired = errors.New("interrupted run needs a decision to resume")
+	// ErrInvalidDecision is returned when a run is resumed with a decision that
+	// is malformed or while no interrupt is pending.
+	ErrInvalidDecision = errors.New("invalid decision")
+	// ErrInvalidFork is returned by Fork if the new thread already has
+	// checkpoints.
+	ErrInvalidFork = errors.New("invalid fork")
+)
@@ -1,100 +1,360 @@
 package graphs
 
-
 import (
 	"context"
-	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
 	"sync"
+	"time"
+)
 
-	"github.com/tmc/langchaingo/schema"
-	"github.com/tmc/langchaingo/tools"
+const (
+	// START is the virtual node every run begins from.
+	START = "__start__"
+	// END is the virtual node that finishes a run once reached.
+	END = "__end__"
 )
 
+// NodeFunc is the work done by a node. It receives a copy of the current state
+// and returns the keys it wants to change.
+type NodeFunc func(ctx context.Context, state State) (State, error)
+
+// RouterFunc decides which branch of a conditional edge is followed. The
+// returned label is looked up in the path map given to AddConditionalEdges, or
+// used as the name of the next node if there is no path map.
+type RouterFunc func(ctx context.Context, state State) (string, error)
+
 // Node represents a single node in the graph.
 type Node struct {
-	ID       string
-	Children []*Node
-	Action   schema.AgentAction
+	Name string
+	Func NodeFunc
+
+	// InterruptBefore pauses runs before the node runs.
+	InterruptBefore bool
+	// InterruptAfter pauses runs after the node ran.
+	InterruptAfter bool
+	// OnReject is run instead of Func when an interrupt before the node is
+	// rejected. If nil a rejected node does not update the state.
+	OnReject RejectFunc
+
+	// Timeout limits how long the node can run. Zero means no limit. At the
+	// deadline the context of the node is canceled and the node fails with
+	// ErrNodeTimeout, even if it ignores its context and keeps running.
+	Timeout time.Duration
+	// RetryPolicy tells how the node is retried when it fails. If nil the
+	// node is not retried.
+	RetryPolicy *RetryPolicy
+
+	subgraph *subgraph
+}
+
+type branch struct {
+	router RouterFunc
+	paths  map[string]string
+}
+
+// destinations returns the nodes the branch can lead to, or nil if any node
+// could be picked.
+func (b *branch) destinations() []string {
+	if b.paths == nil {
+		return nil
+	}
+	dests := make([]string, 0, len(b.paths))
+	for _, to := range b.paths {
+		dests = append(dests, to)
+	}
+	sort.Strings(dests)
+	return dests
+}
+
+// join is an edge that is only followed once all of its sources have run.
+type join struct {
+	from []string
+	to   string
 }
 
-// Graph represents a directional graph.
+// Graph represents a directional graph. Nodes and edges are added to the graph
+// and it is turned into a runnable CompiledGraph with Compile.
 type Graph struct {
-	Nodes map[string]*Node
-	mu    sync.Mutex
+	nodes    map[string]*Node
+	edges    map[string][]string
+	branches map[string]*branch
+	joins    []join
+	channels map[string]Reducer
+	mu       sync.Mutex
 }
 
 // NewGraph creates a new Graph.
 func NewGraph() *Graph {
 	return &Graph{
-		Nodes: make(map[string]*Node),
+		nodes:    make(map[string]*Node),
+		edges:    make(map[string][]string),
+		branches: make(map[string]*branch),
+		channels: make(map[string]Reducer),
 	}
 }
 
 // AddNode adds a new node to the graph.
-func (g *Graph) AddNode(id string) {
+func (g *Graph) AddNode(name string, fn NodeFunc, opts ...NodeOption) error {
+	if name == "" || name == START || name == END {
+		return fmt.Errorf("%w: %q", ErrInvalidNodeName, name)
+	}
+	if fn == nil {
+		return fmt.Errorf("%w: node %q has no function", ErrInvalidNodeName, name)
+	}
+
 	g.mu.Lock()
 	defer g.mu.Unlock()
-	if _, exists := g.Nodes[id]; !exists {
-		g.Nodes[id] = &Node{ID: id}
+
+	if _, exists := g.nodes[name]; exists {
+		return fmt.Errorf("%w: %q", ErrDuplicateNode, name)
+	}
+	node := &Node{Name: name, Func: fn}
+	for _, opt := range opts {
+		opt(node)
 	}
+	g.nodes[name] = node
+	return nil
 }
 
-// AddEdge adds a directional edge between two nodes.
-func (g *Graph) AddEdge(fromID, toID string) error {
+// AddEdge adds a directional edge between two nodes. The nodes do not have to
+// exist yet; edges are checked when the graph is compiled. A node with several
+// outgoing edges fans out: all of its successors run concurrently in the next
+// step.
+func (g *Graph) AddEdge(from, to string) error {
+	if from == END || to == START {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidEdge, from, to)
+	}
+
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	fromNode, fromExists := g.Nodes[fromID]
-	toNode, toExists := g.Nodes[toID]
+	g.edges[from] = append(g.edges[from], to)
+	return nil
+}
 
-	if !fromExists || !toExists {
-		return errors.New("one or both nodes do not exist")
+// AddJoinEdge adds an edge that waits for several nodes. The node to runs in
+// the step after the last of the from nodes has run, no matter in which steps
+// the other from nodes ran. It is used to fan in branches of unequal length.
+func (g *Graph) AddJoinEdge(from []string, to string) error {
+	if len(from) == 0 || to == START {
+		return fmt.Errorf("%w: %v -> %s", ErrInvalidEdge, from, to)
+	}
+	for _, f := range from {
+		if f == START || f == END {
+			return fmt.Errorf("%w: %v -> %s", ErrInvalidEdge, from, to)
+		}
 	}
 
-	fromNode.Children = append(fromNode.Children, toNode)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.joins = append(g.joins, join{from: append([]string(nil), from...), to: to})
 	return nil
 }
 
-// Execute executes the graph starting from the given node ID.
-func (g *Graph) Execute(ctx context.Context, startID string, agent schema.Agent) error {
+// AddConditionalEdges adds edges leaving from that are chosen at run time by
+// router. The label returned by the router is mapped to a node with paths. If
+// paths is nil the label must be the name of a node, or END.
+func (g *Graph) AddConditionalEdges(from string, router RouterFunc, paths map[string]string) error {
+	if from == END || router == nil {
+		return fmt.Errorf("%w: conditional edge from %s", ErrInvalidEdge, from)
+	}
+
 	g.mu.Lock()
-	startNode, exists := g.Nodes[startID]
-	g.mu.Unlock()
+	defer g.mu.Unlock()
 
-	if !exists {
-		return errors.New("start node does not exist")
+	if _, exists := g.branches[from]; exists {
+		return fmt.Errorf("%w: %s already has conditional edges", ErrInvalidEdge, from)
 	}
+	var p map[string]string
+	if paths != nil {
+		p = make(map[string]string, len(paths))
+		for label, to := range paths {
+			p[label] = to
+		}
+	}
+	g.branches[from] = &branch{router: router, paths: p}
+	return nil
+}
+
+// SetEntryPoint adds an edge from START to the given node.
+func (g *Graph) SetEntryPoint(name string) error {
+	return g.AddEdge(START, name)
+}
 
-	return g.executeNode(ctx, startNode, agent)
+// SetFinishPoint adds an edge from the given node to END.
+func (g *Graph) SetFinishPoint(name string) error {
+	return g.AddEdge(name, END)
 }
 
-func (g *Graph) executeNode(ctx context.Context, node *Node, agent schema.Agent) error {
-	// Execute the action associated with the node
-	_, _, err := agent.Plan(ctx, nil, map[string]string{"input": node.ID})
+// Compile validates the graph and returns a runnable snapshot of it. Later
+// changes to the graph do not affect the compiled graph.
+func (g *Graph) Compile(opts ...CompileOption) (*CompiledGraph, error) {
+	var o compileOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	c := &CompiledGraph{
+		checkpointer: o.checkpointer,
+		nodes:        maps.Clone(g.nodes),
+		edges:        make(map[string][]string, len(g.edges)),
+		branches:     maps.Clone(g.branches),
+		joins:        append([]join(nil), g.joins...),
+		channels:     maps.Clone(g.channels),
+	}
+	for from, to := range g.edges {
+		c.edges[from] = append([]string(nil), to...)
+	}
+
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Execute compiles the graph and runs it with input as the initial state.
+func (g *Graph) Execute(ctx context.Context, input State, opts ...RunOption) (State, error) {
+	c, err := g.Compile()
 	if err != nil {
+		return nil, err
+	}
+	return c.Invoke(ctx, input, opts...)
+}
+
+// CompiledGraph is a validated, immutable graph that can be run.
+type CompiledGraph struct {
+	nodes    map[string]*Node
+	edges    map[string][]string
+	branches map[string]*branch
+	joins    []join
+	channels map[string]Reducer
+
+	checkpointer Checkpointer
+}
+
+// NodeNames returns the names of the nodes of the graph in sorted order.
+func (c *CompiledGraph) NodeNames() []string {
+	names := make([]string, 0, len(c.nodes))
+	for name := range c.nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *CompiledGraph) validate() error {
+	if len(c.edges[START]) == 0 && c.branches[START] == nil {
+		return ErrNoEntryPoint
+	}
+	if err := c.validateEdges(); err != nil {
 		return err
 	}
 
-	// Execute child nodes
-	for _, child := range node.Children {
-		if err := g.executeNode(ctx, child, agent); err != nil {
-			return err
+	reachable := c.reachable()
+	for _, name := range c.NodeNames() {
+		if !reachable[name] {
+			return fmt.Errorf("%w: %q", ErrUnreachableNode, name)
+		}
+		node := c.nodes[name]
+		if (node.InterruptBefore || node.InterruptAfter) && c.checkpointer == nil {
+			return fmt.Errorf("%w: node %q has an interrupt", ErrNoCheckpointer, name)
+		}
+		if node.subgraph != nil && node.subgraph.graph.hasInterrupts() && c.checkpointer == nil {
+			return fmt.Errorf("%w: subgraph %q has an interrupt", ErrNoCheckpointer, name)
 		}
 	}
 	return nil
 }
 
-// GetTools returns the tools available to the graph.
-func (g *Graph) GetTools() []tools.Tool {
+func (c *CompiledGraph) validateEdges() error {
+	isNode := func(name string) bool {
+		_, ok := c.nodes[name]
+		return ok
+	}
 
-	g.mu.Lock()
-	defer g.mu.Unlock()
+	for _, from := range sortedKeys(c.edges) {
+		tos := c.edges[from]
+		if from != START && !isNode(from) {
+			return fmt.Errorf("%w: %s -> %v", ErrDanglingEdge, from, tos)
+		}
+		for _, to := range tos {
+			if to != END && !isNode(to) {
+				return fmt.Errorf("%w: %s -> %s", ErrDanglingEdge, from, to)
+			}
+		}
+	}
+	for _, from := range sortedKeys(c.branches) {
+		b := c.branches[from]
+		if from != START && !isNode(from) {
+			return fmt.Errorf("%w: conditional edge from %s", ErrDanglingEdge, from)
+		}
+		for _, to := range b.destinations() {
+			if to != END && !isNode(to) {
+				return fmt.Errorf("%w: %s -> %s", ErrDanglingEdge, from, to)
+			}
+		}
+	}
+
+	for _, j := range c.joins {
+		for _, from := range j.from {
+			if !isNode(from) {
+				return fmt.Errorf("%w: %v -> %s", ErrDanglingEdge, j.from, j.to)
+			}
+		}
+		if j.to != END && !isNode(j.to) {
+			return fmt.Errorf("%w: %v -> %s", ErrDanglingEdge, j.from, j.to)
+		}
+	}
+
+	for _, name := range c.NodeNames() {
+		if len(c.successors(name)) == 0 {
+			return fmt.Errorf("%w: %q", ErrDeadEnd, name)
+		}
+	}
+	return nil
+}
+
+// successors returns the nodes that can follow from. If from has conditional
+// edges without a path map every node is a possible successor.
+func (c *CompiledGraph) successors(from string) []string {
+	next := append([]string(nil), c.edges[from]...)
+	if b := c.branches[from]; b != nil {
+		dests := b.destinations()
+		if dests == nil {
+			dests = append(c.NodeNames(), END)
+		}
+		next = append(next, dests...)
+	}
+	for _, j := range c.joins {
+		if slices.Contains(j.from, from) {
+			next = append(next, j.to)
+		}
+	}
+	return next
+}
+
+// reachable returns the set of nodes that can be reached from START.
+func (c *CompiledGraph) reachable() map[string]bool {
+	seen := map[string]bool{START: true}
+	queue := []string{START}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
 
-	var allTools []tools.Tool
-	for _, node := range g.Nodes {
-		if node.Agent != nil && node.Agent.Tools != nil {
-			allTools = append(allTools, node.Agent.Tools...)
+		next := c.successors(from)
+		for _, to := range next {
+			if !seen[to] {
+				seen[to] = true
+				queue = append(queue, to)
+			}
 		}
 	}
-	return allTools
+	return seen
 }
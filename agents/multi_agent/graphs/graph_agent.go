@@ -1,4 +1,4 @@
-package multi_agent
+package graphs
 
 import (
 	"context"
@@ -8,65 +8,62 @@ import (
 	"github.com/tmc/langchaingo/tools"
 )
 
-// GraphAgent is an implementation of the Agent interface with graph features.
+// GraphAgent is an implementation of the Agent interface that runs a graph.
+// The inputs of the agent are the initial state of the run and the output keys
+// are read from the final state.
 type GraphAgent struct {
-	Graph *Graph
-	Tools []tools.Tool
+	Graph      *Graph
+	Tools      []tools.Tool
+	InputKeys  []string
+	OutputKeys []string
 }
 
 var _ agents.Agent = (*GraphAgent)(nil)
 
 // NewGraphAgent creates a new GraphAgent.
-func NewGraphAgent() *GraphAgent {
+func NewGraphAgent(graph *Graph, inputKeys, outputKeys []string) *GraphAgent {
 	return &GraphAgent{
-		Graph: NewGraph(),
-		Tools: []tools.Tool{},
+		Graph:      graph,
+		Tools:      []tools.Tool{},
+		InputKeys:  inputKeys,
+		OutputKeys: outputKeys,
 	}
 }
 
-// Plan decides what action to take or returns the final result of the input.
+// Plan runs the graph and returns the final result of the input.
 func (a *GraphAgent) Plan(
 	ctx context.Context,
-	intermediateSteps []schema.AgentStep,
+	_ []schema.AgentStep,
 	inputs map[string]string,
 ) ([]schema.AgentAction, *schema.AgentFinish, error) {
-	// Analyze the current state of the graph
-	currentState := a.Graph.GetCurrentState()
-
-	// Check if we've reached a terminal state
-	if a.Graph.IsTerminalState(currentState) {
-		return nil, &schema.AgentFinish{
-			ReturnValues: map[string]string{"result": currentState.String()},
-			Log:          "Reached terminal state",
-		}, nil
+	input := make(State, len(inputs))
+	for key, value := range inputs {
+		input[key] = value
 	}
 
-	// Determine next actions based on graph traversal
-	actions := make([]schema.AgentAction, 0)
-	nextStates := a.Graph.GetNextStates(currentState)
-
-	for _, state := range nextStates {
-		action := schema.AgentAction{
-			Tool: "graph_traversal",
-			ToolInput: map[string]string{
-				"target_state": state.String(),
-			},
-			Log: "Transitioning to next state",
-		}
-		actions = append(actions, action)
+	state, err := a.Graph.Execute(ctx, input)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return actions, nil, nil
+	returnValues := make(map[string]any, len(a.OutputKeys))
+	for _, key := range a.OutputKeys {
+		returnValues[key] = state[key]
+	}
+	return nil, &schema.AgentFinish{
+		ReturnValues: returnValues,
+		Log:          "Reached end of graph",
+	}, nil
 }
 
 // GetInputKeys returns the input keys for the agent.
 func (a *GraphAgent) GetInputKeys() []string {
-	return []string{"initial_state", "goal_state", "constraints"}
+	return a.InputKeys
 }
 
 // GetOutputKeys returns the output keys for the agent.
 func (a *GraphAgent) GetOutputKeys() []string {
-	return []string{"final_state", "path", "metrics"}
+	return a.OutputKeys
 }
 
 // GetTools returns the tools available to the agent.
@@ -0,0 +1,181 @@
+package graphs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func constNode(key string, value any) NodeFunc {
+	return func(context.Context, State) (State, error) {
+		return State{key: value}, nil
+	}
+}
+
+func TestGraphExecute(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph()
+	require.NoError(t, g.AddNode("a", constNode("a", 1)))
+	require.NoError(t, g.AddNode("b", func(_ context.Context, s State) (State, error) {
+		a, _ := Get[int](s, "a")
+		return State{"b": a + 1}, nil
+	}))
+	require.NoError(t, g.SetEntryPoint("a"))
+	require.NoError(t, g.AddEdge("a", "b"))
+	require.NoError(t, g.SetFinishPoint("b"))
+
+	state, err := g.Execute(context.Background(), State{"input": "x"})
+	require.NoError(t, err)
+	assert.Equal(t, State{"input": "x", "a": 1, "b": 2}, state)
+}
+
+func TestGraphConditionalEdges(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph()
+	require.NoError(t, g.AddNode("count", func(_ context.Context, s State) (State, error) {
+		n, _ := Get[int](s, "n")
+		return State{"n": n + 1}, nil
+	}))
+	require.NoError(t, g.SetEntryPoint("count"))
+	require.NoError(t, g.AddConditionalEdges("count", func(_ context.Context, s State) (string, error) {
+		if n, _ := Get[int](s, "n"); n < 3 {
+			return "again", nil
+		}
+		return "done", nil
+	}, map[string]string{"again": "count", "done": END}))
+
+	c, err := g.Compile()
+	require.NoError(t, err)
+	state, err := c.Invoke(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, State{"n": 3}, state)
+}
+
+func TestGraphRouterUnknownNode(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph()
+	require.NoError(t, g.AddNode("a", constNode("a", 1)))
+	require.NoError(t, g.SetEntryPoint("a"))
+	require.NoError(t, g.AddConditionalEdges("a", func(context.Context, State) (string, error) {
+		return "nowhere", nil
+	}, nil))
+
+	_, err := g.Execute(context.Background(), nil)
+	require.ErrorIs(t, err, ErrUnknownNode)
+}
+
+func TestGraphNodeError(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+	g := NewGraph()
+	require.NoError(t, g.AddNode("a", constNode("a", 1)))
+	require.NoError(t, g.AddNode("b", func(context.Context, State) (State, error) {
+		return nil, errBoom
+	}))
+	require.NoError(t, g.SetEntryPoint("a"))
+	require.NoError(t, g.AddEdge("a", "b"))
+	require.NoError(t, g.SetFinishPoint("b"))
+
+	state, err := g.Execute(context.Background(), nil)
+	require.ErrorIs(t, err, errBoom)
+	assert.Equal(t, State{"a": 1}, state)
+}
+
+func TestGraphCompileErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		build func(g *Graph)
+		err   error
+	}{
+		{
+			name:  "no entry point",
+			build: func(g *Graph) { _ = g.SetFinishPoint("a") },
+			err:   ErrNoEntryPoint,
+		},
+		{
+			name: "dangling edge",
+			build: func(g *Graph) {
+				_ = g.SetEntryPoint("a")
+				_ = g.AddEdge("a", "missing")
+			},
+			err: ErrDanglingEdge,
+		},
+		{
+			name: "dangling conditional edge",
+			build: func(g *Graph) {
+				_ = g.SetEntryPoint("a")
+				_ = g.AddConditionalEdges("a", func(context.Context, State) (string, error) {
+					return "x", nil
+				}, map[string]string{"x": "missing"})
+			},
+			err: ErrDanglingEdge,
+		},
+		{
+			name: "unreachable node",
+			build: func(g *Graph) {
+				_ = g.SetEntryPoint("a")
+				_ = g.SetFinishPoint("a")
+				_ = g.AddNode("b", constNode("b", 1))
+				_ = g.SetFinishPoint("b")
+			},
+			err: ErrUnreachableNode,
+		},
+		{
+			name:  "dead end",
+			build: func(g *Graph) { _ = g.SetEntryPoint("a") },
+			err:   ErrDeadEnd,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGraph()
+			require.NoError(t, g.AddNode("a", constNode("a", 1)))
+			tc.build(g)
+			_, err := g.Compile()
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestGraphBuilderErrors(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph()
+	require.ErrorIs(t, g.AddNode(START, constNode("a", 1)), ErrInvalidNodeName)
+	require.ErrorIs(t, g.AddNode("a", nil), ErrInvalidNodeName)
+	require.NoError(t, g.AddNode("a", constNode("a", 1)))
+	require.ErrorIs(t, g.AddNode("a", constNode("a", 1)), ErrDuplicateNode)
+	require.ErrorIs(t, g.AddEdge(END, "a"), ErrInvalidEdge)
+	require.ErrorIs(t, g.AddEdge("a", START), ErrInvalidEdge)
+	require.ErrorIs(t, g.AddConditionalEdges("a", nil, nil), ErrInvalidEdge)
+}
+
+func TestGraphAgent(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph()
+	require.NoError(t, g.AddNode("echo", func(_ context.Context, s State) (State, error) {
+		input, _ := Get[string](s, "input")
+		return State{"output": "echo: " + input}, nil
+	}))
+	require.NoError(t, g.SetEntryPoint("echo"))
+	require.NoError(t, g.SetFinishPoint("echo"))
+
+	agent := NewGraphAgent(g, []string{"input"}, []string{"output"})
+	actions, finish, err := agent.Plan(context.Background(), nil, map[string]string{"input": "hi"})
+	require.NoError(t, err)
+	assert.Empty(t, actions)
+	assert.Equal(t, map[string]any{"output": "echo: hi"}, finish.ReturnValues)
+}
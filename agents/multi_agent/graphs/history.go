@@ -0,0 +1,141 @@
+package graphs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Snapshot is the view of a thread at one step of its history.
+type Snapshot struct {
+	ThreadID string
+	Step     int
+	// State is the state after the step.
+	State State
+	// Next holds the nodes that run in the following step.
+	Next []string
+	// Writes holds the update each node wrote in the step.
+	Writes map[string]State
+	// AgentSteps holds the tool calls made up to the step, read from StepsKey.
+	AgentSteps []schema.AgentStep
+	// Interrupt is set if the run was paused at the step.
+	Interrupt *Interrupt
+	// ForkedFrom is set if the step was copied from another thread by Fork.
+	ForkedFrom *CheckpointRef
+	CreatedAt  time.Time
+}
+
+func newSnapshot(cp Checkpoint) Snapshot {
+	snapshot := Snapshot{
+		ThreadID:   cp.ThreadID,
+		Step:       cp.Step,
+		State:      cp.State,
+		Next:       cp.Next,
+		Writes:     cp.Writes,
+		Interrupt:  cp.Interrupt,
+		ForkedFrom: cp.ForkedFrom,
+		CreatedAt:  cp.CreatedAt,
+	}
+	snapshot.AgentSteps, _ = Get[[]schema.AgentStep](cp.State, StepsKey)
+	if cp.Interrupt != nil {
+		r := &run{state: cp.State, pendingWrites: writesFromNodes(cp.PendingWrites)}
+		snapshot.Interrupt = r.describe(cp.Interrupt)
+	}
+	return snapshot
+}
+
+// History returns the snapshots of every step of a thread, oldest first.
+func (c *CompiledGraph) History(ctx context.Context, threadID string) ([]Snapshot, error) {
+	if c.checkpointer == nil {
+		return nil, ErrNoCheckpointer
+	}
+	cps, err := c.checkpointer.List(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make([]Snapshot, len(cps))
+	for i, cp := range cps {
+		snapshots[i] = newSnapshot(cp)
+		if err := c.describeSubgraphs(ctx, snapshots[i].Interrupt); err != nil {
+			return nil, err
+		}
+	}
+	return snapshots, nil
+}
+
+// GetState returns the snapshot of a thread at the given step.
+func (c *CompiledGraph) GetState(ctx context.Context, threadID string, step int) (Snapshot, error) {
+	if c.checkpointer == nil {
+		return Snapshot{}, ErrNoCheckpointer
+	}
+	cp, err := c.checkpointer.Get(ctx, threadID, step)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snapshot := newSnapshot(cp)
+	if err := c.describeSubgraphs(ctx, snapshot.Interrupt); err != nil {
+		return Snapshot{}, err
+	}
+	return snapshot, nil
+}
+
+// Fork creates the thread newThreadID from the history of threadID up to the
+// given step. The keys of update are written over the state of that step,
+// without going through the channel reducers. Resume runs the new thread from
+// the forked step; the original thread is left untouched.
+func (c *CompiledGraph) Fork(
+	ctx context.Context,
+	threadID string,
+	step int,
+	newThreadID string,
+	update State,
+) (Snapshot, error) {
+	if c.checkpointer == nil {
+		return Snapshot{}, ErrNoCheckpointer
+	}
+	if newThreadID == "" {
+		return Snapshot{}, ErrNoThreadID
+	}
+	if existing, err := c.checkpointer.List(ctx, newThreadID); err != nil || len(existing) > 0 {
+		if err != nil {
+			return Snapshot{}, err
+		}
+		return Snapshot{}, fmt.Errorf("%w: thread %q already exists", ErrInvalidFork, newThreadID)
+	}
+
+	cps, err := c.checkpointer.List(ctx, threadID)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	last := slices.IndexFunc(cps, func(cp Checkpoint) bool { return cp.Step == step })
+	if last < 0 {
+		return Snapshot{}, fmt.Errorf("%w: thread %q step %d", ErrCheckpointNotFound, threadID, step)
+	}
+
+	for _, cp := range cps[:last] {
+		if err := c.checkpointer.Put(ctx, moveCheckpoint(cp, newThreadID)); err != nil {
+			return Snapshot{}, err
+		}
+	}
+	forked := moveCheckpoint(cps[last], newThreadID)
+	forked.State = forked.State.Merge(update)
+	forked.ForkedFrom = &CheckpointRef{ThreadID: threadID, Step: step}
+	forked.CreatedAt = time.Now()
+	if err := c.checkpointer.Put(ctx, forked); err != nil {
+		return Snapshot{}, err
+	}
+	return newSnapshot(forked), nil
+}
+
+// moveCheckpoint returns a copy of cp belonging to the given thread.
+func moveCheckpoint(cp Checkpoint, threadID string) Checkpoint {
+	moved := cp.clone()
+	moved.ThreadID = threadID
+	if moved.Interrupt != nil {
+		moved.Interrupt.ThreadID = threadID
+	}
+	return moved
+}
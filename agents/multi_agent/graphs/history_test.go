@@ -0,0 +1,100 @@
+package graphs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c := newReviewGraph(t, NewMemoryCheckpointer())
+	_, err := c.Invoke(ctx, State{"input": "x"}, WithThreadID("thread"))
+	require.NoError(t, err)
+
+	history, err := c.History(ctx, "thread")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+
+	assert.Equal(t, 0, history[0].Step)
+	assert.Equal(t, []string{"agent"}, history[0].Next)
+	assert.Equal(t, map[string]State{START: {"input": "x"}}, history[0].Writes)
+
+	assert.Equal(t, []string{"tools"}, history[1].Next)
+	assert.Contains(t, history[1].Writes, "agent")
+	assert.Empty(t, history[1].AgentSteps)
+
+	assert.Empty(t, history[2].Next)
+	assert.Equal(t, []schema.AgentStep{
+		{Action: schema.AgentAction{Tool: "upper", ToolInput: "hello"}, Observation: "HELLO"},
+	}, history[2].AgentSteps)
+
+	snapshot, err := c.GetState(ctx, "thread", 1)
+	require.NoError(t, err)
+	assert.Equal(t, history[1].State, snapshot.State)
+
+	_, err = c.GetState(ctx, "thread", 7)
+	require.ErrorIs(t, err, ErrCheckpointNotFound)
+}
+
+func TestHistoryShowsInterrupts(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c := newReviewGraph(t, NewMemoryCheckpointer(), InterruptBefore())
+	_, err := c.Invoke(ctx, nil, WithThreadID("thread"))
+	require.ErrorIs(t, err, ErrInterrupted)
+
+	history, err := c.History(ctx, "thread")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.NotNil(t, history[1].Interrupt)
+	assert.Equal(t, []schema.AgentAction{{Tool: "upper", ToolInput: "hello"}}, history[1].Interrupt.Actions)
+}
+
+func TestFork(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c := newReviewGraph(t, NewMemoryCheckpointer())
+	_, err := c.Invoke(ctx, nil, WithThreadID("thread"))
+	require.NoError(t, err)
+
+	snapshot, err := c.Fork(ctx, "thread", 1, "fork", State{
+		ActionsKey: []schema.AgentAction{{Tool: "upper", ToolInput: "forked"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "fork", snapshot.ThreadID)
+	assert.Equal(t, &CheckpointRef{ThreadID: "thread", Step: 1}, snapshot.ForkedFrom)
+	assert.Equal(t, []string{"tools"}, snapshot.Next)
+
+	state, err := c.Resume(ctx, "fork")
+	require.NoError(t, err)
+	assert.Equal(t, []schema.AgentStep{
+		{Action: schema.AgentAction{Tool: "upper", ToolInput: "forked"}, Observation: "FORKED"},
+	}, steps(t, state))
+
+	forkHistory, err := c.History(ctx, "fork")
+	require.NoError(t, err)
+	require.Len(t, forkHistory, 3)
+	assert.Nil(t, forkHistory[0].ForkedFrom)
+	assert.NotNil(t, forkHistory[1].ForkedFrom)
+	assert.Nil(t, forkHistory[2].ForkedFrom)
+
+	original, err := c.GetState(ctx, "thread", 2)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", original.AgentSteps[0].Action.ToolInput)
+
+	_, err = c.Fork(ctx, "thread", 1, "fork", nil)
+	require.ErrorIs(t, err, ErrInvalidFork)
+	_, err = c.Fork(ctx, "thread", 9, "other", nil)
+	require.ErrorIs(t, err, ErrCheckpointNotFound)
+	history, err := c.History(ctx, "other")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
@@ -1,19 +1,38 @@
-package multi_agent
+package graphs
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"sync"
 
+	"github.com/google/uuid"
 	"github.com/tmc/langchaingo/agents"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/tools"
 )
 
 // HumanInTheLoopAgent is an implementation of the Agent interface with human-in-the-loop features.
+// Its actions are run by a tool node that is interrupted before it runs, so a
+// human can approve, reject or edit the tool calls first.
 type HumanInTheLoopAgent struct {
 	Graph *Graph
 	Tools []tools.Tool
+	// Checkpointer stores the run while it waits for a decision.
+	Checkpointer Checkpointer
+	// ThreadID is the thread the run is stored under.
+	ThreadID string
+	// Results holds the final state of the last run. The finished tool calls
+	// are stored under StepsKey.
+	Results State
+	// Interrupt is the interrupt the run waits on, if any.
+	Interrupt *Interrupt
+	// Err is the error of the last execution, if any.
+	Err error
+	// Feedback is the last feedback given by a human.
+	Feedback string
+
+	compiled *CompiledGraph
+	actions  []schema.AgentAction
 }
 
 var _ agents.Agent = (*HumanInTheLoopAgent)(nil)
@@ -21,8 +40,10 @@ var _ agents.Agent = (*HumanInTheLoopAgent)(nil)
 // NewHumanInTheLoopAgent creates a new HumanInTheLoopAgent.
 func NewHumanInTheLoopAgent() *HumanInTheLoopAgent {
 	return &HumanInTheLoopAgent{
-		Graph: NewGraph(),
-		Tools: []tools.Tool{},
+		Graph:        NewGraph(),
+		Tools:        []tools.Tool{},
+		Checkpointer: NewMemoryCheckpointer(),
+		ThreadID:     uuid.NewString(),
 	}
 }
 
@@ -81,77 +102,57 @@ func (a *HumanInTheLoopAgent) GetTools() []tools.Tool {
 
 // InitializeHumanInTheLoopActions initializes the human-in-the-loop actions for the agent.
 func (a *HumanInTheLoopAgent) InitializeHumanInTheLoopActions(actions []schema.AgentAction) {
-	for _, action := range actions {
-		node := &Node{
-			ID:      len(a.Graph.Nodes),
-			Value:   action,
-			State:   "initialized",
-			Actions: []interface{}{action},
-		}
-		a.Graph.AddNode(node)
-	}
+	g := NewGraph()
+	// Errors can only come from invalid names, which can not happen here;
+	// anything else surfaces when the graph is compiled.
+	_ = g.AddChannel(StepsKey, Append)
+	_ = g.AddNode("tools", ToolNode(a.Tools), InterruptBefore(), OnReject(RejectToolCalls))
+	_ = g.SetEntryPoint("tools")
+	_ = g.SetFinishPoint("tools")
+	a.Graph = g
+	a.actions = actions
 }
 
-// ExecuteHumanInTheLoopActions executes the human-in-the-loop actions for the agent.
+// ExecuteHumanInTheLoopActions executes the human-in-the-loop actions for the
+// agent. The run pauses before the tools are called and Interrupt is set; use
+// Decide or HumanFeedback to continue it.
 func (a *HumanInTheLoopAgent) ExecuteHumanInTheLoopActions() {
-	var wg sync.WaitGroup
-	for _, node := range a.Graph.Nodes {
-		wg.Add(1)
-		go func(n *Node) {
-			defer wg.Done()
-			for _, action := range n.Actions {
-				n.mu.Lock()
-				n.State = "executing"
-				n.mu.Unlock()
-
-				if agentAction, ok := action.(schema.AgentAction); ok {
-					n.mu.Lock()
-					// Execute the tool
-					for _, tool := range a.Tools {
-						if tool.Name() == agentAction.Tool {
-							result, err := tool.Call(agentAction.ToolInput)
-							if err == nil {
-								n.Result = result
-							} else {
-								n.Result = fmt.Sprintf("Error: %v", err)
-							}
-							break
-						}
-					}
-					n.State = "completed"
-					n.mu.Unlock()
-				}
-			}
-		}(node)
+	a.compiled, a.Err = a.Graph.Compile(WithCheckpointer(a.Checkpointer))
+	if a.Err != nil {
+		return
 	}
-	wg.Wait()
-	a.Graph.Execute()
+	a.setResult(a.compiled.Invoke(context.Background(), State{ActionsKey: a.actions}, WithThreadID(a.ThreadID)))
 }
 
-// HumanFeedback collects feedback from a human user.
-func (a *HumanInTheLoopAgent) HumanFeedback(ctx context.Context, feedback string) error {
-	// Process the feedback and update the graph
-	feedbackNode := &Node{
-		ID:    len(a.Graph.Nodes),
-		Value: feedback,
-		State: "feedback",
+// Decide continues the paused run with the decision of a human.
+func (a *HumanInTheLoopAgent) Decide(ctx context.Context, decision Decision) error {
+	if a.compiled == nil || a.Interrupt == nil {
+		return fmt.Errorf("%w: no pending interrupt", ErrInvalidDecision)
 	}
-	a.Graph.AddNode(feedbackNode)
-
-	// Update the state of related nodes based on feedback
-	for _, node := range a.Graph.Nodes {
-		if node.State == "completed" {
-			node.mu.Lock()
-			node.Feedback = feedback
-			// Potentially modify the node's state or trigger new actions based on feedback
-			if feedback == "reject" {
-				node.State = "rejected"
-			} else if feedback == "approve" {
-				node.State = "approved"
-			}
-			node.mu.Unlock()
-		}
+	a.setResult(a.compiled.Resume(ctx, a.ThreadID, WithDecision(decision)))
+	return a.Err
+}
+
+// HumanFeedback collects feedback from a human user. The feedback "approve"
+// runs the pending tool calls and "reject" rejects them. Any other feedback
+// rejects them with the feedback as the reason.
+func (a *HumanInTheLoopAgent) HumanFeedback(ctx context.Context, feedback string) error {
+	a.Feedback = feedback
+
+	decision := Decision{Type: DecisionReject, Reason: feedback}
+	switch feedback {
+	case "approve":
+		decision = Decision{Type: DecisionApprove}
+	case "reject":
+		decision.Reason = ""
 	}
+	return a.Decide(ctx, decision)
+}
 
-	return nil
+func (a *HumanInTheLoopAgent) setResult(state State, err error) {
+	a.Results, a.Interrupt, a.Err = state, nil, err
+	var interrupt *Interrupt
+	if errors.As(err, &interrupt) {
+		a.Interrupt, a.Err = interrupt, nil
+	}
 }
@@ -0,0 +1,189 @@
+package graphs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// InterruptPoint tells whether a run was interrupted before or after a node.
+type InterruptPoint string
+
+const (
+	// InterruptPointBefore is used for runs paused before the node ran.
+	InterruptPointBefore InterruptPoint = "before"
+	// InterruptPointAfter is used for runs paused after the node ran but before
+	// its update was merged into the state.
+	InterruptPointAfter InterruptPoint = "after"
+)
+
+// Interrupt is returned as the error of a run that paused at an interrupt
+// point. The run is saved in a checkpoint and continues once Resume is called
+// with a Decision. Use errors.As to get the interrupt of a run and errors.Is
+// with ErrInterrupted to check for one.
+type Interrupt struct {
+	ThreadID string `json:"thread_id"`
+	Step     int    `json:"step"`
+	// Nodes holds the interrupted nodes of the step.
+	Nodes []string       `json:"nodes"`
+	Point InterruptPoint `json:"point"`
+	// Subgraphs holds the interrupts of the subgraph nodes that paused the
+	// step, keyed by node name. The nodes of such interrupts are prefixed with
+	// the name of the subgraph node.
+	Subgraphs map[string]*Interrupt `json:"subgraphs,omitempty"`
+
+	// State is the state of the run at the interrupt.
+	State State `json:"-"`
+	// Writes holds the updates of the interrupted nodes for runs paused after
+	// the nodes ran.
+	Writes map[string]State `json:"-"`
+	// Actions holds the pending tool calls stored under ActionsKey for runs
+	// paused before the nodes ran.
+	Actions []schema.AgentAction `json:"-"`
+}
+
+func (i *Interrupt) Error() string {
+	return fmt.Sprintf("%s: %s nodes %v at step %d of thread %q",
+		ErrInterrupted, i.Point, i.Nodes, i.Step, i.ThreadID)
+}
+
+// Is makes errors.Is(err, ErrInterrupted) true for interrupts.
+func (i *Interrupt) Is(target error) bool {
+	return target == ErrInterrupted
+}
+
+// DecisionType is the kind of decision taken on an interrupted run.
+type DecisionType string
+
+const (
+	// DecisionApprove continues the run unchanged.
+	DecisionApprove DecisionType = "approve"
+	// DecisionReject does not run the interrupted nodes, or discards their
+	// updates if they already ran. Nodes added with OnReject run their reject
+	// function instead.
+	DecisionReject DecisionType = "reject"
+	// DecisionEdit changes the state, or the pending tool calls, and continues
+	// the run.
+	DecisionEdit DecisionType = "edit"
+)
+
+// Decision is given to Resume to continue an interrupted run.
+type Decision struct {
+	Type DecisionType
+	// Reason explains a rejection. It is passed to the reject functions of the
+	// interrupted nodes.
+	Reason string
+	// Update holds keys written over the state. For runs paused before the
+	// nodes ran the update is written before they run; otherwise it is written
+	// after their updates were merged.
+	Update State
+	// Actions replaces the pending tool calls stored under ActionsKey.
+	Actions []schema.AgentAction
+}
+
+// update returns the keys the decision writes over the state.
+func (d Decision) update() State {
+	update := State{}.Merge(d.Update)
+	if d.Actions != nil {
+		update[ActionsKey] = d.Actions
+	}
+	return update
+}
+
+func (d Decision) validate() error {
+	switch d.Type {
+	case DecisionApprove, DecisionReject:
+		return nil
+	case DecisionEdit:
+		if len(d.Update) == 0 && d.Actions == nil {
+			return fmt.Errorf("%w: edit without update or actions", ErrInvalidDecision)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: unknown type %q", ErrInvalidDecision, d.Type)
+}
+
+// RejectFunc is run instead of a node when a reviewer rejects it. It receives
+// the reason given in the decision and returns the update of the node.
+type RejectFunc func(ctx context.Context, state State, reason string) (State, error)
+
+// NodeOption is a function that configures a node.
+type NodeOption func(*Node)
+
+// InterruptBefore is an option for AddNode that pauses runs before the node
+// runs.
+func InterruptBefore() NodeOption {
+	return func(n *Node) {
+		n.InterruptBefore = true
+	}
+}
+
+// InterruptAfter is an option for AddNode that pauses runs after the node ran,
+// before its update is merged into the state.
+func InterruptAfter() NodeOption {
+	return func(n *Node) {
+		n.InterruptAfter = true
+	}
+}
+
+// OnReject is an option for AddNode setting the function run instead of the
+// node when an interrupt before it is rejected.
+func OnReject(fn RejectFunc) NodeOption {
+	return func(n *Node) {
+		n.OnReject = fn
+	}
+}
+
+// interruptedNodes returns the nodes of the next step that interrupt at the
+// given point.
+func (r *run) interruptedNodes(point InterruptPoint) []string {
+	var nodes []string
+	for _, name := range r.next {
+		node := r.graph.nodes[name]
+		if point == InterruptPointBefore && node.InterruptBefore ||
+			point == InterruptPointAfter && node.InterruptAfter {
+			nodes = append(nodes, name)
+		}
+	}
+	return nodes
+}
+
+// interrupt saves the run with the pending interrupt and returns it.
+func (r *run) interrupt(ctx context.Context, nodes []string, point InterruptPoint, writes []write) (State, error) {
+	i := &Interrupt{
+		ThreadID: r.threadID,
+		Step:     r.step,
+		Nodes:    nodes,
+		Point:    point,
+	}
+	r.pending = i
+	r.pendingWrites = writes
+	if err := r.checkpoint(ctx); err != nil {
+		return r.state, err
+	}
+	return r.state, r.describe(i)
+}
+
+// describe fills in the parts of an interrupt that are not stored in the
+// checkpoint.
+func (r *run) describe(i *Interrupt) *Interrupt {
+	d := *i
+	d.State = r.state.Clone()
+	if len(d.Subgraphs) > 0 {
+		return &d
+	}
+	if d.Point == InterruptPointAfter {
+		d.Writes = make(map[string]State, len(d.Nodes))
+		for _, w := range r.pendingWrites {
+			for _, name := range d.Nodes {
+				if w.node == name {
+					d.Writes[name] = w.update
+				}
+			}
+		}
+	} else {
+		d.Actions, _ = Get[[]schema.AgentAction](r.state, ActionsKey)
+	}
+	return &d
+}
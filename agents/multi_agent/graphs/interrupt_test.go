@@ -0,0 +1,206 @@
+package graphs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// newReviewGraph returns a graph where an agent node proposes a tool call that
+// a tool node, interrupted before it runs, executes.
+func newReviewGraph(t *testing.T, checkpointer Checkpointer, toolOpts ...NodeOption) *CompiledGraph {
+	t.Helper()
+
+	g := NewGraph()
+	require.NoError(t, g.AddChannel(StepsKey, Append))
+	require.NoError(t, g.AddNode("agent", func(context.Context, State) (State, error) {
+		return State{ActionsKey: []schema.AgentAction{{Tool: "upper", ToolInput: "hello"}}}, nil
+	}))
+	require.NoError(t, g.AddNode("tools", ToolNode([]tools.Tool{upperTool{}}), toolOpts...))
+	require.NoError(t, g.SetEntryPoint("agent"))
+	require.NoError(t, g.AddEdge("agent", "tools"))
+	require.NoError(t, g.SetFinishPoint("tools"))
+
+	c, err := g.Compile(WithCheckpointer(checkpointer))
+	require.NoError(t, err)
+	return c
+}
+
+func steps(t *testing.T, s State) []schema.AgentStep {
+	t.Helper()
+	steps, ok := Get[[]schema.AgentStep](s, StepsKey)
+	require.True(t, ok)
+	return steps
+}
+
+func TestInterruptBefore(t *testing.T) {
+	t.Parallel()
+
+	fileCheckpointer, err := NewFileCheckpointer(t.TempDir())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		decision Decision
+		want     []schema.AgentStep
+	}{
+		{
+			name:     "approve",
+			decision: Decision{Type: DecisionApprove},
+			want: []schema.AgentStep{
+				{Action: schema.AgentAction{Tool: "upper", ToolInput: "hello"}, Observation: "HELLO"},
+			},
+		},
+		{
+			name: "edit",
+			decision: Decision{
+				Type:    DecisionEdit,
+				Actions: []schema.AgentAction{{Tool: "upper", ToolInput: "edited"}},
+			},
+			want: []schema.AgentStep{
+				{Action: schema.AgentAction{Tool: "upper", ToolInput: "edited"}, Observation: "EDITED"},
+			},
+		},
+		{
+			name:     "reject",
+			decision: Decision{Type: DecisionReject, Reason: "not allowed"},
+			want: []schema.AgentStep{{
+				Action:      schema.AgentAction{Tool: "upper", ToolInput: "hello"},
+				Observation: "The tool call was rejected by a reviewer: not allowed",
+			}},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			c := newReviewGraph(t, fileCheckpointer, InterruptBefore(), OnReject(RejectToolCalls))
+
+			_, err := c.Invoke(ctx, nil, WithThreadID(tc.name))
+			require.ErrorIs(t, err, ErrInterrupted)
+			var interrupt *Interrupt
+			require.True(t, errors.As(err, &interrupt))
+			assert.Equal(t, []string{"tools"}, interrupt.Nodes)
+			assert.Equal(t, InterruptPointBefore, interrupt.Point)
+			assert.Equal(t, 1, interrupt.Step)
+			assert.Equal(t, []schema.AgentAction{{Tool: "upper", ToolInput: "hello"}}, interrupt.Actions)
+
+			_, err = c.Resume(ctx, tc.name)
+			require.ErrorIs(t, err, ErrDecisionRequired)
+
+			state, err := c.Resume(ctx, tc.name, WithDecision(tc.decision))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, steps(t, state))
+		})
+	}
+}
+
+func TestInterruptAfter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		decision Decision
+		want     State
+	}{
+		{
+			name:     "approve",
+			decision: Decision{Type: DecisionApprove},
+			want:     State{"draft": "v1", "published": true},
+		},
+		{
+			name:     "edit",
+			decision: Decision{Type: DecisionEdit, Update: State{"draft": "v2"}},
+			want:     State{"draft": "v2", "published": true},
+		},
+		{
+			name:     "reject",
+			decision: Decision{Type: DecisionReject},
+			want:     State{"published": true},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			g := NewGraph()
+			require.NoError(t, g.AddNode("write", constNode("draft", "v1"), InterruptAfter()))
+			require.NoError(t, g.AddNode("publish", constNode("published", true)))
+			require.NoError(t, g.SetEntryPoint("write"))
+			require.NoError(t, g.AddEdge("write", "publish"))
+			require.NoError(t, g.SetFinishPoint("publish"))
+			c, err := g.Compile(WithCheckpointer(NewMemoryCheckpointer()))
+			require.NoError(t, err)
+
+			state, err := c.Invoke(ctx, nil, WithThreadID("thread"))
+			var interrupt *Interrupt
+			require.True(t, errors.As(err, &interrupt))
+			assert.Equal(t, InterruptPointAfter, interrupt.Point)
+			assert.Equal(t, map[string]State{"write": {"draft": "v1"}}, interrupt.Writes)
+			assert.Equal(t, State{}, state)
+
+			state, err = c.Resume(ctx, "thread", WithDecision(tc.decision))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, state)
+		})
+	}
+}
+
+func TestInterruptErrors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	g := NewGraph()
+	require.NoError(t, g.AddNode("a", constNode("a", 1), InterruptBefore()))
+	require.NoError(t, g.SetEntryPoint("a"))
+	require.NoError(t, g.SetFinishPoint("a"))
+	_, err := g.Compile()
+	require.ErrorIs(t, err, ErrNoCheckpointer)
+
+	c, err := g.Compile(WithCheckpointer(NewMemoryCheckpointer()))
+	require.NoError(t, err)
+	_, err = c.Invoke(ctx, nil, WithThreadID("thread"))
+	require.ErrorIs(t, err, ErrInterrupted)
+
+	_, err = c.Resume(ctx, "thread", WithDecision(Decision{Type: DecisionEdit}))
+	require.ErrorIs(t, err, ErrInvalidDecision)
+	_, err = c.Resume(ctx, "thread", WithDecision(Decision{Type: "maybe"}))
+	require.ErrorIs(t, err, ErrInvalidDecision)
+
+	state, err := c.Resume(ctx, "thread", WithDecision(Decision{Type: DecisionApprove}))
+	require.NoError(t, err)
+	assert.Equal(t, State{"a": 1}, state)
+
+	_, err = c.Resume(ctx, "thread", WithDecision(Decision{Type: DecisionApprove}))
+	require.ErrorIs(t, err, ErrInvalidDecision)
+}
+
+func TestHumanInTheLoopAgent(t *testing.T) {
+	t.Parallel()
+
+	agent := NewHumanInTheLoopAgent()
+	agent.Tools = []tools.Tool{upperTool{}}
+	agent.InitializeHumanInTheLoopActions([]schema.AgentAction{{Tool: "upper", ToolInput: "hi"}})
+	agent.ExecuteHumanInTheLoopActions()
+	require.NoError(t, agent.Err)
+	require.NotNil(t, agent.Interrupt)
+	assert.Equal(t, []schema.AgentAction{{Tool: "upper", ToolInput: "hi"}}, agent.Interrupt.Actions)
+
+	require.NoError(t, agent.HumanFeedback(context.Background(), "approve"))
+	assert.Nil(t, agent.Interrupt)
+	assert.Equal(t, []schema.AgentStep{
+		{Action: schema.AgentAction{Tool: "upper", ToolInput: "hi"}, Observation: "HI"},
+	}, steps(t, agent.Results))
+
+	require.ErrorIs(t, agent.HumanFeedback(context.Background(), "approve"), ErrInvalidDecision)
+}
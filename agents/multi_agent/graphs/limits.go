@@ -0,0 +1,163 @@
+package graphs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// DefaultRecursionLimit is the number of steps a single call of Invoke or
+// Resume runs before it stops with a RecursionLimitError.
+const DefaultRecursionLimit = 25
+
+// RecursionLimitError is returned when a run reached its recursion limit
+// before finishing, for instance because two nodes keep routing to each other.
+// It matches ErrRecursionLimit with errors.Is. If the graph has a checkpointer
+// the run can be continued with Resume.
+type RecursionLimitError struct {
+	Limit int
+	// Step is the step the run stopped at and State the state at that step.
+	Step  int
+	State State
+	// Next holds the nodes that would have run next.
+	Next []string
+}
+
+func (e *RecursionLimitError) Error() string {
+	return fmt.Sprintf("%s: stopped after %d steps at step %d with nodes %v pending",
+		ErrRecursionLimit, e.Limit, e.Step, e.Next)
+}
+
+// Is makes errors.Is(err, ErrRecursionLimit) true for recursion limit errors.
+func (e *RecursionLimitError) Is(target error) bool {
+	return target == ErrRecursionLimit
+}
+
+// RetryPolicy tells how a failing node is retried. Zero fields take their
+// default value.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times the node runs, including the first
+	// attempt. Defaults to 3.
+	MaxAttempts int
+	// InitialInterval is the wait before the first retry. Defaults to 500ms.
+	InitialInterval time.Duration
+	// BackoffFactor multiplies the wait after every retry. Defaults to 2.
+	BackoffFactor float64
+	// MaxInterval caps the wait between retries. Defaults to 30s.
+	MaxInterval time.Duration
+	// Jitter spreads the wait randomly between half and all of its value.
+	Jitter bool
+	// Retryable tells whether an error is worth retrying. Defaults to retrying
+	// all errors except cancellations.
+	Retryable func(error) bool
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialInterval <= 0 {
+		p.InitialInterval = 500 * time.Millisecond
+	}
+	if p.BackoffFactor <= 0 {
+		p.BackoffFactor = 2
+	}
+	if p.MaxInterval <= 0 {
+		p.MaxInterval = 30 * time.Second
+	}
+	if p.Retryable == nil {
+		p.Retryable = func(err error) bool { return !errors.Is(err, context.Canceled) }
+	}
+	return p
+}
+
+// interval returns the wait before the given retry, counted from 1.
+func (p RetryPolicy) interval(retry int) time.Duration {
+	interval := float64(p.InitialInterval) * math.Pow(p.BackoffFactor, float64(retry-1))
+	interval = math.Min(interval, float64(p.MaxInterval))
+	if p.Jitter {
+		interval = interval/2 + rand.Float64()*interval/2 //nolint:gosec
+	}
+	return time.Duration(interval)
+}
+
+// Timeout is an option for AddNode that fails the node with ErrNodeTimeout if
+// it runs longer than d. With a retry policy every attempt gets d. The context
+// of the node is canceled at the deadline, and the run goes on without waiting
+// for nodes that ignore it.
+func Timeout(d time.Duration) NodeOption {
+	return func(n *Node) {
+		n.Timeout = d
+	}
+}
+
+// Retry is an option for AddNode that runs the node again when it fails with
+// a retryable error, waiting longer before every attempt.
+func Retry(policy RetryPolicy) NodeOption {
+	return func(n *Node) {
+		p := policy.withDefaults()
+		n.RetryPolicy = &p
+	}
+}
+
+// callNode runs the function of a node, applying its timeout and retry policy.
+func callNode(ctx context.Context, node *Node, fn func(context.Context) (State, error)) (State, error) {
+	if node.RetryPolicy == nil {
+		return callWithTimeout(ctx, node, fn)
+	}
+
+	p := node.RetryPolicy
+	for attempt := 1; ; attempt++ {
+		update, err := callWithTimeout(ctx, node, fn)
+		if err == nil || attempt >= p.MaxAttempts || ctx.Err() != nil ||
+			errors.Is(err, ErrInterrupted) || !p.Retryable(err) {
+			return update, err
+		}
+
+		timer := time.NewTimer(p.interval(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+func callWithTimeout(ctx context.Context, node *Node, fn func(context.Context) (State, error)) (State, error) {
+	if node.Timeout <= 0 {
+		return fn(ctx)
+	}
+
+	nodeCtx, cancel := context.WithTimeout(ctx, node.Timeout)
+	defer cancel()
+
+	type result struct {
+		update State
+		err    error
+	}
+	// The node runs in its own goroutine so that a node ignoring its context
+	// cannot block the step past the deadline. Such a node keeps running in
+	// the background and its update is dropped.
+	done := make(chan result, 1)
+	go func() {
+		update, err := fn(nodeCtx)
+		done <- result{update, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil && ctx.Err() == nil && errors.Is(nodeCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s: %w", ErrNodeTimeout, node.Timeout, r.err)
+		}
+		return r.update, r.err
+	case <-nodeCtx.Done():
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w after %s: %w", ErrNodeTimeout, node.Timeout, nodeCtx.Err())
+	}
+}
@@ -0,0 +1,174 @@
+package graphs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPingPongGraph returns a graph whose two nodes keep routing to each other.
+func newPingPongGraph(t *testing.T, opts ...CompileOption) *CompiledGraph {
+	t.Helper()
+
+	g := NewGraph()
+	for _, name := range []string{"ping", "pong"} {
+		name := name
+		require.NoError(t, g.AddNode(name, func(_ context.Context, s State) (State, error) {
+			n, _ := Get[int](s, "n")
+			return State{"n": n + 1, "last": name}, nil
+		}))
+	}
+	require.NoError(t, g.SetEntryPoint("ping"))
+	require.NoError(t, g.AddEdge("ping", "pong"))
+	require.NoError(t, g.AddEdge("pong", "ping"))
+
+	c, err := g.Compile(opts...)
+	require.NoError(t, err)
+	return c
+}
+
+func TestRecursionLimit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	state, err := newPingPongGraph(t).Invoke(ctx, nil)
+	require.ErrorIs(t, err, ErrRecursionLimit)
+	var limitErr *RecursionLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, DefaultRecursionLimit, limitErr.Limit)
+	assert.Equal(t, DefaultRecursionLimit, limitErr.State["n"])
+	assert.Equal(t, []string{"pong"}, limitErr.Next)
+	assert.Equal(t, limitErr.State, state)
+
+	// Every call of Resume gets its own limit.
+	c := newPingPongGraph(t, WithCheckpointer(NewMemoryCheckpointer()))
+	_, err = c.Invoke(ctx, nil, WithThreadID("thread"), WithRecursionLimit(3))
+	require.ErrorIs(t, err, ErrRecursionLimit)
+	state, err = c.Resume(ctx, "thread", WithRecursionLimit(2))
+	require.ErrorIs(t, err, ErrRecursionLimit)
+	assert.Equal(t, 5, state["n"])
+	assert.Equal(t, "ping", state["last"])
+}
+
+func TestNodeTimeout(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph()
+	require.NoError(t, g.AddNode("slow", func(ctx context.Context, _ State) (State, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, Timeout(10*time.Millisecond)))
+	require.NoError(t, g.SetEntryPoint("slow"))
+	require.NoError(t, g.SetFinishPoint("slow"))
+
+	_, err := g.Execute(context.Background(), nil)
+	require.ErrorIs(t, err, ErrNodeTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNodeTimeoutIgnoringContext(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	defer close(release)
+	g := NewGraph()
+	require.NoError(t, g.AddNode("stuck", func(context.Context, State) (State, error) {
+		<-release
+		return State{"done": true}, nil
+	}, Timeout(10*time.Millisecond)))
+	require.NoError(t, g.SetEntryPoint("stuck"))
+	require.NoError(t, g.SetFinishPoint("stuck"))
+
+	start := time.Now()
+	_, err := g.Execute(context.Background(), nil)
+	require.ErrorIs(t, err, ErrNodeTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestNodeRetry(t *testing.T) {
+	t.Parallel()
+
+	errTransient := errors.New("transient")
+	errFatal := errors.New("fatal")
+
+	tests := []struct {
+		name      string
+		failures  []error
+		policy    RetryPolicy
+		wantCalls int32
+		wantErr   error
+	}{
+		{
+			name:      "recovers",
+			failures:  []error{errTransient, errTransient},
+			policy:    RetryPolicy{InitialInterval: time.Millisecond},
+			wantCalls: 3,
+		},
+		{
+			name:      "gives up",
+			failures:  []error{errTransient, errTransient, errTransient},
+			policy:    RetryPolicy{MaxAttempts: 2, InitialInterval: time.Millisecond},
+			wantCalls: 2,
+			wantErr:   errTransient,
+		},
+		{
+			name:     "not retryable",
+			failures: []error{errFatal},
+			policy: RetryPolicy{
+				InitialInterval: time.Millisecond,
+				Retryable:       func(err error) bool { return errors.Is(err, errTransient) },
+			},
+			wantCalls: 1,
+			wantErr:   errFatal,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			g := NewGraph()
+			require.NoError(t, g.AddNode("flaky", func(context.Context, State) (State, error) {
+				call := calls.Add(1)
+				if int(call) <= len(tc.failures) {
+					return nil, tc.failures[call-1]
+				}
+				return State{"ok": true}, nil
+			}, Retry(tc.policy)))
+			require.NoError(t, g.SetEntryPoint("flaky"))
+			require.NoError(t, g.SetFinishPoint("flaky"))
+
+			state, err := g.Execute(context.Background(), nil)
+			assert.Equal(t, tc.wantCalls, calls.Load())
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, true, state["ok"])
+		})
+	}
+}
+
+func TestRetryPolicyInterval(t *testing.T) {
+	t.Parallel()
+
+	p := RetryPolicy{InitialInterval: time.Second, MaxInterval: 3 * time.Second}.withDefaults()
+	assert.Equal(t, time.Second, p.interval(1))
+	assert.Equal(t, 2*time.Second, p.interval(2))
+	assert.Equal(t, 3*time.Second, p.interval(3))
+
+	p.Jitter = true
+	for i := 0; i < 10; i++ {
+		interval := p.interval(2)
+		assert.GreaterOrEqual(t, interval, time.Second)
+		assert.LessOrEqual(t, interval, 2*time.Second)
+	}
+}
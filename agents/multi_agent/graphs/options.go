@@ -0,0 +1,66 @@
+package graphs
+
+// CompileOption is a function that configures a compiled graph.
+type CompileOption func(*compileOptions)
+
+type compileOptions struct {
+	checkpointer Checkpointer
+}
+
+// WithCheckpointer is an option for Compile that saves a checkpoint after every
+// step of a run. Runs of a graph with a checkpointer need a thread ID.
+func WithCheckpointer(checkpointer Checkpointer) CompileOption {
+	return func(o *compileOptions) {
+		o.checkpointer = checkpointer
+	}
+}
+
+// RunOption is a function that configures a single run of a graph.
+type RunOption func(*runOptions)
+
+type runOptions struct {
+	threadID       string
+	decision       *Decision
+	recursionLimit int
+	emitter        *emitter
+}
+
+// WithThreadID is an option for Invoke setting the thread the checkpoints of
+// the run are stored under.
+func WithThreadID(threadID string) RunOption {
+	return func(o *runOptions) {
+		o.threadID = threadID
+	}
+}
+
+// WithDecision is an option for Resume giving the decision taken on the
+// pending interrupt of the run.
+func WithDecision(decision Decision) RunOption {
+	return func(o *runOptions) {
+		o.decision = &decision
+	}
+}
+
+// WithRecursionLimit is an option for Invoke and Resume setting the number of
+// steps the call runs before it stops with a RecursionLimitError. Defaults to
+// DefaultRecursionLimit.
+func WithRecursionLimit(limit int) RunOption {
+	return func(o *runOptions) {
+		o.recursionLimit = limit
+	}
+}
+
+// withEmitter is the option used by Stream to receive the events of a run.
+func withEmitter(e *emitter) RunOption {
+	return func(o *runOptions) {
+		o.emitter = e
+	}
+}
+
+func applyRunOptions(opts []RunOption) runOptions {
+	o := runOptions{recursionLimit: DefaultRecursionLimit}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,466 @@
+package graphs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/callbacks"
+)
+
+// Invoke runs the graph from START with input as the initial state and returns
+// the state once no more nodes are scheduled.
+//
+// The graph runs in supersteps. All nodes scheduled for a step run
+// concurrently on the same state and the step ends once every one of them has
+// returned. Their updates are then merged into the state through the channel
+// reducers, in the order of the node names, and the nodes for the next step
+// are picked from the edges leaving the nodes that ran. If a node fails the
+// state as it was before the failing step is returned together with the error.
+//
+// If the graph was compiled with a checkpointer a checkpoint is saved after
+// every step under the thread ID given with WithThreadID.
+func (c *CompiledGraph) Invoke(ctx context.Context, input State, opts ...RunOption) (State, error) {
+	r, err := c.newRun(applyRunOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	r.writes = []write{{node: START, update: input}}
+	if r.state, err = c.apply(State{}, r.writes); err != nil {
+		return nil, err
+	}
+	if r.next, err = r.schedule(ctx, []string{START}, r.state); err != nil {
+		return r.state, err
+	}
+	if err := r.checkpoint(ctx); err != nil {
+		return r.state, err
+	}
+	return r.loop(ctx)
+}
+
+// Resume continues the run of a thread from its latest checkpoint. The step
+// that was in progress when the run stopped is run again. If the run already
+// finished its final state is returned.
+//
+// A run paused at an interrupt point needs a decision, given with
+// WithDecision, to continue.
+func (c *CompiledGraph) Resume(ctx context.Context, threadID string, opts ...RunOption) (State, error) {
+	if c.checkpointer == nil {
+		return nil, ErrNoCheckpointer
+	}
+	cp, err := c.checkpointer.Latest(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	o := applyRunOptions(opts)
+	o.threadID = threadID
+	r, err := c.newRun(o)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.restore(cp); err != nil {
+		return cp.State, err
+	}
+
+	switch {
+	case r.pending == nil && o.decision != nil:
+		return r.state, fmt.Errorf("%w: thread %q has no pending interrupt", ErrInvalidDecision, threadID)
+	case r.pending == nil:
+		return r.loop(ctx)
+	case o.decision == nil:
+		d := r.describe(r.pending)
+		if err := c.describeSubgraphs(ctx, d); err != nil {
+			return r.state, err
+		}
+		return r.state, fmt.Errorf("%w: %w", ErrDecisionRequired, d)
+	}
+	if err := o.decision.validate(); err != nil {
+		return r.state, err
+	}
+	return r.decide(ctx, *o.decision)
+}
+
+// run holds the bookkeeping of a single graph run.
+type run struct {
+	graph    *CompiledGraph
+	threadID string
+
+	step  int
+	state State
+	// next holds the nodes scheduled for the next step, and writes the writes
+	// of the last step.
+	next   []string
+	writes []write
+	// waiting holds, per join edge, the sources that have run since the join
+	// was last followed.
+	waiting map[int]map[string]bool
+
+	// pending is the interrupt the run is paused at, and pendingWrites the
+	// writes of the step in progress for interrupts after nodes.
+	pending       *Interrupt
+	pendingWrites []write
+	// approved skips the interrupts before the nodes of the next step, and
+	// rejected holds the reasons for the nodes of the next step that must not
+	// run.
+	approved bool
+	rejected map[string]string
+	// forkedFrom is kept when the checkpoint the run was resumed from is saved
+	// again.
+	forkedFrom *CheckpointRef
+
+	// subgraphDecision is passed on to the subgraphs resumed for a decision,
+	// and restored is set until the step the run was restored at finished.
+	subgraphDecision *Decision
+	restored         bool
+
+	// emitter receives the events of the run if it is streamed.
+	emitter *emitter
+
+	// recursionLimit is the number of steps the run can take, and steps the
+	// number of steps it took.
+	recursionLimit int
+	steps          int
+}
+
+func (c *CompiledGraph) newRun(o runOptions) (*run, error) {
+	if c.checkpointer != nil && o.threadID == "" {
+		return nil, ErrNoThreadID
+	}
+	return &run{
+		graph:    c,
+		threadID: o.threadID,
+		waiting:  make(map[int]map[string]bool),
+		emitter:  o.emitter,
+
+		recursionLimit: o.recursionLimit,
+	}, nil
+}
+
+// loop runs steps until no more nodes are scheduled or the run is interrupted.
+func (r *run) loop(ctx context.Context) (State, error) {
+	for len(r.next) > 0 {
+		if err := ctx.Err(); err != nil {
+			return r.state, err
+		}
+		if r.steps >= r.recursionLimit {
+			return r.state, &RecursionLimitError{
+				Limit: r.recursionLimit,
+				Step:  r.step,
+				State: r.state.Clone(),
+				Next:  slices.Clone(r.next),
+			}
+		}
+
+		if !r.approved {
+			if nodes := r.interruptedNodes(InterruptPointBefore); len(nodes) > 0 {
+				return r.interrupt(ctx, nodes, InterruptPointBefore, nil)
+			}
+		}
+		rejected := r.rejected
+		r.approved, r.rejected = false, nil
+
+		writes, interrupted, err := r.runStep(ctx, r.next, rejected)
+		if err != nil {
+			return r.state, err
+		}
+		if len(interrupted) > 0 {
+			return r.interruptSubgraphs(ctx, writes, interrupted)
+		}
+		if state, err := r.afterStep(ctx, writes, rejected); err != nil {
+			return state, err
+		}
+	}
+	return r.state, nil
+}
+
+// afterStep pauses the run if nodes of the step interrupt after they ran, and
+// finishes the step otherwise.
+func (r *run) afterStep(ctx context.Context, writes []write, rejected map[string]string) (State, error) {
+	nodes := slices.DeleteFunc(r.interruptedNodes(InterruptPointAfter), func(name string) bool {
+		_, ok := rejected[name]
+		return ok
+	})
+	if len(nodes) > 0 {
+		return r.interrupt(ctx, nodes, InterruptPointAfter, writes)
+	}
+	return r.state, r.finishStep(ctx, writes, nil)
+}
+
+// decide continues a run paused at an interrupt with the given decision.
+func (r *run) decide(ctx context.Context, d Decision) (State, error) {
+	i, writes := r.pending, r.pendingWrites
+	r.pending, r.pendingWrites = nil, nil
+
+	if len(i.Subgraphs) > 0 {
+		if state, err := r.resumeSubgraphs(ctx, i, writes, d); err != nil {
+			return state, err
+		}
+		return r.loop(ctx)
+	}
+	if i.Point == InterruptPointBefore {
+		if d.Type == DecisionReject {
+			r.rejected = make(map[string]string, len(i.Nodes))
+			for _, name := range i.Nodes {
+				r.rejected[name] = d.Reason
+			}
+		}
+		r.state = r.state.Merge(d.update())
+		r.approved = true
+		return r.loop(ctx)
+	}
+
+	if d.Type == DecisionReject {
+		writes = slices.DeleteFunc(writes, func(w write) bool {
+			return slices.Contains(i.Nodes, w.node)
+		})
+	}
+	if err := r.finishStep(ctx, writes, d.update()); err != nil {
+		return r.state, err
+	}
+	return r.loop(ctx)
+}
+
+// finishStep merges the writes of a step and the given update into the state,
+// schedules the next step and saves a checkpoint.
+func (r *run) finishStep(ctx context.Context, writes []write, update State) error {
+	state, err := r.graph.apply(r.state, writes)
+	if err != nil {
+		return err
+	}
+	state = state.Merge(update)
+	next, err := r.schedule(ctx, r.next, state)
+	if err != nil {
+		return err
+	}
+
+	r.step++
+	r.steps++
+	r.state, r.next, r.writes = state, next, writes
+	r.forkedFrom = nil
+	r.restored = false
+	for _, w := range writes {
+		r.emitter.emit(Event{Mode: StreamUpdates, Node: w.node, Step: r.step, Update: w.update})
+	}
+	r.emitter.emit(Event{Mode: StreamValues, Step: r.step, State: r.state.Clone()})
+	return r.checkpoint(ctx)
+}
+
+// checkpoint saves the current position of the run if the graph has a
+// checkpointer.
+func (r *run) checkpoint(ctx context.Context) error {
+	if r.graph.checkpointer == nil {
+		return nil
+	}
+
+	joins := make(map[int][]string, len(r.waiting))
+	for i, from := range r.waiting {
+		joins[i] = sortedKeys(from)
+	}
+	err := r.graph.checkpointer.Put(ctx, Checkpoint{
+		ThreadID:      r.threadID,
+		Step:          r.step,
+		State:         r.state,
+		Next:          r.next,
+		Writes:        writesByNode(r.writes),
+		Joins:         joins,
+		Interrupt:     r.pending,
+		PendingWrites: writesByNode(r.pendingWrites),
+		ForkedFrom:    r.forkedFrom,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("saving checkpoint %d of thread %q: %w", r.step, r.threadID, err)
+	}
+	return nil
+}
+
+// restore sets the position of the run to the given checkpoint.
+func (r *run) restore(cp Checkpoint) error {
+	for _, name := range cp.Next {
+		if _, ok := r.graph.nodes[name]; !ok {
+			return fmt.Errorf("%w: %q in checkpoint %d of thread %q", ErrUnknownNode, name, cp.Step, cp.ThreadID)
+		}
+	}
+
+	r.step = cp.Step
+	r.state = cp.State.Clone()
+	r.next = append([]string(nil), cp.Next...)
+	for i, from := range cp.Joins {
+		r.waiting[i] = make(map[string]bool, len(from))
+		for _, f := range from {
+			r.waiting[i][f] = true
+		}
+	}
+	r.pending = cp.Interrupt
+	r.writes = writesFromNodes(cp.Writes)
+	r.pendingWrites = writesFromNodes(cp.PendingWrites)
+	r.forkedFrom = cp.ForkedFrom
+	r.restored = true
+	return nil
+}
+
+func writesByNode(writes []write) map[string]State {
+	if len(writes) == 0 {
+		return nil
+	}
+	byNode := make(map[string]State, len(writes))
+	for _, w := range writes {
+		byNode[w.node] = w.update
+	}
+	return byNode
+}
+
+// writesFromNodes returns the writes ordered by node name.
+func writesFromNodes(byNode map[string]State) []write {
+	var writes []write
+	for _, node := range sortedKeys(byNode) {
+		writes = append(writes, write{node: node, update: byNode[node]})
+	}
+	return writes
+}
+
+// schedule returns the sorted nodes to run after the given nodes ran.
+func (r *run) schedule(ctx context.Context, ran []string, state State) ([]string, error) {
+	c := r.graph
+	next := make([]string, 0)
+	for _, from := range ran {
+		next = append(next, c.edges[from]...)
+
+		if b := c.branches[from]; b != nil {
+			to, err := c.route(ctx, from, b, state)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, to)
+		}
+
+		for i, j := range c.joins {
+			if !slices.Contains(j.from, from) {
+				continue
+			}
+			if r.waiting[i] == nil {
+				r.waiting[i] = make(map[string]bool, len(j.from))
+			}
+			r.waiting[i][from] = true
+			if len(r.waiting[i]) == len(j.from) {
+				delete(r.waiting, i)
+				next = append(next, j.to)
+			}
+		}
+	}
+
+	slices.Sort(next)
+	next = slices.Compact(next)
+	return slices.DeleteFunc(next, func(name string) bool { return name == END }), nil
+}
+
+// route asks the router of a conditional edge for the next node.
+func (c *CompiledGraph) route(ctx context.Context, from string, b *branch, state State) (string, error) {
+	label, err := b.router(ctx, state.Clone())
+	if err != nil {
+		return "", fmt.Errorf("routing from %q: %w", from, err)
+	}
+	to := label
+	if b.paths != nil {
+		var ok bool
+		if to, ok = b.paths[label]; !ok {
+			return "", fmt.Errorf("%w: route %q from %q", ErrUnknownNode, label, from)
+		}
+	}
+	if _, ok := c.nodes[to]; !ok && to != END {
+		return "", fmt.Errorf("%w: %q from %q", ErrUnknownNode, to, from)
+	}
+	return to, nil
+}
+
+// runStep runs the given nodes concurrently and waits for all of them. Nodes
+// in rejected run their reject function instead. The writes are returned in
+// the order of the nodes. Subgraph nodes whose subgraph was interrupted do not
+// write anything; their interrupts are returned instead. When a node fails the
+// other nodes of the step are canceled and the error of the node that failed
+// first is returned.
+func (r *run) runStep(
+	ctx context.Context,
+	nodes []string,
+	rejected map[string]string,
+) ([]write, map[string]*Interrupt, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg          sync.WaitGroup
+		mu          sync.Mutex
+		once        sync.Once
+		firstErr    error
+		interrupted map[string]*Interrupt
+	)
+	updates := make([]State, len(nodes))
+	for i, name := range nodes {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			ctx := withNode(callbacks.WithUsageScope(ctx, name), r.emitter, name, r.step+1)
+			update, err := r.runNode(ctx, name, r.state, rejected)
+			var interrupt *Interrupt
+			switch {
+			case err == nil:
+				updates[i] = update
+			case r.graph.nodes[name].subgraph != nil && errors.As(err, &interrupt):
+				mu.Lock()
+				defer mu.Unlock()
+				if interrupted == nil {
+					interrupted = make(map[string]*Interrupt)
+				}
+				interrupted[name] = interrupt
+			default:
+				once.Do(func() {
+					firstErr = fmt.Errorf("node %q: %w", name, err)
+					cancel()
+				})
+			}
+		}(i, name)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	writes := make([]write, 0, len(nodes))
+	for i, name := range nodes {
+		if _, ok := interrupted[name]; !ok {
+			writes = append(writes, write{node: name, update: updates[i]})
+		}
+	}
+	return writes, interrupted, nil
+}
+
+// sortWrites orders writes by node name, the order they are merged in.
+func sortWrites(writes []write) {
+	slices.SortFunc(writes, func(a, b write) int {
+		return strings.Compare(a.node, b.node)
+	})
+}
+
+func (r *run) runNode(ctx context.Context, name string, state State, rejected map[string]string) (State, error) {
+	node := r.graph.nodes[name]
+	reason, isRejected := rejected[name]
+	switch {
+	case !isRejected && node.subgraph != nil:
+		return callNode(ctx, node, func(ctx context.Context) (State, error) {
+			return r.runSubgraph(ctx, name, state)
+		})
+	case !isRejected:
+		return callNode(ctx, node, func(ctx context.Context) (State, error) {
+			return node.Func(ctx, state.Clone())
+		})
+	case node.OnReject != nil:
+		return node.OnReject(ctx, state.Clone(), reason)
+	}
+	return nil, nil
+}
@@ -0,0 +1,162 @@
+// Package sqlite3 adds support for
+// storing graph checkpoints in sqlite3.
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver.
+	"github.com/tmc/langchaingo/agents/multi_agent/graphs"
+)
+
+// DefaultTableName sets a default table name.
+const DefaultTableName = "langchaingo_graph_checkpoints"
+
+// DefaultSchema sets a default schema to be run after connecting.
+const DefaultSchema = `CREATE TABLE IF NOT EXISTS %s (
+		thread_id TEXT NOT NULL,
+		step INTEGER NOT NULL,
+		checkpoint TEXT NOT NULL,
+		created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (thread_id, step)
+);`
+
+// Checkpointer is a graphs.Checkpointer that stores checkpoints as JSON in a
+// sqlite3 table.
+type Checkpointer struct {
+	// DB is the database connection.
+	DB *sql.DB
+	// DBAddress is the address or file path for connecting the db.
+	DBAddress string
+	// TableName is the name of the checkpoints table.
+	TableName string
+	// Schema defines a initial schema to be run.
+	Schema []byte
+}
+
+// Statically assert that Checkpointer implements the checkpointer interface.
+var _ graphs.Checkpointer = &Checkpointer{}
+
+// NewCheckpointer creates a new Checkpointer, connecting to the database and
+// running the schema.
+func NewCheckpointer(ctx context.Context, options ...Option) (*Checkpointer, error) {
+	c := &Checkpointer{}
+	for _, option := range options {
+		option(c)
+	}
+
+	if c.TableName == "" {
+		c.TableName = DefaultTableName
+	}
+	if c.Schema == nil {
+		c.Schema = []byte(fmt.Sprintf(DefaultSchema, c.TableName))
+	}
+	if c.DBAddress == "" {
+		c.DBAddress = ":memory:"
+	}
+	if c.DB == nil {
+		db, err := sql.Open("sqlite3", c.DBAddress)
+		if err != nil {
+			return nil, err
+		}
+		c.DB = db
+	}
+
+	if _, err := c.DB.ExecContext(ctx, string(c.Schema)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Put stores a checkpoint, replacing any checkpoint with the same thread ID and
+// step.
+func (c *Checkpointer) Put(ctx context.Context, cp graphs.Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("encoding checkpoint: %w", err)
+	}
+
+	querytpl := []string{
+		"INSERT OR REPLACE INTO ",
+		" (thread_id, step, checkpoint) VALUES (?, ?, ?);",
+	}
+	query := strings.Join(querytpl, c.TableName)
+	_, err = c.DB.ExecContext(ctx, query, cp.ThreadID, cp.Step, string(data))
+	return err
+}
+
+// Get returns the checkpoint of a thread at the given step.
+func (c *Checkpointer) Get(ctx context.Context, threadID string, step int) (graphs.Checkpoint, error) {
+	querytpl := []string{
+		"SELECT checkpoint FROM ",
+		" WHERE thread_id = ? AND step = ?;",
+	}
+	query := strings.Join(querytpl, c.TableName)
+	return c.queryOne(ctx, query, threadID, step)
+}
+
+// Latest returns the checkpoint of a thread with the highest step.
+func (c *Checkpointer) Latest(ctx context.Context, threadID string) (graphs.Checkpoint, error) {
+	querytpl := []string{
+		"SELECT checkpoint FROM ",
+		" WHERE thread_id = ? ORDER BY step DESC LIMIT 1;",
+	}
+	query := strings.Join(querytpl, c.TableName)
+	return c.queryOne(ctx, query, threadID)
+}
+
+// List returns all checkpoints of a thread ordered by step.
+func (c *Checkpointer) List(ctx context.Context, threadID string) ([]graphs.Checkpoint, error) {
+	querytpl := []string{
+		"SELECT checkpoint FROM ",
+		" WHERE thread_id = ? ORDER BY step ASC;",
+	}
+	query := strings.Join(querytpl, c.TableName)
+	res, err := c.DB.QueryContext(ctx, query, threadID)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var cps []graphs.Checkpoint
+	for res.Next() {
+		var data string
+		if err := res.Scan(&data); err != nil {
+			return nil, err
+		}
+		cp, err := decode(data)
+		if err != nil {
+			return nil, err
+		}
+		cps = append(cps, cp)
+	}
+	if err := res.Err(); err != nil {
+		return nil, err
+	}
+	return cps, nil
+}
+
+func (c *Checkpointer) queryOne(ctx context.Context, query string, args ...any) (graphs.Checkpoint, error) {
+	var data string
+	err := c.DB.QueryRowContext(ctx, query, args...).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return graphs.Checkpoint{}, fmt.Errorf("%w: %v", graphs.ErrCheckpointNotFound, args)
+	}
+	if err != nil {
+		return graphs.Checkpoint{}, err
+	}
+	return decode(data)
+}
+
+func decode(data string) (graphs.Checkpoint, error) {
+	var cp graphs.Checkpoint
+	if err := json.Unmarshal([]byte(data), &cp); err != nil {
+		return graphs.Checkpoint{}, fmt.Errorf("decoding checkpoint: %w", err)
+	}
+	return cp, nil
+}
@@ -0,0 +1,39 @@
+package sqlite3
+
+import "database/sql"
+
+// Option is a function for creating a new checkpointer
+// with other than the default values.
+type Option func(c *Checkpointer)
+
+// WithDB is an option for NewCheckpointer for adding
+// a database connection.
+func WithDB(db *sql.DB) Option {
+	return func(c *Checkpointer) {
+		c.DB = db
+	}
+}
+
+// WithDBAddress is an option for NewCheckpointer for
+// specifying an address or file path for when connecting the db.
+func WithDBAddress(addr string) Option {
+	return func(c *Checkpointer) {
+		c.DBAddress = addr
+	}
+}
+
+// WithTableName is an option for NewCheckpointer for
+// setting the name of the checkpoints table.
+func WithTableName(name string) Option {
+	return func(c *Checkpointer) {
+		c.TableName = name
+	}
+}
+
+// WithSchema is an option for NewCheckpointer for
+// running a schema when connected. Useful for migrations for example.
+func WithSchema(schema []byte) Option {
+	return func(c *Checkpointer) {
+		c.Schema = schema
+	}
+}
@@ -0,0 +1,120 @@
+package sqlite3_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents/multi_agent/graphs"
+	"github.com/tmc/langchaingo/agents/multi_agent/graphs/sqlite3"
+)
+
+func TestCheckpointer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c, err := sqlite3.NewCheckpointer(ctx)
+	require.NoError(t, err)
+
+	_, err = c.Latest(ctx, "thread")
+	require.ErrorIs(t, err, graphs.ErrCheckpointNotFound)
+
+	for step := 0; step < 3; step++ {
+		require.NoError(t, c.Put(ctx, graphs.Checkpoint{
+			ThreadID: "thread",
+			Step:     step,
+			State:    graphs.State{"step": step},
+			Next:     []string{"node"},
+		}))
+	}
+	require.NoError(t, c.Put(ctx, graphs.Checkpoint{ThreadID: "thread", Step: 2, State: graphs.State{"step": 20}}))
+
+	latest, err := c.Latest(ctx, "thread")
+	require.NoError(t, err)
+	assert.Equal(t, 2, latest.Step)
+	step, _ := graphs.Get[int](latest.State, "step")
+	assert.Equal(t, 20, step)
+
+	first, err := c.Get(ctx, "thread", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"node"}, first.Next)
+
+	cps, err := c.List(ctx, "thread")
+	require.NoError(t, err)
+	require.Len(t, cps, 3)
+	for i, cp := range cps {
+		assert.Equal(t, i, cp.Step)
+	}
+}
+
+func TestCheckpointerResume(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c, err := sqlite3.NewCheckpointer(ctx)
+	require.NoError(t, err)
+
+	fail := true
+	g := graphs.NewGraph()
+	require.NoError(t, g.AddChannel("visited", graphs.Append))
+	require.NoError(t, g.AddNode("a", func(context.Context, graphs.State) (graphs.State, error) {
+		return graphs.State{"visited": []string{"a"}}, nil
+	}))
+	require.NoError(t, g.AddNode("b", func(context.Context, graphs.State) (graphs.State, error) {
+		if fail {
+			return nil, assert.AnError
+		}
+		return graphs.State{"visited": []string{"b"}}, nil
+	}))
+	require.NoError(t, g.SetEntryPoint("a"))
+	require.NoError(t, g.AddEdge("a", "b"))
+	require.NoError(t, g.SetFinishPoint("b"))
+	compiled, err := g.Compile(graphs.WithCheckpointer(c))
+	require.NoError(t, err)
+
+	_, err = compiled.Invoke(ctx, graphs.State{"visited": []string{}}, graphs.WithThreadID("t1"))
+	require.ErrorIs(t, err, assert.AnError)
+
+	fail = false
+	state, err := compiled.Resume(ctx, "t1")
+	require.NoError(t, err)
+	visited, _ := graphs.Get[[]string](state, "visited")
+	assert.Equal(t, []string{"a", "b"}, visited)
+}
+
+func TestCheckpointerResumeTypedReducer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c, err := sqlite3.NewCheckpointer(ctx)
+	require.NoError(t, err)
+
+	fail := true
+	g := graphs.NewGraph()
+	require.NoError(t, g.AddChannel("n", graphs.MergeFunc(func(current, update int) (int, error) {
+		return current + update, nil
+	})))
+	require.NoError(t, g.AddNode("a", func(context.Context, graphs.State) (graphs.State, error) {
+		return graphs.State{"n": 1}, nil
+	}))
+	require.NoError(t, g.AddNode("b", func(context.Context, graphs.State) (graphs.State, error) {
+		if fail {
+			return nil, assert.AnError
+		}
+		return graphs.State{"n": 2}, nil
+	}))
+	require.NoError(t, g.SetEntryPoint("a"))
+	require.NoError(t, g.AddEdge("a", "b"))
+	require.NoError(t, g.SetFinishPoint("b"))
+	compiled, err := g.Compile(graphs.WithCheckpointer(c))
+	require.NoError(t, err)
+
+	_, err = compiled.Invoke(ctx, nil, graphs.WithThreadID("t1"))
+	require.ErrorIs(t, err, assert.AnError)
+
+	fail = false
+	state, err := compiled.Resume(ctx, "t1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, state["n"])
+}
@@ -0,0 +1,62 @@
+package graphs
+
+import (
+	"encoding/json"
+	"maps"
+)
+
+// State is the value threaded through a graph run. Every node receives a copy
+// of the current state and returns an update holding only the keys it wants to
+// change. The runtime merges each update into the state before moving on.
+type State map[string]any
+
+// Clone returns a shallow copy of the state.
+func (s State) Clone() State {
+	c := make(State, len(s))
+	maps.Copy(c, s)
+	return c
+}
+
+// Merge returns a new state with the keys of update written over the keys of s.
+// Neither s nor update is modified.
+func (s State) Merge(update State) State {
+	merged := s.Clone()
+	maps.Copy(merged, update)
+	return merged
+}
+
+// Get returns the value stored under key as a T. The boolean is false if the
+// key is missing or holds a value that can not be converted to T.
+//
+// States loaded from a checkpoint hold the generic types produced by decoding
+// JSON, such as float64 and map[string]any. Such values are converted to T by
+// encoding them back to JSON and decoding the result into a T.
+func Get[T any](s State, key string) (T, bool) {
+	raw, ok := s[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return convert[T](raw)
+}
+
+// convert returns raw as a T, converting the values decoded from JSON like Get.
+func convert[T any](raw any) (T, bool) {
+	var zero T
+	if raw == nil {
+		return zero, false
+	}
+	if v, ok := raw.(T); ok {
+		return v, true
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return zero, false
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return zero, false
+	}
+	return v, true
+}
@@ -0,0 +1,267 @@
+package graphs
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// StreamMode selects the events emitted by Stream. Modes can be combined with
+// the | operator.
+type StreamMode int
+
+const (
+	// StreamValues emits the full state after every step.
+	StreamValues StreamMode = 1 << iota
+	// StreamUpdates emits the update of every node after every step.
+	StreamUpdates
+	// StreamMessages emits the chunks streamed by LLMs called from nodes with
+	// StreamingFunc or CallbacksHandler.
+	StreamMessages
+	// StreamTools emits an event when a tool called by a node starts, ends or
+	// fails.
+	StreamTools
+)
+
+// ToolEventType is the kind of a tool event.
+type ToolEventType string
+
+const (
+	// ToolStart is emitted before a tool is called.
+	ToolStart ToolEventType = "start"
+	// ToolEnd is emitted after a tool returned.
+	ToolEnd ToolEventType = "end"
+	// ToolError is emitted if a tool failed.
+	ToolError ToolEventType = "error"
+)
+
+// ToolEvent describes a tool call made by a node.
+type ToolEvent struct {
+	Type ToolEventType
+	// Tool is the name of the tool. It is empty for tools reported through
+	// CallbacksHandler, as the callbacks do not carry it.
+	Tool   string
+	Input  string
+	Output string
+	Err    error
+}
+
+// Event is emitted by Stream while a graph runs. Mode tells which of the other
+// fields is set.
+type Event struct {
+	Mode StreamMode
+	// Node is the node the event comes from. It is empty for StreamValues
+	// events of the graph itself. Events of subgraphs are prefixed with the path
+	// of the subgraph node, such as "team/tools", and their StreamValues events
+	// carry the path of the subgraph node.
+	Node string
+	// Step is the step the event belongs to, counted in the graph or subgraph
+	// the event comes from.
+	Step int
+	// State is the state after the step, for StreamValues.
+	State State
+	// Update is the update the node wrote, for StreamUpdates.
+	Update State
+	// Chunk is a chunk streamed by an LLM, for StreamMessages.
+	Chunk []byte
+	// Tool describes a tool call, for StreamTools.
+	Tool *ToolEvent
+}
+
+// EventSeq is an iterator over the events of a streamed run. Once the run
+// ends it yields a final zero event with the error of the run, if any. With
+// Go 1.23 or later it can be used with range:
+//
+//	for event, err := range compiled.Stream(ctx, input, graphs.StreamUpdates) {
+//		...
+//	}
+type EventSeq func(yield func(Event, error) bool)
+
+// Stream compiles the graph and runs it like Execute, emitting the events
+// selected by mode.
+func (g *Graph) Stream(ctx context.Context, input State, mode StreamMode, opts ...RunOption) EventSeq {
+	c, err := g.Compile()
+	if err != nil {
+		return func(yield func(Event, error) bool) {
+			yield(Event{}, err)
+		}
+	}
+	return c.Stream(ctx, input, mode, opts...)
+}
+
+// Stream runs the graph like Invoke and returns an iterator over the events
+// selected by mode. The run starts when the iteration starts and is canceled if
+// the iteration stops early or ctx is canceled.
+func (c *CompiledGraph) Stream(ctx context.Context, input State, mode StreamMode, opts ...RunOption) EventSeq {
+	return stream(ctx, mode, func(ctx context.Context, e *emitter) error {
+		_, err := c.Invoke(ctx, input, append(opts, withEmitter(e))...)
+		return err
+	})
+}
+
+// StreamResume resumes a run like Resume and returns an iterator over the
+// events selected by mode.
+func (c *CompiledGraph) StreamResume(ctx context.Context, threadID string, mode StreamMode, opts ...RunOption) EventSeq {
+	return stream(ctx, mode, func(ctx context.Context, e *emitter) error {
+		_, err := c.Resume(ctx, threadID, append(opts, withEmitter(e))...)
+		return err
+	})
+}
+
+func stream(ctx context.Context, mode StreamMode, runFn func(context.Context, *emitter) error) EventSeq {
+	return func(yield func(Event, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		e := &emitter{
+			mode:   mode,
+			events: make(chan Event),
+			acks:   make(chan struct{}),
+			done:   ctx.Done(),
+		}
+		errc := make(chan error, 1)
+		go func() {
+			defer close(e.events)
+			errc <- runFn(ctx, e)
+		}()
+
+		for event := range e.events {
+			if !yield(event, nil) {
+				cancel()
+				for range e.events { //nolint:revive // Drain until the run stops.
+				}
+				return
+			}
+			select {
+			case e.acks <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if err := <-errc; err != nil {
+			yield(Event{}, err)
+		}
+	}
+}
+
+// emitter sends the events of a run to a stream. Every event is acknowledged
+// once the consumer handled it, so the run does not get ahead of the consumer
+// and stops right after the consumer stopped.
+type emitter struct {
+	mode   StreamMode
+	events chan Event
+	acks   chan struct{}
+	done   <-chan struct{}
+	// prefix is the path of the subgraph node the events come from.
+	prefix string
+}
+
+// namespace returns an emitter for the events of the subgraph of a node.
+func (e *emitter) namespace(node string) *emitter {
+	if e == nil {
+		return nil
+	}
+	sub := *e
+	sub.prefix = e.prefix + node + "/"
+	return &sub
+}
+
+func (e *emitter) emit(event Event) {
+	if e == nil || e.mode&event.Mode == 0 {
+		return
+	}
+	if e.prefix != "" {
+		event.Node = strings.TrimSuffix(e.prefix+event.Node, "/")
+	}
+	select {
+	case e.events <- event:
+	case <-e.done:
+		return
+	}
+	select {
+	case <-e.acks:
+	case <-e.done:
+	}
+}
+
+type nodeContextKey struct{}
+
+// nodeContext tells what node a context passed to a node function belongs to.
+type nodeContext struct {
+	emitter *emitter
+	node    string
+	step    int
+}
+
+func withNode(ctx context.Context, e *emitter, node string, step int) context.Context {
+	return context.WithValue(ctx, nodeContextKey{}, &nodeContext{emitter: e, node: node, step: step})
+}
+
+func nodeFromContext(ctx context.Context) *nodeContext {
+	nc, _ := ctx.Value(nodeContextKey{}).(*nodeContext)
+	if nc == nil {
+		return &nodeContext{}
+	}
+	return nc
+}
+
+func (nc *nodeContext) emit(event Event) {
+	event.Node, event.Step = nc.node, nc.step
+	nc.emitter.emit(event)
+}
+
+// StreamingFunc returns a function for llms.WithStreamingFunc that emits the
+// chunks streamed by an LLM as StreamMessages events of the node running with
+// ctx. Outside of a streamed run the chunks are dropped.
+func StreamingFunc(ctx context.Context) func(context.Context, []byte) error {
+	nc := nodeFromContext(ctx)
+	return func(_ context.Context, chunk []byte) error {
+		nc.emit(Event{Mode: StreamMessages, Chunk: append([]byte(nil), chunk...)})
+		return nil
+	}
+}
+
+// CallbacksHandler returns a callbacks handler that emits the streamed LLM
+// chunks and tool calls reported to it as events of the node running with ctx.
+// It lets agents and chains called from a node take part in streaming.
+func CallbacksHandler(ctx context.Context) callbacks.Handler { //nolint:ireturn
+	return &streamHandler{node: nodeFromContext(ctx)}
+}
+
+type streamHandler struct {
+	callbacks.SimpleHandler
+	node *nodeContext
+}
+
+func (h *streamHandler) HandleStreamingFunc(_ context.Context, chunk []byte) {
+	h.node.emit(Event{Mode: StreamMessages, Chunk: append([]byte(nil), chunk...)})
+}
+
+func (h *streamHandler) HandleToolStart(_ context.Context, input string) {
+	h.node.emit(Event{Mode: StreamTools, Tool: &ToolEvent{Type: ToolStart, Input: input}})
+}
+
+func (h *streamHandler) HandleToolEnd(_ context.Context, output string) {
+	h.node.emit(Event{Mode: StreamTools, Tool: &ToolEvent{Type: ToolEnd, Output: output}})
+}
+
+func (h *streamHandler) HandleToolError(_ context.Context, err error) {
+	h.node.emit(Event{Mode: StreamTools, Tool: &ToolEvent{Type: ToolError, Err: err}})
+}
+
+// callTool calls tool and emits its start and end, or its error, as events of
+// the node running with ctx.
+func callTool(ctx context.Context, tool tools.Tool, input string) (string, error) {
+	nc := nodeFromContext(ctx)
+	nc.emit(Event{Mode: StreamTools, Tool: &ToolEvent{Type: ToolStart, Tool: tool.Name(), Input: input}})
+	output, err := tool.Call(ctx, input)
+	if err != nil {
+		nc.emit(Event{Mode: StreamTools, Tool: &ToolEvent{Type: ToolError, Tool: tool.Name(), Input: input, Err: err}})
+		return "", err
+	}
+	nc.emit(Event{Mode: StreamTools, Tool: &ToolEvent{
+		Type: ToolEnd, Tool: tool.Name(), Input: input, Output: output,
+	}})
+	return output, nil
+}
@@ -0,0 +1,153 @@
+package graphs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func collect(t *testing.T, seq EventSeq) ([]Event, error) {
+	t.Helper()
+	var (
+		events []Event
+		runErr error
+	)
+	seq(func(event Event, err error) bool {
+		if err != nil {
+			runErr = err
+			return false
+		}
+		events = append(events, event)
+		return true
+	})
+	return events, runErr
+}
+
+func TestStreamValuesAndUpdates(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph()
+	require.NoError(t, g.AddNode("a", constNode("a", 1)))
+	require.NoError(t, g.AddNode("b", constNode("b", 2)))
+	require.NoError(t, g.SetEntryPoint("a"))
+	require.NoError(t, g.AddEdge("a", "b"))
+	require.NoError(t, g.SetFinishPoint("b"))
+
+	events, err := collect(t, g.Stream(context.Background(), State{"in": 0}, StreamValues|StreamUpdates))
+	require.NoError(t, err)
+	assert.Equal(t, []Event{
+		{Mode: StreamUpdates, Node: "a", Step: 1, Update: State{"a": 1}},
+		{Mode: StreamValues, Step: 1, State: State{"in": 0, "a": 1}},
+		{Mode: StreamUpdates, Node: "b", Step: 2, Update: State{"b": 2}},
+		{Mode: StreamValues, Step: 2, State: State{"in": 0, "a": 1, "b": 2}},
+	}, events)
+}
+
+func TestStreamMessagesAndTools(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph()
+	require.NoError(t, g.AddNode("llm", func(ctx context.Context, _ State) (State, error) {
+		streamingFunc := StreamingFunc(ctx)
+		for _, chunk := range []string{"Hel", "lo"} {
+			if err := streamingFunc(ctx, []byte(chunk)); err != nil {
+				return nil, err
+			}
+		}
+		return State{ActionsKey: []schema.AgentAction{{Tool: "upper", ToolInput: "hi"}}}, nil
+	}))
+	require.NoError(t, g.AddNode("tools", ToolNode([]tools.Tool{upperTool{}})))
+	require.NoError(t, g.SetEntryPoint("llm"))
+	require.NoError(t, g.AddEdge("llm", "tools"))
+	require.NoError(t, g.SetFinishPoint("tools"))
+
+	events, err := collect(t, g.Stream(context.Background(), nil, StreamMessages|StreamTools))
+	require.NoError(t, err)
+	assert.Equal(t, []Event{
+		{Mode: StreamMessages, Node: "llm", Step: 1, Chunk: []byte("Hel")},
+		{Mode: StreamMessages, Node: "llm", Step: 1, Chunk: []byte("lo")},
+		{Mode: StreamTools, Node: "tools", Step: 2, Tool: &ToolEvent{Type: ToolStart, Tool: "upper", Input: "hi"}},
+		{Mode: StreamTools, Node: "tools", Step: 2, Tool: &ToolEvent{
+			Type: ToolEnd, Tool: "upper", Input: "hi", Output: "HI",
+		}},
+	}, events)
+}
+
+func TestStreamError(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+	g := NewGraph()
+	require.NoError(t, g.AddNode("a", func(context.Context, State) (State, error) {
+		return nil, errBoom
+	}))
+	require.NoError(t, g.SetEntryPoint("a"))
+	require.NoError(t, g.SetFinishPoint("a"))
+
+	_, err := collect(t, g.Stream(context.Background(), nil, StreamValues))
+	require.ErrorIs(t, err, errBoom)
+
+	_, err = collect(t, NewGraph().Stream(context.Background(), nil, StreamValues))
+	require.ErrorIs(t, err, ErrNoEntryPoint)
+}
+
+func TestStreamStopsRun(t *testing.T) {
+	t.Parallel()
+
+	ran := make(chan string, 10)
+	g := NewGraph()
+	for _, name := range []string{"a", "b", "c"} {
+		name := name
+		require.NoError(t, g.AddNode(name, func(context.Context, State) (State, error) {
+			ran <- name
+			return State{name: true}, nil
+		}))
+	}
+	require.NoError(t, g.SetEntryPoint("a"))
+	require.NoError(t, g.AddEdge("a", "b"))
+	require.NoError(t, g.AddEdge("b", "c"))
+	require.NoError(t, g.SetFinishPoint("c"))
+
+	var events []Event
+	g.Stream(context.Background(), nil, StreamUpdates)(func(event Event, err error) bool {
+		require.NoError(t, err)
+		events = append(events, event)
+		return false
+	})
+	close(ran)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "a", events[0].Node)
+	var nodes []string
+	for name := range ran {
+		nodes = append(nodes, name)
+	}
+	assert.Equal(t, []string{"a"}, nodes)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := collect(t, g.Stream(ctx, nil, StreamUpdates))
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestStreamingAgentStreamOutput(t *testing.T) {
+	t.Parallel()
+
+	agent := NewStreamingAgent()
+	agent.Tools = []tools.Tool{upperTool{}}
+	agent.InitializeStreamingActions([]schema.AgentAction{{Tool: "upper", ToolInput: "hi"}})
+
+	outputChan := make(chan string, 1)
+	require.NoError(t, agent.StreamOutput(context.Background(), outputChan))
+	assert.Equal(t, "upper: HI", <-outputChan)
+	assert.Equal(t, State{"upper": "HI"}, agent.Results)
+
+	// The channel is owned by the caller and still open.
+	outputChan <- "more"
+	assert.Equal(t, "more", <-outputChan)
+}
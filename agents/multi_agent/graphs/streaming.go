@@ -1,9 +1,8 @@
-package multi_agent
+package graphs
 
 import (
 	"context"
 	"fmt"
-	"sync"
 
 	"github.com/tmc/langchaingo/agents"
 	"github.com/tmc/langchaingo/schema"
@@ -14,6 +13,11 @@ import (
 type StreamingAgent struct {
 	Graph *Graph
 	Tools []tools.Tool
+	// Results holds the observations of the last executed actions keyed by
+	// tool name.
+	Results State
+	// Err is the error of the last execution, if any.
+	Err error
 }
 
 var _ agents.Agent = (*StreamingAgent)(nil)
@@ -61,50 +65,59 @@ func (a *StreamingAgent) GetTools() []tools.Tool {
 
 // InitializeStreamingActions initializes the streaming actions for the agent.
 func (a *StreamingAgent) InitializeStreamingActions(actions []schema.AgentAction) {
-	for _, action := range actions {
-		node := &Node{
-			ID:      len(a.Graph.Nodes),
-			Value:   action,
-			State:   "initialized",
-			Actions: []interface{}{action},
-		}
-		a.Graph.AddNode(node)
-	}
+	a.Graph = newActionGraph(a.Tools, actions)
 }
 
 // ExecuteStreamingActions executes the streaming actions for the agent.
 func (a *StreamingAgent) ExecuteStreamingActions() {
-	var wg sync.WaitGroup
-	for _, node := range a.Graph.Nodes {
-		wg.Add(1)
-		go func(n *Node) {
-			defer wg.Done()
-			for _, action := range n.Actions {
-				// Implement the logic for self-reflecting or human-in-the-loop tasks.
-				// This is a placeholder implementation.
-				n.mu.Lock()
-				n.State = "executing"
-				n.mu.Unlock()
-				// Simulate action execution
-				n.mu.Lock()
-				n.State = "completed"
-				n.mu.Unlock()
+	a.Results, a.Err = a.Graph.Execute(context.Background(), nil)
+}
+
+// Stream executes the streaming actions and returns an iterator over the
+// events selected by mode. Results and Err are set once the run ends.
+func (a *StreamingAgent) Stream(ctx context.Context, mode StreamMode) EventSeq {
+	return func(yield func(Event, error) bool) {
+		a.Results, a.Err = nil, nil
+		a.Graph.Stream(ctx, nil, mode|StreamValues)(func(event Event, err error) bool {
+			if err != nil {
+				a.Err = err
+				return yield(event, err)
 			}
-		}(node)
+			if event.Mode == StreamValues {
+				a.Results = event.State
+				if mode&StreamValues == 0 {
+					return true
+				}
+			}
+			return yield(event, nil)
+		})
 	}
-	wg.Wait()
-	a.Graph.Execute()
 }
 
-// StreamOutput streams the output of the agent's actions.
+// StreamOutput executes the streaming actions and sends a line with the tool
+// name and observation to outputChan as soon as every tool returned. The
+// channel is owned by the caller and is not closed.
 func (a *StreamingAgent) StreamOutput(ctx context.Context, outputChan chan<- string) error {
-	for _, node := range a.Graph.Nodes {
-		for _, action := range node.Actions {
-			// Implement the logic to stream the output of the action.
-			// This is a placeholder implementation.
-			outputChan <- fmt.Sprintf("Streaming output for action: %v", action)
+	var err error
+	a.Stream(ctx, StreamTools)(func(event Event, runErr error) bool {
+		if runErr != nil {
+			err = runErr
+			return false
 		}
-	}
-	close(outputChan)
-	return nil
+		line := fmt.Sprintf("%s: %s", event.Tool.Tool, event.Tool.Output)
+		switch event.Tool.Type {
+		case ToolStart:
+			return true
+		case ToolError:
+			line = fmt.Sprintf("%s: %v", event.Tool.Tool, event.Tool.Err)
+		}
+		select {
+		case outputChan <- line:
+			return true
+		case <-ctx.Done():
+			err = ctx.Err()
+			return false
+		}
+	})
+	return err
 }
@@ -0,0 +1,308 @@
+package graphs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// subgraph is a compiled graph run as a node of another graph.
+type subgraph struct {
+	graph *CompiledGraph
+	// input maps keys of the parent state to keys of the subgraph state, and
+	// output maps keys of the subgraph state back to keys of the parent state.
+	input  map[string]string
+	output map[string]string
+}
+
+// SubgraphOption is a function that configures a subgraph node.
+type SubgraphOption func(*subgraph)
+
+// WithInputKeys is an option for AddSubgraph mapping keys of the parent state
+// to the keys of the subgraph state they are passed in as. Without it the
+// subgraph starts from the whole parent state.
+func WithInputKeys(keys map[string]string) SubgraphOption {
+	return func(s *subgraph) {
+		s.input = keys
+	}
+}
+
+// WithOutputKeys is an option for AddSubgraph mapping keys of the final
+// subgraph state to the keys of the parent state they are written to. Without
+// it the keys the subgraph changed are written to the parent state as they
+// are.
+func WithOutputKeys(keys map[string]string) SubgraphOption {
+	return func(s *subgraph) {
+		s.output = keys
+	}
+}
+
+// AddSubgraph adds a node that runs a compiled graph. The subgraph runs to its
+// end within a single step of the parent graph and its result is written to
+// the parent state like the update of any other node. A slice written to a key
+// with a reducer in the parent is cut to the elements past the parent value it
+// starts with, so that a subgraph appending to a list the parent passed in
+// does not make Append add the list twice.
+//
+// The subgraph uses the checkpointer of the parent graph, if any, and saves
+// its checkpoints under a thread named after the parent thread, the node and
+// the step, such as "thread/node:3". Events streamed from the subgraph and the
+// nodes of its interrupts are prefixed with the node name, such as
+// "node/tools". An interrupt in the subgraph pauses the parent run, and the
+// decision given to Resume is passed on to the subgraph.
+func (g *Graph) AddSubgraph(name string, sub *CompiledGraph, opts ...SubgraphOption) error {
+	if sub == nil {
+		return fmt.Errorf("%w: node %q has no subgraph", ErrInvalidNodeName, name)
+	}
+	if strings.Contains(name, "/") {
+		return fmt.Errorf("%w: %q contains a slash", ErrInvalidNodeName, name)
+	}
+	s := &subgraph{graph: sub}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return g.AddNode(name, s.unreachable, func(n *Node) {
+		n.subgraph = s
+	})
+}
+
+// unreachable is the function of subgraph nodes. Runs call runSubgraph
+// instead.
+func (s *subgraph) unreachable(context.Context, State) (State, error) {
+	return nil, errors.New("subgraph node run outside of a graph run")
+}
+
+// Subgraph returns the graph run by the node, or nil if the node is not a
+// subgraph.
+func (n *Node) Subgraph() *CompiledGraph {
+	if n.subgraph == nil {
+		return nil
+	}
+	return n.subgraph.graph
+}
+
+// hasInterrupts tells whether a node of the graph or of one of its subgraphs
+// has an interrupt.
+func (c *CompiledGraph) hasInterrupts() bool {
+	for _, node := range c.nodes {
+		if node.InterruptBefore || node.InterruptAfter {
+			return true
+		}
+		if node.subgraph != nil && node.subgraph.graph.hasInterrupts() {
+			return true
+		}
+	}
+	return false
+}
+
+// subgraphRun returns the graph of a subgraph node set up to run with the
+// checkpointer of c.
+func (c *CompiledGraph) subgraphRun(name string) *CompiledGraph {
+	sub := *c.nodes[name].subgraph.graph
+	sub.checkpointer = c.checkpointer
+	return &sub
+}
+
+// subgraphThread returns the thread the subgraph node name saves its
+// checkpoints under when run in the current step.
+func (r *run) subgraphThread(name string) string {
+	return fmt.Sprintf("%s/%s:%d", r.threadID, name, r.step)
+}
+
+// runSubgraph runs the subgraph of a node. The subgraph resumes with the
+// pending decision of the run if there is one, or from its latest checkpoint if
+// the run was resumed and the subgraph already started, for instance before a
+// crash.
+func (r *run) runSubgraph(ctx context.Context, name string, state State) (State, error) {
+	s := r.graph.nodes[name].subgraph
+	sub := r.graph.subgraphRun(name)
+	threadID := r.subgraphThread(name)
+	opts := []RunOption{WithThreadID(threadID), withEmitter(r.emitter.namespace(name))}
+
+	var (
+		final State
+		err   error
+	)
+	switch {
+	case r.subgraphDecision != nil:
+		final, err = sub.Resume(ctx, threadID, append(opts, WithDecision(*r.subgraphDecision))...)
+	case r.restored && sub.checkpointer != nil && sub.started(ctx, threadID):
+		final, err = sub.Resume(ctx, threadID, opts...)
+	default:
+		final, err = sub.Invoke(ctx, s.inputState(state), opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.outputUpdate(state, final, r.graph.channels), nil
+}
+
+func (c *CompiledGraph) started(ctx context.Context, threadID string) bool {
+	_, err := c.checkpointer.Latest(ctx, threadID)
+	return err == nil
+}
+
+func (s *subgraph) inputState(state State) State {
+	if s.input == nil {
+		return state.Clone()
+	}
+	input := make(State, len(s.input))
+	for from, to := range s.input {
+		if v, ok := state[from]; ok {
+			input[to] = v
+		}
+	}
+	return input
+}
+
+// outputUpdate returns the update of the parent state from the final state of
+// the subgraph. channels are the reducers of the parent graph.
+func (s *subgraph) outputUpdate(parent, final State, channels map[string]Reducer) State {
+	update := State{}
+	set := func(key string, v any) {
+		if _, ok := channels[key]; ok {
+			if v, ok = newElements(parent[key], v); !ok {
+				return
+			}
+		}
+		update[key] = v
+	}
+	if s.output == nil {
+		input := s.inputState(parent)
+		for key, v := range final {
+			if old, ok := input[key]; !ok || !reflect.DeepEqual(old, v) {
+				set(key, v)
+			}
+		}
+		return update
+	}
+	for from, to := range s.output {
+		if v, ok := final[from]; ok {
+			set(to, v)
+		}
+	}
+	return update
+}
+
+// newElements returns the elements of the slice v past the slice current it
+// starts with, and false if there are none. Other values are returned as they
+// are.
+func newElements(current, v any) (any, bool) {
+	cur, val := reflect.ValueOf(current), reflect.ValueOf(v)
+	if cur.Kind() != reflect.Slice || val.Kind() != reflect.Slice || val.Len() < cur.Len() {
+		return v, true
+	}
+	for i := 0; i < cur.Len(); i++ {
+		if !reflect.DeepEqual(cur.Index(i).Interface(), val.Index(i).Interface()) {
+			return v, true
+		}
+	}
+	if val.Len() == cur.Len() {
+		return nil, false
+	}
+	return val.Slice(cur.Len(), val.Len()).Interface(), true
+}
+
+// interruptSubgraphs pauses the run because the subgraphs of some nodes of the
+// step were interrupted. writes holds the writes of the nodes that finished.
+func (r *run) interruptSubgraphs(
+	ctx context.Context,
+	writes []write,
+	interrupted map[string]*Interrupt,
+) (State, error) {
+	i := &Interrupt{
+		ThreadID:  r.threadID,
+		Step:      r.step,
+		Subgraphs: make(map[string]*Interrupt, len(interrupted)),
+	}
+	for _, name := range sortedKeys(interrupted) {
+		sub := interrupted[name]
+		if i.Point == "" {
+			i.Point = sub.Point
+		}
+		for _, node := range sub.Nodes {
+			i.Nodes = append(i.Nodes, name+"/"+node)
+		}
+		i.Subgraphs[name] = &Interrupt{
+			ThreadID:  sub.ThreadID,
+			Step:      sub.Step,
+			Nodes:     sub.Nodes,
+			Point:     sub.Point,
+			Subgraphs: sub.Subgraphs,
+		}
+	}
+
+	r.pending = i
+	r.pendingWrites = writes
+	if err := r.checkpoint(ctx); err != nil {
+		return r.state, err
+	}
+	d := r.describe(i)
+	if err := r.graph.describeSubgraphs(ctx, d); err != nil {
+		return r.state, err
+	}
+	return r.state, d
+}
+
+// resumeSubgraphs runs the interrupted subgraphs of the pending step again,
+// passing on the decision, and finishes the step.
+func (r *run) resumeSubgraphs(ctx context.Context, i *Interrupt, writes []write, d Decision) (State, error) {
+	r.subgraphDecision = &d
+	resumed, interrupted, err := r.runStep(ctx, sortedKeys(i.Subgraphs), nil)
+	r.subgraphDecision = nil
+	if err != nil {
+		return r.state, err
+	}
+
+	writes = append(writes, resumed...)
+	sortWrites(writes)
+	if len(interrupted) > 0 {
+		return r.interruptSubgraphs(ctx, writes, interrupted)
+	}
+	return r.afterStep(ctx, writes, nil)
+}
+
+// describeSubgraphs fills in the parts of an interrupt of subgraph nodes that
+// are stored in the checkpoints of the subgraphs: the interrupts of the
+// subgraphs, with their state, and their writes and tool calls.
+func (c *CompiledGraph) describeSubgraphs(ctx context.Context, d *Interrupt) error {
+	if d == nil || len(d.Subgraphs) == 0 || c.checkpointer == nil {
+		return nil
+	}
+
+	subgraphs := make(map[string]*Interrupt, len(d.Subgraphs))
+	d.Writes, d.Actions = nil, nil
+	for _, name := range sortedKeys(d.Subgraphs) {
+		node, ok := c.nodes[name]
+		if !ok || node.subgraph == nil {
+			return fmt.Errorf("%w: subgraph %q", ErrUnknownNode, name)
+		}
+		sub := c.subgraphRun(name)
+		cp, err := sub.checkpointer.Latest(ctx, d.Subgraphs[name].ThreadID)
+		if err != nil {
+			return err
+		}
+		if cp.Interrupt == nil {
+			subgraphs[name] = d.Subgraphs[name]
+			continue
+		}
+
+		r := &run{state: cp.State, pendingWrites: writesFromNodes(cp.PendingWrites)}
+		sd := r.describe(cp.Interrupt)
+		if err := sub.describeSubgraphs(ctx, sd); err != nil {
+			return err
+		}
+		subgraphs[name] = sd
+		d.Actions = append(d.Actions, sd.Actions...)
+		for node, w := range sd.Writes {
+			if d.Writes == nil {
+				d.Writes = make(map[string]State)
+			}
+			d.Writes[name+"/"+node] = w
+		}
+	}
+	d.Subgraphs = subgraphs
+	return nil
+}
@@ -0,0 +1,249 @@
+package graphs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// newTeamGraph returns a parent graph running the review graph as the
+// subgraph "team" between two nodes.
+func newTeamGraph(t *testing.T, checkpointer Checkpointer, toolOpts ...NodeOption) *CompiledGraph {
+	t.Helper()
+
+	g := NewGraph()
+	require.NoError(t, g.AddNode("plan", constNode("task", "review")))
+	require.NoError(t, g.AddSubgraph("team", newReviewGraph(t, NewMemoryCheckpointer(), toolOpts...),
+		WithInputKeys(map[string]string{"task": "task"}),
+		WithOutputKeys(map[string]string{StepsKey: "team_steps"}),
+	))
+	require.NoError(t, g.AddNode("report", func(_ context.Context, s State) (State, error) {
+		return State{"done": len(steps(t, State{StepsKey: s["team_steps"]}))}, nil
+	}))
+	require.NoError(t, g.SetEntryPoint("plan"))
+	require.NoError(t, g.AddEdge("plan", "team"))
+	require.NoError(t, g.AddEdge("team", "report"))
+	require.NoError(t, g.SetFinishPoint("report"))
+
+	c, err := g.Compile(WithCheckpointer(checkpointer))
+	require.NoError(t, err)
+	return c
+}
+
+func TestSubgraph(t *testing.T) {
+	t.Parallel()
+
+	child := NewGraph()
+	require.NoError(t, child.AddNode("double", func(_ context.Context, s State) (State, error) {
+		n, _ := Get[int](s, "n")
+		return State{"n": n * 2, "scratch": true}, nil
+	}))
+	require.NoError(t, child.SetEntryPoint("double"))
+	require.NoError(t, child.SetFinishPoint("double"))
+	sub, err := child.Compile()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		opts []SubgraphOption
+		want State
+	}{
+		{
+			name: "shared state",
+			want: State{"n": 4, "scratch": true, "other": "kept"},
+		},
+		{
+			name: "mapped keys",
+			opts: []SubgraphOption{
+				WithInputKeys(map[string]string{"value": "n"}),
+				WithOutputKeys(map[string]string{"n": "result"}),
+			},
+			want: State{"value": 2, "result": 4, "other": "kept"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			g := NewGraph()
+			require.NoError(t, g.AddSubgraph("sub", sub, tc.opts...))
+			require.NoError(t, g.SetEntryPoint("sub"))
+			require.NoError(t, g.SetFinishPoint("sub"))
+
+			input := State{"n": 2, "other": "kept"}
+			if tc.opts != nil {
+				input = State{"value": 2, "other": "kept"}
+			}
+			state, err := g.Execute(context.Background(), input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, state)
+		})
+	}
+}
+
+func TestSubgraphAppendChannel(t *testing.T) {
+	t.Parallel()
+
+	child := NewGraph()
+	require.NoError(t, child.AddChannel("items", Append))
+	require.NoError(t, child.AddNode("add", constNode("items", "sub")))
+	require.NoError(t, child.SetEntryPoint("add"))
+	require.NoError(t, child.SetFinishPoint("add"))
+	sub, err := child.Compile()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		opts []SubgraphOption
+	}{
+		{name: "shared state"},
+		{
+			name: "mapped keys",
+			opts: []SubgraphOption{
+				WithInputKeys(map[string]string{"items": "items"}),
+				WithOutputKeys(map[string]string{"items": "items"}),
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			g := NewGraph()
+			require.NoError(t, g.AddChannel("items", Append))
+			require.NoError(t, g.AddNode("parent", constNode("items", "in parent")))
+			require.NoError(t, g.AddSubgraph("sub", sub, tc.opts...))
+			require.NoError(t, g.SetEntryPoint("parent"))
+			require.NoError(t, g.AddEdge("parent", "sub"))
+			require.NoError(t, g.SetFinishPoint("sub"))
+
+			state, err := g.Execute(context.Background(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"in parent", "sub"}, state["items"])
+		})
+	}
+}
+
+func TestSubgraphErrors(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph()
+	require.ErrorIs(t, g.AddSubgraph("sub", nil), ErrInvalidNodeName)
+	require.ErrorIs(t, g.AddSubgraph("a/b", &CompiledGraph{}), ErrInvalidNodeName)
+
+	// A subgraph with interrupts needs a checkpointer in the parent graph.
+	require.NoError(t, g.AddSubgraph("team", newReviewGraph(t, NewMemoryCheckpointer(), InterruptBefore())))
+	require.NoError(t, g.SetEntryPoint("team"))
+	require.NoError(t, g.SetFinishPoint("team"))
+	_, err := g.Compile()
+	require.ErrorIs(t, err, ErrNoCheckpointer)
+
+	errBoom := errors.New("boom")
+	child := NewGraph()
+	require.NoError(t, child.AddNode("fail", func(context.Context, State) (State, error) {
+		return nil, errBoom
+	}))
+	require.NoError(t, child.SetEntryPoint("fail"))
+	require.NoError(t, child.SetFinishPoint("fail"))
+	sub, err := child.Compile()
+	require.NoError(t, err)
+
+	g = NewGraph()
+	require.NoError(t, g.AddSubgraph("sub", sub))
+	require.NoError(t, g.SetEntryPoint("sub"))
+	require.NoError(t, g.SetFinishPoint("sub"))
+	_, err = g.Execute(context.Background(), nil)
+	require.ErrorIs(t, err, errBoom)
+}
+
+func TestSubgraphInterrupt(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	checkpointer := NewMemoryCheckpointer()
+	c := newTeamGraph(t, checkpointer, InterruptBefore(), OnReject(RejectToolCalls))
+
+	_, err := c.Invoke(ctx, nil, WithThreadID("thread"))
+	var interrupt *Interrupt
+	require.ErrorAs(t, err, &interrupt)
+	assert.Equal(t, []string{"team/tools"}, interrupt.Nodes)
+	assert.Equal(t, InterruptPointBefore, interrupt.Point)
+	assert.Equal(t, 1, interrupt.Step)
+	assert.Equal(t, []schema.AgentAction{{Tool: "upper", ToolInput: "hello"}}, interrupt.Actions)
+	require.Contains(t, interrupt.Subgraphs, "team")
+	assert.Equal(t, "thread/team:1", interrupt.Subgraphs["team"].ThreadID)
+	assert.Equal(t, []string{"tools"}, interrupt.Subgraphs["team"].Nodes)
+
+	// The subgraph checkpoints are stored under the namespaced thread.
+	history, err := checkpointer.List(ctx, "thread/team:1")
+	require.NoError(t, err)
+	assert.NotEmpty(t, history)
+
+	_, err = c.Resume(ctx, "thread")
+	require.ErrorIs(t, err, ErrDecisionRequired)
+	require.ErrorAs(t, err, &interrupt)
+	assert.Equal(t, []schema.AgentAction{{Tool: "upper", ToolInput: "hello"}}, interrupt.Actions)
+
+	state, err := c.Resume(ctx, "thread", WithDecision(Decision{
+		Type:    DecisionEdit,
+		Actions: []schema.AgentAction{{Tool: "upper", ToolInput: "edited"}},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, 1, state["done"])
+	teamSteps, ok := Get[[]schema.AgentStep](state, "team_steps")
+	require.True(t, ok)
+	assert.Equal(t, []schema.AgentStep{
+		{Action: schema.AgentAction{Tool: "upper", ToolInput: "edited"}, Observation: "EDITED"},
+	}, teamSteps)
+}
+
+func TestSubgraphStream(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph()
+	require.NoError(t, g.AddSubgraph("team", newReviewGraph(t, NewMemoryCheckpointer())))
+	require.NoError(t, g.SetEntryPoint("team"))
+	require.NoError(t, g.SetFinishPoint("team"))
+	c, err := g.Compile(WithCheckpointer(NewMemoryCheckpointer()))
+	require.NoError(t, err)
+
+	events, err := collect(t, c.Stream(context.Background(), nil, StreamUpdates|StreamTools, WithThreadID("thread")))
+	require.NoError(t, err)
+
+	var got []string
+	for _, event := range events {
+		if event.Tool != nil {
+			got = append(got, event.Node+" "+string(event.Tool.Type))
+			continue
+		}
+		got = append(got, event.Node)
+	}
+	assert.Equal(t, []string{
+		"team/agent",
+		"team/tools start",
+		"team/tools end",
+		"team/tools",
+		"team",
+	}, got)
+}
+
+func TestSubgraphHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := newTeamGraph(t, NewMemoryCheckpointer(), InterruptBefore())
+	_, err := c.Invoke(ctx, nil, WithThreadID("thread"))
+	require.ErrorIs(t, err, ErrInterrupted)
+
+	snapshot, err := c.GetState(ctx, "thread", 1)
+	require.NoError(t, err)
+	require.NotNil(t, snapshot.Interrupt)
+	assert.Equal(t, []string{"team/tools"}, snapshot.Interrupt.Nodes)
+	assert.Equal(t, []schema.AgentAction{{Tool: "upper", ToolInput: "hello"}}, snapshot.Interrupt.Actions)
+}
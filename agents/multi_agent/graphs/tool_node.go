@@ -0,0 +1,75 @@
+package graphs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+const (
+	// ActionsKey is the state key holding the pending tool calls, as a
+	// []schema.AgentAction, run by ToolNode.
+	ActionsKey = "actions"
+	// StepsKey is the state key ToolNode writes the finished tool calls to, as
+	// a []schema.AgentStep. Graphs using ToolNode should add an Append channel
+	// for it.
+	StepsKey = "intermediate_steps"
+)
+
+// ToolNode returns a node function that runs the tool calls stored under
+// ActionsKey with the given tools. The calls are cleared and one step per call
+// is written to StepsKey.
+func ToolNode(ts []tools.Tool) NodeFunc {
+	nameToTool := make(map[string]tools.Tool, len(ts))
+	for _, tool := range ts {
+		nameToTool[strings.ToUpper(tool.Name())] = tool
+	}
+
+	return func(ctx context.Context, state State) (State, error) {
+		actions, _ := Get[[]schema.AgentAction](state, ActionsKey)
+		steps := make([]schema.AgentStep, 0, len(actions))
+		for _, action := range actions {
+			tool, ok := nameToTool[strings.ToUpper(action.Tool)]
+			if !ok {
+				steps = append(steps, schema.AgentStep{
+					Action:      action,
+					Observation: fmt.Sprintf("%s is not a valid tool, try another one", action.Tool),
+				})
+				continue
+			}
+
+			// Invalid arguments are reported back to the agent so that it can
+			// fix them.
+			if err := tools.Validate(tool, action.ToolInput); err != nil {
+				steps = append(steps, schema.AgentStep{Action: action, Observation: err.Error()})
+				continue
+			}
+
+			observation, err := callTool(ctx, tool, action.ToolInput)
+			if err != nil {
+				return nil, fmt.Errorf("tool %q: %w", action.Tool, err)
+			}
+			steps = append(steps, schema.AgentStep{Action: action, Observation: observation})
+		}
+		return State{ActionsKey: []schema.AgentAction{}, StepsKey: steps}, nil
+	}
+}
+
+// RejectToolCalls is a RejectFunc for tool nodes. Instead of running the
+// pending tool calls it clears them and records a step per call telling the
+// agent the call was rejected.
+func RejectToolCalls(_ context.Context, state State, reason string) (State, error) {
+	actions, _ := Get[[]schema.AgentAction](state, ActionsKey)
+	steps := make([]schema.AgentStep, 0, len(actions))
+	for _, action := range actions {
+		observation := "The tool call was rejected by a reviewer."
+		if reason != "" {
+			observation = fmt.Sprintf("The tool call was rejected by a reviewer: %s", reason)
+		}
+		steps = append(steps, schema.AgentStep{Action: action, Observation: observation})
+	}
+	return State{ActionsKey: []schema.AgentAction{}, StepsKey: steps}, nil
+}
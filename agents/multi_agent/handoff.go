@@ -0,0 +1,135 @@
+package multiagent
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/agents/multi_agent/graphs"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Handoff is a team where the workers route between themselves. The first
+// worker gets the input. A worker given a HandoffTool can transfer control to
+// another worker, and the run ends with the answer of the first worker that
+// does not hand off.
+type Handoff struct {
+	Workers          []Worker
+	Memory           schema.Memory
+	CallbacksHandler callbacks.Handler
+
+	runner
+	graph *graphs.CompiledGraph
+}
+
+var (
+	_ chains.Chain           = (*Handoff)(nil)
+	_ callbacks.HandlerHaver = (*Handoff)(nil)
+)
+
+// NewHandoff creates a team of workers handing off to each other, starting
+// with the first worker.
+func NewHandoff(workers []Worker, opts ...Option) (*Handoff, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if _, err := validateWorkers(workers); err != nil {
+		return nil, err
+	}
+
+	h := &Handoff{
+		Workers:          workers,
+		Memory:           o.memory,
+		CallbacksHandler: o.callbacksHandler,
+		runner: runner{
+			maxTurns:         o.maxTurns,
+			maxIterations:    o.maxIterations,
+			callbacksHandler: o.callbacksHandler,
+			executorOptions:  o.executorOptions,
+		},
+	}
+	var err error
+	if h.graph, err = h.build(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *Handoff) build() (*graphs.CompiledGraph, error) {
+	g := graphs.NewGraph()
+	if err := g.AddChannel(ScratchpadKey, graphs.Append); err != nil {
+		return nil, err
+	}
+	if err := g.SetEntryPoint(h.Workers[0].Name); err != nil {
+		return nil, err
+	}
+	for _, w := range h.Workers {
+		if err := g.AddNode(w.Name, h.workerNode(w)); err != nil {
+			return nil, err
+		}
+		if err := g.AddConditionalEdges(w.Name, routeNext, nil); err != nil {
+			return nil, err
+		}
+	}
+	return g.Compile()
+}
+
+func (h *Handoff) workerNode(w Worker) graphs.NodeFunc {
+	return func(ctx context.Context, state graphs.State) (graphs.State, error) {
+		if err := h.checkTurns(state); err != nil {
+			return nil, err
+		}
+		task, ok := graphs.Get[string](state, _taskKey)
+		if !ok {
+			task, _ = graphs.Get[string](state, InputKey)
+		}
+		turns, _ := graphs.Get[[]Turn](state, ScratchpadKey)
+		turn, err := h.run(ctx, w, task, turns)
+		if err != nil {
+			return nil, err
+		}
+
+		update := graphs.State{ScratchpadKey: []Turn{turn}}
+		if turn.HandoffTo != "" {
+			update[_nextKey], update[_taskKey] = turn.HandoffTo, turn.Output
+			return update, nil
+		}
+		update[_nextKey], update[OutputKey] = graphs.END, turn.Output
+		return update, nil
+	}
+}
+
+// Graph returns the graph of the team, for instance to add it to another graph
+// with graphs.Graph.AddSubgraph. It takes InputKey as input and writes the
+// final answer to OutputKey and the turns to ScratchpadKey.
+func (h *Handoff) Graph() *graphs.CompiledGraph {
+	return h.graph
+}
+
+// Call runs the team on the input under InputKey. The final answer is returned
+// under OutputKey and the turns of the workers under ScratchpadKey, also if the
+// run fails.
+func (h *Handoff) Call(ctx context.Context, inputs map[string]any, _ ...chains.ChainCallOption) (map[string]any, error) { //nolint:lll
+	return h.callGraph(ctx, h.graph, inputs)
+}
+
+// GetMemory returns the memory of the team.
+func (h *Handoff) GetMemory() schema.Memory { //nolint:ireturn
+	return h.Memory
+}
+
+// GetInputKeys returns the input keys of the team.
+func (h *Handoff) GetInputKeys() []string {
+	return []string{InputKey}
+}
+
+// GetOutputKeys returns the output keys of the team.
+func (h *Handoff) GetOutputKeys() []string {
+	return []string{OutputKey, ScratchpadKey}
+}
+
+// GetCallbackHandler returns the callbacks handler of the team.
+func (h *Handoff) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
+	return h.CallbacksHandler
+}
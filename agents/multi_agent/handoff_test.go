@@ -0,0 +1,83 @@
+package multiagent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents/multi_agent/graphs"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestHandoff(t *testing.T) {
+	t.Parallel()
+
+	triage := &scriptedAgent{
+		plans: [][]schema.AgentAction{{{Tool: "transfer_to_billing", ToolInput: "refund order 42"}}},
+		tools: []tools.Tool{HandoffTool("billing", "Handles payments.")},
+	}
+	billing := &scriptedAgent{answer: "refunded"}
+
+	h, err := NewHandoff([]Worker{
+		{Name: "triage", Agent: triage},
+		{Name: "billing", Agent: billing},
+	})
+	require.NoError(t, err)
+
+	outputs, err := h.Call(context.Background(), map[string]any{"input": "I want my money back"})
+	require.NoError(t, err)
+	assert.Equal(t, "refunded", outputs[OutputKey])
+	assert.Equal(t, []Turn{
+		{Worker: "triage", Input: "I want my money back", Output: "refund order 42", HandoffTo: "billing"},
+		{Worker: "billing", Input: "refund order 42", Output: "refunded"},
+	}, outputs[ScratchpadKey])
+	assert.Contains(t, billing.inputs[0], "[triage] Handed off to billing: refund order 42")
+}
+
+func TestHandoffMaxTurns(t *testing.T) {
+	t.Parallel()
+
+	ping := &scriptedAgent{
+		plans: [][]schema.AgentAction{{{Tool: "transfer_to_pong", ToolInput: "ping"}}},
+		tools: []tools.Tool{HandoffTool("pong", "")},
+	}
+	pong := &scriptedAgent{
+		plans: [][]schema.AgentAction{{{Tool: "transfer_to_ping", ToolInput: "pong"}}},
+		tools: []tools.Tool{HandoffTool("ping", "")},
+	}
+	h, err := NewHandoff([]Worker{{Name: "ping", Agent: ping}, {Name: "pong", Agent: pong}}, WithMaxTurns(3))
+	require.NoError(t, err)
+
+	outputs, err := h.Call(context.Background(), map[string]any{"input": "go"})
+	require.ErrorIs(t, err, ErrMaxTurns)
+	assert.Len(t, outputs[ScratchpadKey], 3)
+}
+
+func TestHandoffUnknownWorker(t *testing.T) {
+	t.Parallel()
+
+	agent := &scriptedAgent{tools: []tools.Tool{HandoffTool("nobody", "")}}
+	_, err := NewHandoff([]Worker{{Name: "a", Agent: agent}})
+	require.ErrorIs(t, err, ErrUnknownWorker)
+}
+
+func TestHandoffAsSubgraph(t *testing.T) {
+	t.Parallel()
+
+	h, err := NewHandoff([]Worker{{Name: "a", Agent: &scriptedAgent{answer: "done"}}})
+	require.NoError(t, err)
+
+	g := graphs.NewGraph()
+	require.NoError(t, g.AddSubgraph("team", h.Graph(),
+		graphs.WithInputKeys(map[string]string{"question": InputKey}),
+		graphs.WithOutputKeys(map[string]string{OutputKey: "answer"}),
+	))
+	require.NoError(t, g.SetEntryPoint("team"))
+	require.NoError(t, g.SetFinishPoint("team"))
+
+	state, err := g.Execute(context.Background(), graphs.State{"question": "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "done", state["answer"])
+}
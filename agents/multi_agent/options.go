@@ -0,0 +1,84 @@
+package multiagent
+
+import (
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	_defaultMaxTurns      = 10
+	_defaultMaxIterations = 5
+)
+
+type options struct {
+	maxTurns         int
+	maxIterations    int
+	memory           schema.Memory
+	callbacksHandler callbacks.Handler
+	prompt           *prompts.PromptTemplate
+	executorOptions  []agents.Option
+}
+
+// Option is a function that configures a team.
+type Option func(*options)
+
+func defaultOptions() options {
+	return options{
+		maxTurns:      _defaultMaxTurns,
+		maxIterations: _defaultMaxIterations,
+		memory:        memory.NewSimple(),
+	}
+}
+
+// WithMaxTurns sets the number of turns the workers can take before the run
+// stops with ErrMaxTurns. Defaults to 10.
+func WithMaxTurns(maxTurns int) Option {
+	return func(o *options) {
+		o.maxTurns = maxTurns
+	}
+}
+
+// WithMaxIterations sets the number of times a worker can plan within a single
+// turn. Defaults to 5.
+func WithMaxIterations(maxIterations int) Option {
+	return func(o *options) {
+		o.maxIterations = maxIterations
+	}
+}
+
+// WithExecutorOptions sets options of the agents.Executor running every turn
+// of the workers, such as agents.WithGuardrails, agents.WithMaxConcurrency,
+// agents.WithToolErrorHandler or the budgets. By default the errors of the
+// tools are given back to the worker as observations. The callbacks handler of
+// the executor is the one of the team.
+func WithExecutorOptions(opts ...agents.Option) Option {
+	return func(o *options) {
+		o.executorOptions = append(o.executorOptions, opts...)
+	}
+}
+
+// WithMemory sets the memory of the team.
+func WithMemory(memory schema.Memory) Option {
+	return func(o *options) {
+		o.memory = memory
+	}
+}
+
+// WithCallbacksHandler sets the handler told about the actions of the
+// workers and the tools they call.
+func WithCallbacksHandler(handler callbacks.Handler) Option {
+	return func(o *options) {
+		o.callbacksHandler = handler
+	}
+}
+
+// WithPrompt sets the prompt of the supervisor. It is given the input
+// variables "input", "workers", "worker_names" and "scratchpad".
+func WithPrompt(prompt prompts.PromptTemplate) Option {
+	return func(o *options) {
+		o.prompt = &prompt
+	}
+}
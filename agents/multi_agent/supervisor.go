@@ -0,0 +1,254 @@
+package multiagent
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/agents/multi_agent/graphs"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	// SupervisorNode is the name of the node of the supervisor in the graph of
+	// a Supervisor.
+	SupervisorNode = "supervisor"
+
+	_defaultSupervisorPrompt = `You are a supervisor managing a team of workers:
+
+{{.workers}}
+
+Given the request below and the work done so far, decide which worker acts next and what they should do, or give the final answer once the request is done.
+
+Use exactly one of the following formats:
+
+Next: the worker that acts next, one of [ {{.worker_names}} ]
+Task: the instructions for that worker
+
+Final Answer: the final answer to the request
+
+Request: {{.input}}
+
+Work done so far:
+{{.scratchpad}}`
+)
+
+var (
+	_finalAnswerRegexp = regexp.MustCompile(`(?s)Final Answer:\s*(.*)`)
+	_nextRegexp        = regexp.MustCompile(`Next:\s*(.+)`)
+	_taskRegexp        = regexp.MustCompile(`(?s)Task:\s*(.*)`)
+)
+
+// Supervisor is a team where an LLM routes between the workers. After every
+// turn of a worker the supervisor reads the scratchpad and picks the worker
+// that acts next, until it gives the final answer.
+type Supervisor struct {
+	LLM              llms.Model
+	Workers          []Worker
+	Prompt           prompts.PromptTemplate
+	Memory           schema.Memory
+	CallbacksHandler callbacks.Handler
+
+	runner
+	workers map[string]Worker
+	graph   *graphs.CompiledGraph
+}
+
+var (
+	_ chains.Chain           = (*Supervisor)(nil)
+	_ callbacks.HandlerHaver = (*Supervisor)(nil)
+)
+
+// NewSupervisor creates a team of workers routed by llm.
+func NewSupervisor(llm llms.Model, workers []Worker, opts ...Option) (*Supervisor, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	byName, err := validateWorkers(workers, SupervisorNode)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Supervisor{
+		LLM:              llm,
+		Workers:          workers,
+		Prompt:           supervisorPrompt(),
+		Memory:           o.memory,
+		CallbacksHandler: o.callbacksHandler,
+		runner: runner{
+			maxTurns:         o.maxTurns,
+			maxIterations:    o.maxIterations,
+			callbacksHandler: o.callbacksHandler,
+			executorOptions:  o.executorOptions,
+		},
+		workers: byName,
+	}
+	if o.prompt != nil {
+		s.Prompt = *o.prompt
+	}
+	if s.graph, err = s.build(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func supervisorPrompt() prompts.PromptTemplate {
+	return prompts.PromptTemplate{
+		Template:       _defaultSupervisorPrompt,
+		TemplateFormat: prompts.TemplateFormatGoTemplate,
+		InputVariables: []string{"input", "workers", "worker_names", "scratchpad"},
+	}
+}
+
+func (s *Supervisor) build() (*graphs.CompiledGraph, error) {
+	g := graphs.NewGraph()
+	if err := g.AddChannel(ScratchpadKey, graphs.Append); err != nil {
+		return nil, err
+	}
+	if err := g.AddNode(SupervisorNode, s.supervise); err != nil {
+		return nil, err
+	}
+	if err := g.SetEntryPoint(SupervisorNode); err != nil {
+		return nil, err
+	}
+	if err := g.AddConditionalEdges(SupervisorNode, routeNext, nil); err != nil {
+		return nil, err
+	}
+	for _, w := range s.Workers {
+		if err := g.AddNode(w.Name, s.workerNode(w)); err != nil {
+			return nil, err
+		}
+		if err := g.AddEdge(w.Name, SupervisorNode); err != nil {
+			return nil, err
+		}
+	}
+	return g.Compile()
+}
+
+// supervise asks the LLM which worker acts next.
+func (s *Supervisor) supervise(ctx context.Context, state graphs.State) (graphs.State, error) {
+	input, _ := graphs.Get[string](state, InputKey)
+	turns, _ := graphs.Get[[]Turn](state, ScratchpadKey)
+	prompt, err := s.Prompt.Format(map[string]any{
+		"input":        input,
+		"workers":      workerDescriptions(s.Workers),
+		"worker_names": workerNames(s.Workers),
+		"scratchpad":   formatScratchpad(turns),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := llms.GenerateFromSinglePrompt(ctx, s.LLM, prompt,
+		llms.WithStreamingFunc(graphs.StreamingFunc(ctx)))
+	if err != nil {
+		return nil, err
+	}
+
+	if match := _finalAnswerRegexp.FindStringSubmatch(output); match != nil {
+		return graphs.State{_nextKey: graphs.END, OutputKey: strings.TrimSpace(match[1])}, nil
+	}
+	next := _nextRegexp.FindStringSubmatch(output)
+	if next == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnableToParseRoute, output)
+	}
+	w, ok := s.worker(strings.TrimSpace(next[1]))
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownWorker, strings.TrimSpace(next[1]))
+	}
+	if err := s.checkTurns(state); err != nil {
+		return nil, err
+	}
+
+	task := input
+	if match := _taskRegexp.FindStringSubmatch(output); match != nil {
+		task = strings.TrimSpace(match[1])
+	}
+	return graphs.State{_nextKey: w.Name, _taskKey: task}, nil
+}
+
+// worker looks a worker up by name, ignoring case.
+func (s *Supervisor) worker(name string) (Worker, bool) {
+	if w, ok := s.workers[name]; ok {
+		return w, true
+	}
+	for _, w := range s.Workers {
+		if strings.EqualFold(w.Name, name) {
+			return w, true
+		}
+	}
+	return Worker{}, false
+}
+
+func (s *Supervisor) workerNode(w Worker) graphs.NodeFunc {
+	return func(ctx context.Context, state graphs.State) (graphs.State, error) {
+		task, _ := graphs.Get[string](state, _taskKey)
+		turns, _ := graphs.Get[[]Turn](state, ScratchpadKey)
+		turn, err := s.run(ctx, w, task, turns)
+		if err != nil {
+			return nil, err
+		}
+		return graphs.State{ScratchpadKey: []Turn{turn}}, nil
+	}
+}
+
+func routeNext(_ context.Context, state graphs.State) (string, error) {
+	next, _ := graphs.Get[string](state, _nextKey)
+	return next, nil
+}
+
+// Graph returns the graph of the team, for instance to add it to another graph
+// with graphs.Graph.AddSubgraph. It takes InputKey as input and writes the
+// final answer to OutputKey and the turns to ScratchpadKey.
+func (s *Supervisor) Graph() *graphs.CompiledGraph {
+	return s.graph
+}
+
+// Call runs the team on the input under InputKey. The final answer is returned
+// under OutputKey and the turns of the workers under ScratchpadKey, also if the
+// run fails.
+func (s *Supervisor) Call(ctx context.Context, inputs map[string]any, _ ...chains.ChainCallOption) (map[string]any, error) { //nolint:lll
+	return s.callGraph(ctx, s.graph, inputs)
+}
+
+// GetMemory returns the memory of the team.
+func (s *Supervisor) GetMemory() schema.Memory { //nolint:ireturn
+	return s.Memory
+}
+
+// GetInputKeys returns the input keys of the team.
+func (s *Supervisor) GetInputKeys() []string {
+	return []string{InputKey}
+}
+
+// GetOutputKeys returns the output keys of the team.
+func (s *Supervisor) GetOutputKeys() []string {
+	return []string{OutputKey, ScratchpadKey}
+}
+
+// GetCallbackHandler returns the callbacks handler of the team.
+func (s *Supervisor) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
+	return s.CallbacksHandler
+}
+
+func workerNames(workers []Worker) string {
+	names := make([]string, len(workers))
+	for i, w := range workers {
+		names[i] = w.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func workerDescriptions(workers []Worker) string {
+	lines := make([]string, len(workers))
+	for i, w := range workers {
+		lines[i] = fmt.Sprintf("- %s: %s", w.Name, w.Description)
+	}
+	return strings.Join(lines, "\n")
+}
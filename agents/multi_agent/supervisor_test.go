@@ -0,0 +1,176 @@
+package multiagent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// scriptedLLM returns its responses in order and records the prompts.
+type scriptedLLM struct {
+	responses []string
+	prompts   []string
+}
+
+var _ llms.Model = (*scriptedLLM)(nil)
+
+func (l *scriptedLLM) GenerateContent(
+	_ context.Context,
+	messages []llms.MessageContent,
+	_ ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	l.prompts = append(l.prompts, messages[0].Parts[0].(llms.TextContent).Text)
+	response := l.responses[0]
+	l.responses = l.responses[1:]
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: response}}}, nil
+}
+
+func (l *scriptedLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+// scriptedAgent plays its plans in order and records its inputs.
+type scriptedAgent struct {
+	plans  [][]schema.AgentAction
+	answer string
+	tools  []tools.Tool
+	inputs []string
+}
+
+func (a *scriptedAgent) Plan(
+	_ context.Context,
+	steps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	a.inputs = append(a.inputs, inputs["input"])
+	if len(steps) < len(a.plans) {
+		return a.plans[len(steps)], nil, nil
+	}
+	answer := a.answer
+	if len(steps) > 0 {
+		answer += " " + steps[len(steps)-1].Observation
+	}
+	return nil, &schema.AgentFinish{ReturnValues: map[string]any{"output": answer}}, nil
+}
+
+func (a *scriptedAgent) GetInputKeys() []string  { return []string{"input"} }
+func (a *scriptedAgent) GetOutputKeys() []string { return []string{"output"} }
+func (a *scriptedAgent) GetTools() []tools.Tool  { return a.tools }
+
+type upperTool struct{}
+
+func (upperTool) Name() string        { return "upper" }
+func (upperTool) Description() string { return "Converts the input to upper case." }
+func (upperTool) Call(_ context.Context, input string) (string, error) {
+	return strings.ToUpper(input), nil
+}
+
+func TestSupervisor(t *testing.T) {
+	t.Parallel()
+
+	researcher := &scriptedAgent{
+		plans:  [][]schema.AgentAction{{{Tool: "upper", ToolInput: "facts"}}},
+		answer: "found",
+		tools:  []tools.Tool{upperTool{}},
+	}
+	writer := &scriptedAgent{answer: "draft written"}
+	llm := &scriptedLLM{responses: []string{
+		"Next: researcher\nTask: find facts",
+		"Next: Writer\nTask: write it up",
+		"Final Answer: the report",
+	}}
+
+	s, err := NewSupervisor(llm, []Worker{
+		{Name: "researcher", Description: "Finds facts.", Agent: researcher},
+		{Name: "writer", Description: "Writes reports.", Agent: writer},
+	})
+	require.NoError(t, err)
+
+	outputs, err := chains.Call(context.Background(), s, map[string]any{"input": "write a report"})
+	require.NoError(t, err)
+	assert.Equal(t, "the report", outputs[OutputKey])
+	assert.Equal(t, []Turn{
+		{
+			Worker: "researcher",
+			Input:  "find facts",
+			Output: "found FACTS",
+			Steps: []schema.AgentStep{
+				{Action: schema.AgentAction{Tool: "upper", ToolInput: "facts"}, Observation: "FACTS"},
+			},
+		},
+		{Worker: "writer", Input: "write it up", Output: "draft written"},
+	}, outputs[ScratchpadKey])
+
+	require.Len(t, llm.prompts, 3)
+	assert.Contains(t, llm.prompts[0], "- researcher: Finds facts.")
+	assert.Contains(t, llm.prompts[0], "Request: write a report")
+	assert.Contains(t, llm.prompts[2], "[writer] Result: draft written")
+	// The writer sees the work of the researcher.
+	assert.Contains(t, writer.inputs[0], "[researcher] Result: found FACTS")
+}
+
+func TestSupervisorWithPrompt(t *testing.T) {
+	t.Parallel()
+
+	llm := &scriptedLLM{responses: []string{"Final Answer: done"}}
+	s, err := NewSupervisor(llm, []Worker{
+		{Name: "researcher", Description: "Finds facts.", Agent: &scriptedAgent{}},
+	}, WithPrompt(prompts.PromptTemplate{
+		Template:       "Team:\n{{.workers}}\nPick one of {{.worker_names}} for {{.input}}.\n{{.scratchpad}}",
+		TemplateFormat: prompts.TemplateFormatGoTemplate,
+		InputVariables: []string{"input", "workers", "worker_names", "scratchpad"},
+	}))
+	require.NoError(t, err)
+
+	outputs, err := chains.Call(context.Background(), s, map[string]any{"input": "a report"})
+	require.NoError(t, err)
+	assert.Equal(t, "done", outputs[OutputKey])
+	require.Len(t, llm.prompts, 1)
+	assert.Contains(t, llm.prompts[0], "- researcher: Finds facts.")
+	assert.Contains(t, llm.prompts[0], "Pick one of researcher for a report.")
+}
+
+func TestSupervisorMaxTurns(t *testing.T) {
+	t.Parallel()
+
+	llm := &scriptedLLM{responses: []string{
+		"Next: worker\nTask: again",
+		"Next: worker\nTask: again",
+		"Next: worker\nTask: again",
+	}}
+	s, err := NewSupervisor(llm, []Worker{
+		{Name: "worker", Agent: &scriptedAgent{answer: "done"}},
+	}, WithMaxTurns(2))
+	require.NoError(t, err)
+
+	outputs, err := s.Call(context.Background(), map[string]any{"input": "loop"})
+	require.ErrorIs(t, err, ErrMaxTurns)
+	assert.Len(t, outputs[ScratchpadKey], 2)
+}
+
+func TestSupervisorErrors(t *testing.T) {
+	t.Parallel()
+
+	agent := &scriptedAgent{}
+	_, err := NewSupervisor(&scriptedLLM{}, nil)
+	require.ErrorIs(t, err, ErrInvalidWorker)
+	_, err = NewSupervisor(&scriptedLLM{}, []Worker{{Name: "a", Agent: agent}, {Name: "a", Agent: agent}})
+	require.ErrorIs(t, err, ErrInvalidWorker)
+	_, err = NewSupervisor(&scriptedLLM{}, []Worker{{Name: SupervisorNode, Agent: agent}})
+	require.ErrorIs(t, err, ErrInvalidWorker)
+
+	for _, response := range []string{"I do not know", "Next: nobody"} {
+		s, err := NewSupervisor(&scriptedLLM{responses: []string{response}}, []Worker{{Name: "a", Agent: agent}})
+		require.NoError(t, err)
+		_, err = s.Call(context.Background(), map[string]any{"input": "x"})
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,375 @@
+package multiagent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/agents/multi_agent/graphs"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+const (
+	// InputKey is the input key of teams, and OutputKey the output key holding
+	// the final answer.
+	InputKey  = "input"
+	OutputKey = "output"
+	// ScratchpadKey is the output key, and the state key, holding the turns of
+	// the workers as a []Turn.
+	ScratchpadKey = "scratchpad"
+
+	// _nextKey holds the worker acting next and _taskKey what it should do.
+	_nextKey = "next"
+	_taskKey = "task"
+)
+
+// Worker is a member of a team.
+type Worker struct {
+	// Name identifies the worker. It is the name of its node in the graph of
+	// the team.
+	Name string
+	// Description tells the supervisor, or the other workers, what the worker
+	// is good at.
+	Description string
+	// Agent plans the work of the worker with the tools it returns from
+	// GetTools.
+	Agent agents.Agent
+}
+
+// Turn is an entry of the scratchpad shared by the workers of a team.
+type Turn struct {
+	Worker string `json:"worker"`
+	// Input is the task the worker was given.
+	Input string `json:"input"`
+	// Output is the answer of the worker, or the task it handed off.
+	Output string `json:"output"`
+	// HandoffTo is the worker control was transferred to, if any.
+	HandoffTo string `json:"handoff_to,omitempty"`
+	// Steps holds the tool calls the worker made.
+	Steps []schema.AgentStep `json:"steps,omitempty"`
+}
+
+// HandoffTool returns a tool that lets an agent transfer control to the worker
+// named to. The input of the tool is given to that worker as its task. The
+// tool is not called: using it ends the turn of the worker.
+func HandoffTool(to, description string) tools.Tool { //nolint:ireturn
+	return &handoffTool{to: to, description: description}
+}
+
+type handoffTool struct {
+	to          string
+	description string
+}
+
+func (t *handoffTool) Name() string {
+	return "transfer_to_" + t.to
+}
+
+func (t *handoffTool) Description() string {
+	return fmt.Sprintf("Transfer control to %s. %s The input should be the task for %s, "+
+		"with all the context they need to do it.", t.to, t.description, t.to)
+}
+
+func (t *handoffTool) Call(context.Context, string) (string, error) {
+	return "Transferred to " + t.to, nil
+}
+
+// validateWorkers checks the workers of a team and returns them by name.
+func validateWorkers(workers []Worker, reserved ...string) (map[string]Worker, error) {
+	if len(workers) == 0 {
+		return nil, fmt.Errorf("%w: team has no workers", ErrInvalidWorker)
+	}
+	byName := make(map[string]Worker, len(workers))
+	for _, w := range workers {
+		switch {
+		case w.Name == "" || strings.Contains(w.Name, "/"):
+			return nil, fmt.Errorf("%w: invalid name %q", ErrInvalidWorker, w.Name)
+		case w.Agent == nil:
+			return nil, fmt.Errorf("%w: %q has no agent", ErrInvalidWorker, w.Name)
+		}
+		if _, exists := byName[w.Name]; exists {
+			return nil, fmt.Errorf("%w: duplicate name %q", ErrInvalidWorker, w.Name)
+		}
+		for _, r := range reserved {
+			if w.Name == r {
+				return nil, fmt.Errorf("%w: name %q is reserved", ErrInvalidWorker, w.Name)
+			}
+		}
+		byName[w.Name] = w
+	}
+
+	for _, w := range workers {
+		for _, tool := range w.Agent.GetTools() {
+			if h, ok := tool.(*handoffTool); ok {
+				if _, known := byName[h.to]; !known {
+					return nil, fmt.Errorf("%w: %q hands off to %q", ErrUnknownWorker, w.Name, h.to)
+				}
+			}
+		}
+	}
+	return byName, nil
+}
+
+// runner runs the turns of the workers of a team.
+type runner struct {
+	maxTurns         int
+	maxIterations    int
+	callbacksHandler callbacks.Handler
+	executorOptions  []agents.Option
+}
+
+// checkTurns fails once the workers took the maximum number of turns.
+func (r runner) checkTurns(state graphs.State) error {
+	turns, _ := graphs.Get[[]Turn](state, ScratchpadKey)
+	if len(turns) >= r.maxTurns {
+		return fmt.Errorf("%w: %d turns", ErrMaxTurns, len(turns))
+	}
+	return nil
+}
+
+// run runs the worker in an agents.Executor until it answers or hands off. The
+// worker is given task and the turns of the workers before it.
+func (r runner) run(ctx context.Context, w Worker, task string, turns []Turn) (Turn, error) {
+	inputs := make(map[string]any)
+	for _, key := range w.Agent.GetInputKeys() {
+		inputs[key] = ""
+	}
+	inputs[primaryInputKey(w.Agent)] = workerInput(task, turns)
+
+	handlers := []callbacks.Handler{graphs.CallbacksHandler(ctx)}
+	if r.callbacksHandler != nil {
+		handlers = append(handlers, r.callbacksHandler)
+	}
+	handler := callbacks.CombiningHandler{Callbacks: handlers}
+
+	agent := newWorkerAgent(w.Agent, handler)
+	opts := append([]agents.Option{
+		agents.WithMaxIterations(r.maxIterations),
+		agents.WithToolErrorHandler(agents.NewToolErrorHandler(nil)),
+	}, r.executorOptions...)
+	executor := agents.NewExecutor(agent.executorAgent(), append(opts, agents.WithCallbacksHandler(handler))...)
+
+	_, err := executor.Call(ctx, inputs)
+	turn := Turn{Worker: w.Name, Input: task}
+	if len(agent.steps) > 0 {
+		turn.Steps = agent.steps
+	}
+	if err != nil {
+		return turn, fmt.Errorf("worker %q: %w", w.Name, err)
+	}
+	turn.HandoffTo = agent.handoffTo
+	turn.Output = finishOutput(w.Agent, agent.finish)
+	return turn, nil
+}
+
+// workerAgent is the agent of a worker as run by the executor of a turn. It
+// records the steps and the finish of the turn, and finishes the turn when the
+// agent uses a HandoffTool.
+type workerAgent struct {
+	agents.Agent
+	tools []tools.Tool
+
+	steps     []schema.AgentStep
+	finish    *schema.AgentFinish
+	handoff   *schema.AgentFinish
+	handoffTo string
+}
+
+// newWorkerAgent wraps agent, reporting the calls of its tools to handler.
+func newWorkerAgent(agent agents.Agent, handler callbacks.Handler) *workerAgent {
+	a := &workerAgent{Agent: agent}
+	for _, tool := range agent.GetTools() {
+		a.tools = append(a.tools, withToolCallbacks(tool, handler))
+	}
+	return a
+}
+
+// executorAgent returns the agent to run in the executor, which can generate a
+// final answer if the agent of the worker can.
+func (a *workerAgent) executorAgent() agents.Agent { //nolint:ireturn
+	if _, ok := a.Agent.(agents.FinalAnswerAgent); ok {
+		return finalAnswerWorkerAgent{a}
+	}
+	return a
+}
+
+// Plan plans with the agent of the worker. The actions planned after a handoff
+// are dropped, and the ones before it run before the turn finishes.
+func (a *workerAgent) Plan(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	a.steps = intermediateSteps
+	if a.handoff != nil {
+		a.finish = a.handoff
+		return nil, a.handoff, nil
+	}
+
+	actions, finish, err := a.Agent.Plan(ctx, intermediateSteps, inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if finish != nil {
+		a.finish = finish
+		return nil, finish, nil
+	}
+	for i, action := range actions {
+		if h, ok := a.handoffTool(action.Tool); ok {
+			a.handoffTo = h.to
+			a.handoff = &schema.AgentFinish{
+				ReturnValues: map[string]any{OutputKey: action.ToolInput},
+				Log:          action.Log,
+			}
+			if i == 0 {
+				a.finish = a.handoff
+				return nil, a.handoff, nil
+			}
+			return actions[:i], nil, nil
+		}
+	}
+	return actions, nil, nil
+}
+
+// GetTools returns the tools of the agent, reporting their calls.
+func (a *workerAgent) GetTools() []tools.Tool {
+	return a.tools
+}
+
+func (a *workerAgent) handoffTool(name string) (*handoffTool, bool) {
+	for _, tool := range a.Agent.GetTools() {
+		if h, ok := tool.(*handoffTool); ok && strings.EqualFold(h.Name(), name) {
+			return h, true
+		}
+	}
+	return nil, false
+}
+
+// finalAnswerWorkerAgent is a workerAgent whose agent can generate a final
+// answer when the executor stops early.
+type finalAnswerWorkerAgent struct {
+	*workerAgent
+}
+
+var _ agents.FinalAnswerAgent = finalAnswerWorkerAgent{}
+
+// FinalAnswer finishes the turn with the pending handoff, if any, or the final
+// answer of the agent.
+func (a finalAnswerWorkerAgent) FinalAnswer(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) (*schema.AgentFinish, error) {
+	a.steps = intermediateSteps
+	if a.handoff != nil {
+		a.finish = a.handoff
+		return a.handoff, nil
+	}
+	agent, _ := a.Agent.(agents.FinalAnswerAgent)
+	finish, err := agent.FinalAnswer(ctx, intermediateSteps, inputs)
+	if err != nil {
+		return nil, err
+	}
+	a.finish = finish
+	return finish, nil
+}
+
+// withToolCallbacks returns a tool reporting the calls of tool to handler,
+// which is how the graph streams the tool calls of the workers.
+func withToolCallbacks(tool tools.Tool, handler callbacks.Handler) tools.Tool { //nolint:ireturn
+	t := callbacksTool{Tool: tool, handler: handler}
+	if st, ok := tool.(tools.StructuredTool); ok {
+		return structuredCallbacksTool{callbacksTool: t, schema: st.Schema()}
+	}
+	return t
+}
+
+type callbacksTool struct {
+	tools.Tool
+	handler callbacks.Handler
+}
+
+func (t callbacksTool) Call(ctx context.Context, input string) (string, error) {
+	t.handler.HandleToolStart(ctx, input)
+	output, err := t.Tool.Call(ctx, input)
+	if err != nil {
+		t.handler.HandleToolError(ctx, err)
+		return "", err
+	}
+	t.handler.HandleToolEnd(ctx, output)
+	return output, nil
+}
+
+type structuredCallbacksTool struct {
+	callbacksTool
+	schema jsonschema.Definition
+}
+
+func (t structuredCallbacksTool) Schema() jsonschema.Definition {
+	return t.schema
+}
+
+func primaryInputKey(agent agents.Agent) string {
+	for _, key := range agent.GetInputKeys() {
+		if key == InputKey {
+			return key
+		}
+	}
+	if keys := agent.GetInputKeys(); len(keys) > 0 {
+		return keys[0]
+	}
+	return InputKey
+}
+
+// finishOutput returns the answer of an agent from its finish.
+func finishOutput(agent agents.Agent, finish *schema.AgentFinish) string {
+	keys := append([]string{OutputKey}, agent.GetOutputKeys()...)
+	for _, key := range keys {
+		if v, ok := finish.ReturnValues[key]; ok {
+			return fmt.Sprint(v)
+		}
+	}
+	return finish.Log
+}
+
+// workerInput gives a worker its task together with the work done so far.
+func workerInput(task string, turns []Turn) string {
+	if len(turns) == 0 {
+		return task
+	}
+	return fmt.Sprintf("%s\n\nWork done so far by the team:\n%s", task, formatScratchpad(turns))
+}
+
+func formatScratchpad(turns []Turn) string {
+	if len(turns) == 0 {
+		return "Nothing yet."
+	}
+	var sb strings.Builder
+	for _, turn := range turns {
+		fmt.Fprintf(&sb, "[%s] Task: %s\n", turn.Worker, turn.Input)
+		if turn.HandoffTo != "" {
+			fmt.Fprintf(&sb, "[%s] Handed off to %s: %s\n", turn.Worker, turn.HandoffTo, turn.Output)
+			continue
+		}
+		fmt.Fprintf(&sb, "[%s] Result: %s\n", turn.Worker, turn.Output)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// callGraph runs the graph of a team with the input of a chain call. The
+// recursion limit of the graph leaves room for a supervisor step around every
+// turn, so the turns of the workers are limited by ErrMaxTurns only.
+func (r runner) callGraph(ctx context.Context, g *graphs.CompiledGraph, inputs map[string]any) (map[string]any, error) {
+	input, ok := inputs[InputKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", agents.ErrExecutorInputNotString, InputKey)
+	}
+	state, err := g.Invoke(ctx, graphs.State{InputKey: input}, graphs.WithRecursionLimit(2*r.maxTurns+2))
+	turns, _ := graphs.Get[[]Turn](state, ScratchpadKey)
+	output, _ := graphs.Get[string](state, OutputKey)
+	return map[string]any{OutputKey: output, ScratchpadKey: turns}, err
+}
@@ -0,0 +1,114 @@
+package multiagent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/agents/multi_agent/graphs"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+type failingTool struct{}
+
+func (failingTool) Name() string        { return "fail" }
+func (failingTool) Description() string { return "Always fails." }
+func (failingTool) Call(context.Context, string) (string, error) {
+	return "", errors.New("boom")
+}
+
+func TestWorkerToolError(t *testing.T) {
+	t.Parallel()
+
+	worker := &scriptedAgent{
+		plans:  [][]schema.AgentAction{{{Tool: "fail", ToolInput: "x"}}},
+		answer: "recovered:",
+		tools:  []tools.Tool{failingTool{}},
+	}
+	h, err := NewHandoff([]Worker{{Name: "a", Agent: worker}})
+	require.NoError(t, err)
+
+	outputs, err := h.Call(context.Background(), map[string]any{"input": "go"})
+	require.NoError(t, err)
+	assert.Equal(t, "recovered: fail failed: boom", outputs[OutputKey])
+
+	h, err = NewHandoff([]Worker{{Name: "a", Agent: worker}}, WithExecutorOptions(agents.WithToolErrorHandler(nil)))
+	require.NoError(t, err)
+	_, err = h.Call(context.Background(), map[string]any{"input": "go"})
+	require.ErrorContains(t, err, "boom")
+}
+
+func TestWorkerGuardrails(t *testing.T) {
+	t.Parallel()
+
+	worker := &scriptedAgent{
+		plans:  [][]schema.AgentAction{{{Tool: "upper", ToolInput: "x"}}},
+		answer: "observed:",
+		tools:  []tools.Tool{upperTool{}},
+	}
+	h, err := NewHandoff([]Worker{{Name: "a", Agent: worker}},
+		WithExecutorOptions(agents.WithGuardrails(agents.DenyTools("upper"))))
+	require.NoError(t, err)
+
+	outputs, err := h.Call(context.Background(), map[string]any{"input": "go"})
+	require.NoError(t, err)
+	assert.Contains(t, outputs[OutputKey], agents.ErrActionDenied.Error())
+}
+
+func TestWorkerHandoffAfterAction(t *testing.T) {
+	t.Parallel()
+
+	triage := &scriptedAgent{
+		plans: [][]schema.AgentAction{{
+			{Tool: "upper", ToolInput: "order 42"},
+			{Tool: "transfer_to_billing", ToolInput: "refund"},
+		}},
+		tools: []tools.Tool{upperTool{}, HandoffTool("billing", "")},
+	}
+	h, err := NewHandoff([]Worker{
+		{Name: "triage", Agent: triage},
+		{Name: "billing", Agent: &scriptedAgent{answer: "refunded"}},
+	})
+	require.NoError(t, err)
+
+	outputs, err := h.Call(context.Background(), map[string]any{"input": "money back"})
+	require.NoError(t, err)
+	turns, _ := outputs[ScratchpadKey].([]Turn)
+	require.Len(t, turns, 2)
+	assert.Equal(t, Turn{
+		Worker:    "triage",
+		Input:     "money back",
+		Output:    "refund",
+		HandoffTo: "billing",
+		Steps: []schema.AgentStep{{
+			Action:      schema.AgentAction{Tool: "upper", ToolInput: "order 42"},
+			Observation: "ORDER 42",
+		}},
+	}, turns[0])
+}
+
+func TestWorkerStreamsToolCalls(t *testing.T) {
+	t.Parallel()
+
+	worker := &scriptedAgent{
+		plans: [][]schema.AgentAction{{{Tool: "upper", ToolInput: "x"}}},
+		tools: []tools.Tool{upperTool{}},
+	}
+	h, err := NewHandoff([]Worker{{Name: "a", Agent: worker}})
+	require.NoError(t, err)
+
+	var events []graphs.Event
+	h.Graph().Stream(context.Background(), graphs.State{InputKey: "go"}, graphs.StreamTools)(
+		func(event graphs.Event, err error) bool {
+			require.NoError(t, err)
+			events = append(events, event)
+			return true
+		})
+	require.Len(t, events, 2)
+	assert.Equal(t, graphs.ToolStart, events[0].Tool.Type)
+	assert.Equal(t, "X", events[1].Tool.Output)
+}
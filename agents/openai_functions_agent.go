@@ -2,7 +2,6 @@ package agents
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/tmc/langchaingo/callbacks"
@@ -30,7 +29,10 @@ type OpenAIFunctionsAgent struct {
 	CallbacksHandler callbacks.Handler
 }
 
-var _ Agent = (*OpenAIFunctionsAgent)(nil)
+var (
+	_ Agent            = (*OpenAIFunctionsAgent)(nil)
+	_ FinalAnswerAgent = (*OpenAIFunctionsAgent)(nil)
+)
 
 // NewOpenAIFunctionsAgent creates a new OpenAIFunctionsAgent.
 func NewOpenAIFunctionsAgent(llm llms.Model, tools []tools.Tool, opts ...Option) *OpenAIFunctionsAgent {
@@ -49,19 +51,9 @@ func NewOpenAIFunctionsAgent(llm llms.Model, tools []tools.Tool, opts ...Option)
 }
 
 func (o *OpenAIFunctionsAgent) functions() []llms.FunctionDefinition {
-	res := make([]llms.FunctionDefinition, 0)
+	res := make([]llms.FunctionDefinition, 0, len(o.Tools))
 	for _, tool := range o.Tools {
-		res = append(res, llms.FunctionDefinition{
-			Name:        tool.Name(),
-			Description: tool.Description(),
-			Parameters: map[string]any{
-				"properties": map[string]any{
-					"__arg1": map[string]string{"title": "__arg1", "type": "string"},
-				},
-				"required": []string{"__arg1"},
-				"type":     "object",
-			},
-		})
+		res = append(res, *tools.ToLLMTool(tool).Function)
 	}
 	return res
 }
@@ -78,66 +70,40 @@ func (o *OpenAIFunctionsAgent) Plan(
 	}
 	fullInputs[agentScratchpad] = o.constructScratchPad(intermediateSteps)
 
-	var stream func(ctx context.Context, chunk []byte) error
-
-	if o.CallbacksHandler != nil {
-		stream = func(ctx context.Context, chunk []byte) error {
-			o.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
-			return nil
-		}
-	}
+	stream := streamingFunc(ctx, o.CallbacksHandler)
 
 	prompt, err := o.Prompt.FormatPrompt(fullInputs)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	mcList := make([]llms.MessageContent, len(prompt.Messages()))
-	for i, msg := range prompt.Messages() {
-		role := msg.GetType()
-		text := msg.GetContent()
-
-		var mc llms.MessageContent
-
-		switch p := msg.(type) {
-		case llms.ToolChatMessage:
-			mc = llms.MessageContent{
-				Role: role,
-				Parts: []llms.ContentPart{llms.ToolCallResponse{
-					ToolCallID: p.ID,
-					Content:    p.Content,
-				}},
-			}
-
-		case llms.AIChatMessage:
-			mc = llms.MessageContent{
-				Role: role,
-				Parts: []llms.ContentPart{
-					llms.ToolCall{
-						ID:           p.ToolCalls[0].ID,
-						Type:         p.ToolCalls[0].Type,
-						FunctionCall: p.ToolCalls[0].FunctionCall,
-					},
-				},
-			}
-		default:
-			mc = llms.MessageContent{
-				Role:  role,
-				Parts: []llms.ContentPart{llms.TextContent{Text: text}},
-			}
-		}
-		mcList[i] = mc
-	}
-
-	result, err := o.LLM.GenerateContent(ctx, mcList,
+	result, err := o.LLM.GenerateContent(ctx, chatMessagesToContent(prompt.Messages()),
 		llms.WithFunctions(o.functions()), llms.WithStreamingFunc(stream))
 	if err != nil {
 		return nil, nil, err
 	}
+	addResponseUsage(ctx, result)
 
 	return o.ParseOutput(result)
 }
 
+// FinalAnswer asks the LLM for a final answer based on the function calls made
+// so far, without offering it any functions.
+func (o *OpenAIFunctionsAgent) FinalAnswer(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) (*schema.AgentFinish, error) {
+	fullInputs := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		fullInputs[key] = value
+	}
+	fullInputs[agentScratchpad] = o.constructScratchPad(intermediateSteps)
+
+	return generateMessagesFinalAnswer(ctx, o.LLM, o.Prompt, fullInputs, o.OutputKey,
+		streamingFunc(ctx, o.CallbacksHandler))
+}
+
 func (o *OpenAIFunctionsAgent) GetInputKeys() []string {
 	chainInputs := o.Prompt.GetInputVariables()
 
@@ -208,20 +174,11 @@ func (o *OpenAIFunctionsAgent) ParseOutput(contentResp *llms.ContentResponse) (
 	functionCall := choice.FuncCall
 	functionName := functionCall.Name
 	toolInputStr := functionCall.Arguments
-	toolInputMap := make(map[string]any, 0)
-	err := json.Unmarshal([]byte(toolInputStr), &toolInputMap)
+	toolInput, err := tools.InputFromArguments(findTool(o.Tools, functionName), toolInputStr)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	toolInput := toolInputStr
-	if arg1, ok := toolInputMap["__arg1"]; ok {
-		toolInputCheck, ok := arg1.(string)
-		if ok {
-			toolInput = toolInputCheck
-		}
-	}
-
 	contentMsg := "\n"
 	if choice.Content != "" {
 		contentMsg = fmt.Sprintf("responded: %s\n", choice.Content)
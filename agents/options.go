@@ -1,6 +1,8 @@
 package agents
 
 import (
+	"time"
+
 	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/memory"
 	"github.com/tmc/langchaingo/prompts"
@@ -13,13 +15,30 @@ type Options struct {
 	memory                  schema.Memory
 	callbacksHandler        callbacks.Handler
 	errorHandler            *ParserErrorHandler
+	toolErrorHandler        *ToolErrorHandler
+	guardrails              []Guardrail
 	maxIterations           int
+	maxConcurrency          int
+	earlyStoppingMethod     EarlyStoppingMethod
+	maxDuration             time.Duration
+	maxTokens               int
+	maxToolCalls            int
 	returnIntermediateSteps bool
 	outputKey               string
 	promptPrefix            string
 	formatInstructions      string
 	promptSuffix            string
 
+	// plan and execute
+	plannerPrompt   prompts.FormatPrompter
+	replannerPrompt prompts.FormatPrompter
+	maxPlanSteps    int
+
+	// reflection
+	criticPrompt        prompts.FormatPrompter
+	revisionPrompt      prompts.FormatPrompter
+	maxReflectionRounds int
+
 	// openai
 	systemMessage string
 	extraMessages []prompts.MessageFormatter
@@ -31,9 +50,11 @@ type Option func(*Options)
 
 func executorDefaultOptions() Options {
 	return Options{
-		maxIterations: _defaultMaxIterations,
-		outputKey:     _defaultOutputKey,
-		memory:        memory.NewSimple(),
+		maxIterations:       _defaultMaxIterations,
+		maxConcurrency:      _defaultMaxConcurrency,
+		earlyStoppingMethod: EarlyStoppingForce,
+		outputKey:           _defaultOutputKey,
+		memory:              memory.NewSimple(),
 	}
 }
 
@@ -55,6 +76,15 @@ func conversationalDefaultOptions() Options {
 	}
 }
 
+func chatReActDefaultOptions() Options {
+	return Options{
+		promptPrefix:       _defaultChatReActPrefix,
+		formatInstructions: _defaultChatReActFormatInstructions,
+		promptSuffix:       _defaultChatReActSuffix,
+		outputKey:          _defaultOutputKey,
+	}
+}
+
 func openAIFunctionsDefaultOptions() Options {
 	return Options{
 		systemMessage: "You are a helpful AI assistant.",
@@ -62,6 +92,26 @@ func openAIFunctionsDefaultOptions() Options {
 	}
 }
 
+func planAndExecuteDefaultOptions() Options {
+	return Options{
+		plannerPrompt:   createPlannerPrompt(),
+		replannerPrompt: createReplannerPrompt(),
+		maxPlanSteps:    _defaultMaxPlanSteps,
+		outputKey:       _defaultOutputKey,
+		memory:          memory.NewSimple(),
+	}
+}
+
+func reflectionDefaultOptions() Options {
+	return Options{
+		criticPrompt:        createCriticPrompt(),
+		revisionPrompt:      createRevisionPrompt(),
+		maxReflectionRounds: _defaultMaxReflectionRounds,
+		outputKey:           _defaultOutputKey,
+		memory:              memory.NewSimple(),
+	}
+}
+
 func (co Options) getMrklPrompt(tools []tools.Tool) prompts.PromptTemplate {
 	if co.prompt.Template != "" {
 		return co.prompt
@@ -96,6 +146,49 @@ func WithMaxIterations(iterations int) Option {
 	}
 }
 
+// WithMaxConcurrency is an option for setting the number of actions of one plan
+// the executor runs at the same time. By default the executor runs the actions
+// one at a time, whatever the agent; only raise it when the tools are safe for
+// concurrent use.
+func WithMaxConcurrency(n int) Option {
+	return func(co *Options) {
+		co.maxConcurrency = n
+	}
+}
+
+// WithEarlyStoppingMethod is an option for setting what the executor does when it
+// runs out of iterations or budget before the agent finished.
+func WithEarlyStoppingMethod(method EarlyStoppingMethod) Option {
+	return func(co *Options) {
+		co.earlyStoppingMethod = method
+	}
+}
+
+// WithMaxDuration is an option for limiting the wall time of a run of the executor.
+func WithMaxDuration(d time.Duration) Option {
+	return func(co *Options) {
+		co.maxDuration = d
+	}
+}
+
+// WithMaxTokens is an option for limiting the tokens the LLM of the agent uses in
+// a run of the executor. The agents created by this package count the tokens
+// their LLM reports in the Usage of its responses, an LLM not reporting them
+// is not limited. Other agents must report their tokens with AddTokenUsage.
+func WithMaxTokens(tokens int) Option {
+	return func(co *Options) {
+		co.maxTokens = tokens
+	}
+}
+
+// WithMaxToolCalls is an option for limiting the number of tool calls in a run of
+// the executor.
+func WithMaxToolCalls(calls int) Option {
+	return func(co *Options) {
+		co.maxToolCalls = calls
+	}
+}
+
 // WithOutputKey is an option for setting the output key of the agent.
 func WithOutputKey(outputKey string) Option {
 	return func(co *Options) {
@@ -161,6 +254,69 @@ func WithParserErrorHandler(errorHandler *ParserErrorHandler) Option {
 	}
 }
 
+// WithToolErrorHandler is an option for setting a tool error handler to an executor.
+func WithToolErrorHandler(errorHandler *ToolErrorHandler) Option {
+	return func(co *Options) {
+		co.toolErrorHandler = errorHandler
+	}
+}
+
+// WithPlannerPrompt is an option for setting the prompt a plan-and-execute chain uses
+// to make its plan.
+func WithPlannerPrompt(prompt prompts.FormatPrompter) Option {
+	return func(co *Options) {
+		co.plannerPrompt = prompt
+	}
+}
+
+// WithReplannerPrompt is an option for setting the prompt a plan-and-execute chain
+// uses to revise its plan after every step.
+func WithReplannerPrompt(prompt prompts.FormatPrompter) Option {
+	return func(co *Options) {
+		co.replannerPrompt = prompt
+	}
+}
+
+// WithMaxPlanSteps is an option for setting the max number of steps a plan-and-execute
+// chain carries out.
+func WithMaxPlanSteps(steps int) Option {
+	return func(co *Options) {
+		co.maxPlanSteps = steps
+	}
+}
+
+// WithCriticPrompt is an option for setting the prompt the critic of a reflection
+// chain uses to critique a draft.
+func WithCriticPrompt(prompt prompts.FormatPrompter) Option {
+	return func(co *Options) {
+		co.criticPrompt = prompt
+	}
+}
+
+// WithRevisionPrompt is an option for setting the prompt a reflection chain gives
+// its producer to revise a draft.
+func WithRevisionPrompt(prompt prompts.FormatPrompter) Option {
+	return func(co *Options) {
+		co.revisionPrompt = prompt
+	}
+}
+
+// WithMaxReflectionRounds is an option for setting the max number of revisions a
+// reflection chain makes.
+func WithMaxReflectionRounds(rounds int) Option {
+	return func(co *Options) {
+		co.maxReflectionRounds = rounds
+	}
+}
+
+// WithGuardrails is an option for adding guardrails checking the actions of the agent
+// before the executor runs them.
+func WithGuardrails(guardrails ...Guardrail) Option {
+	return func(co *Options) {
+		co.guardrails = append(co.guardrails, guardrails...)
+	}
+}
+
 type OpenAIOption struct{}
 
 func NewOpenAIOption() OpenAIOption {
@@ -0,0 +1,269 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/outputparser"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	// PlanOutputKey is the output key of PlanAndExecute holding the plan as a
+	// []string. It holds the steps done followed by the steps left, if any.
+	PlanOutputKey = "plan"
+	// StepResultsOutputKey is the output key of PlanAndExecute holding the
+	// results of the steps done as a []PlanStepResult.
+	StepResultsOutputKey = "step_results"
+
+	_defaultMaxPlanSteps = 10
+)
+
+// PlanStepResult is a step of a plan carried out by PlanAndExecute.
+type PlanStepResult struct {
+	// Step is the step as written by the planner.
+	Step string
+	// Output is the output of the step executor for the step.
+	Output string
+}
+
+// PlanAndExecute is a chain that first asks an LLM for a plan to answer the
+// input, and then has a step executor carry out the steps of the plan one by
+// one. After every step the LLM revises the steps left based on the results so
+// far, or gives the final answer.
+//
+// Agents deciding on one action at a time tend to lose track of long tasks.
+// The plan keeps them on track, and it is returned along with the results of
+// the steps under PlanOutputKey and StepResultsOutputKey.
+type PlanAndExecute struct {
+	// Planner is the LLM making and revising the plan.
+	Planner llms.Model
+	// PlannerPrompt is the prompt used to make the plan. It gets the "input"
+	// and the "format_instructions" of the parser.
+	PlannerPrompt prompts.FormatPrompter
+	// ReplannerPrompt is the prompt used to revise the plan after every step.
+	// On top of the inputs of the planner prompt it gets the "plan" and the
+	// "past_steps" with their results. The LLM answers with the steps left, or
+	// with "Final Answer:" followed by the answer.
+	ReplannerPrompt prompts.FormatPrompter
+	// Parser parses the steps of the plan from the output of the planner.
+	Parser schema.OutputParser[[]string]
+	// StepExecutor carries out every step. Its agent gets the step, along with
+	// the plan and the results so far, as "input".
+	StepExecutor *Executor
+
+	Memory           schema.Memory
+	CallbacksHandler callbacks.Handler
+	// OutputKey is the key where the final answer is placed.
+	OutputKey string
+	// MaxSteps is the number of steps carried out before the run stops with
+	// ErrNotFinished.
+	MaxSteps int
+}
+
+var (
+	_ chains.Chain           = &PlanAndExecute{}
+	_ callbacks.HandlerHaver = &PlanAndExecute{}
+)
+
+// NewPlanAndExecute creates a plan-and-execute chain with an LLM making the
+// plan and an agent carrying out its steps. The options also apply to the
+// executor running the agent, except for the memory which is only used by the
+// plan-and-execute chain.
+func NewPlanAndExecute(planner llms.Model, agent Agent, opts ...Option) *PlanAndExecute {
+	options := planAndExecuteDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	stepExecutor := NewExecutor(agent, opts...)
+	stepExecutor.Memory = memory.NewSimple()
+
+	return &PlanAndExecute{
+		Planner:          planner,
+		PlannerPrompt:    options.plannerPrompt,
+		ReplannerPrompt:  options.replannerPrompt,
+		Parser:           outputparser.NewNumberedList(),
+		StepExecutor:     stepExecutor,
+		Memory:           options.memory,
+		CallbacksHandler: options.callbacksHandler,
+		OutputKey:        options.outputKey,
+		MaxSteps:         options.maxPlanSteps,
+	}
+}
+
+// Call makes a plan for the input and carries it out.
+func (p *PlanAndExecute) Call(ctx context.Context, values map[string]any, _ ...chains.ChainCallOption) (map[string]any, error) { //nolint:lll
+	inputs, err := inputsToString(values)
+	if err != nil {
+		return nil, err
+	}
+	promptValues := map[string]any{
+		"input":               inputs["input"],
+		"format_instructions": p.Parser.GetFormatInstructions(),
+	}
+
+	output, err := p.generate(ctx, p.PlannerPrompt, promptValues)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := p.Parser.Parse(output)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnableToParseOutput, err)
+	}
+	if len(plan) == 0 {
+		return nil, fmt.Errorf("%w: plan has no steps", ErrUnableToParseOutput)
+	}
+
+	// Every replanning gives a final answer or at least one step left.
+	results := make([]PlanStepResult, 0, len(plan))
+	for {
+		if len(results) >= p.MaxSteps {
+			err := fmt.Errorf("%w: plan has more than %d steps", ErrNotFinished, p.MaxSteps)
+			return p.getReturn("", plan, results), err
+		}
+
+		result, err := p.doStep(ctx, inputs["input"], plan, results)
+		if err != nil {
+			return p.getReturn("", plan, results), err
+		}
+		results = append(results, result)
+
+		promptValues["plan"] = formatPlan(plan)
+		promptValues["past_steps"] = formatPastSteps(results)
+		answer, steps, err := p.replan(ctx, promptValues)
+		if err != nil {
+			return p.getReturn("", plan, results), err
+		}
+		plan = append(plan[:len(results):len(results)], steps...)
+		if answer != "" {
+			return p.finish(ctx, answer, plan, results), nil
+		}
+	}
+}
+
+// doStep has the step executor carry out the next step of the plan.
+func (p *PlanAndExecute) doStep(
+	ctx context.Context,
+	input string,
+	plan []string,
+	results []PlanStepResult,
+) (PlanStepResult, error) {
+	step := plan[len(results)]
+	outputs, err := chains.Call(ctx, p.StepExecutor, map[string]any{
+		"input": formatStepInput(input, plan, results),
+	})
+	if err != nil {
+		return PlanStepResult{}, fmt.Errorf("step %d %q: %w", len(results)+1, step, err)
+	}
+	return PlanStepResult{Step: step, Output: chainOutput(p.StepExecutor, outputs)}, nil
+}
+
+// replan asks the planner for the final answer or the steps left.
+func (p *PlanAndExecute) replan(ctx context.Context, values map[string]any) (string, []string, error) {
+	output, err := p.generate(ctx, p.ReplannerPrompt, values)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, answer, ok := strings.Cut(output, _finalAnswerAction); ok {
+		return strings.TrimSpace(answer), nil, nil
+	}
+	steps, err := p.Parser.Parse(output)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrUnableToParseOutput, err)
+	}
+	if len(steps) == 0 {
+		return "", nil, fmt.Errorf("%w: no final answer and no steps left", ErrUnableToParseOutput)
+	}
+	return "", steps, nil
+}
+
+func (p *PlanAndExecute) generate(ctx context.Context, prompt prompts.FormatPrompter, values map[string]any) (string, error) { //nolint:lll
+	return generateFromPrompt(ctx, p.Planner, prompt, values, p.CallbacksHandler)
+}
+
+func (p *PlanAndExecute) finish(
+	ctx context.Context,
+	answer string,
+	plan []string,
+	results []PlanStepResult,
+) map[string]any {
+	if p.CallbacksHandler != nil {
+		p.CallbacksHandler.HandleAgentFinish(ctx, schema.AgentFinish{
+			ReturnValues: map[string]any{p.OutputKey: answer},
+			Log:          answer,
+		})
+	}
+	return p.getReturn(answer, plan, results)
+}
+
+func (p *PlanAndExecute) getReturn(answer string, plan []string, results []PlanStepResult) map[string]any {
+	outputs := map[string]any{
+		PlanOutputKey:        plan,
+		StepResultsOutputKey: results,
+	}
+	if answer != "" {
+		outputs[p.OutputKey] = answer
+	}
+	return outputs
+}
+
+// GetInputKeys returns the input keys of the chain, which is "input".
+func (p *PlanAndExecute) GetInputKeys() []string {
+	return []string{"input"}
+}
+
+// GetOutputKeys returns the output keys of the chain.
+func (p *PlanAndExecute) GetOutputKeys() []string {
+	return []string{p.OutputKey, PlanOutputKey, StepResultsOutputKey}
+}
+
+func (p *PlanAndExecute) GetMemory() schema.Memory { //nolint:ireturn
+	return p.Memory
+}
+
+func (p *PlanAndExecute) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
+	return p.CallbacksHandler
+}
+
+// generateFromPrompt formats the prompt with the values and returns the content
+// of the first choice the LLM generates for it.
+func generateFromPrompt(
+	ctx context.Context,
+	llm llms.Model,
+	prompt prompts.FormatPrompter,
+	values map[string]any,
+	handler callbacks.Handler,
+) (string, error) {
+	value, err := prompt.FormatPrompt(values)
+	if err != nil {
+		return "", err
+	}
+	resp, err := llm.GenerateContent(ctx, chatMessagesToContent(value.Messages()),
+		llms.WithStreamingFunc(streamingFunc(ctx, handler)))
+	if err != nil {
+		return "", err
+	}
+	addResponseUsage(ctx, resp)
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("%w: no choices in response", ErrUnableToParseOutput)
+	}
+	return resp.Choices[0].Content, nil
+}
+
+// chainOutput returns the first output of a chain that is a string, looking at
+// the output keys of the chain in order.
+func chainOutput(chain chains.Chain, outputs map[string]any) string {
+	for _, key := range chain.GetOutputKeys() {
+		if output, ok := outputs[key].(string); ok {
+			return output
+		}
+	}
+	return ""
+}
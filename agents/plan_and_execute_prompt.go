@@ -0,0 +1,87 @@
+package agents
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/prompts"
+)
+
+const (
+	_defaultPlannerTemplate = `Let's first understand the request below and devise a plan to solve it.
+Keep the plan short, make every step a self-contained task, and make the last step give the final answer to the request.
+
+{{.format_instructions}}
+
+Request: {{.input}}
+
+Plan:`
+
+	_defaultReplannerTemplate = `You are revising a plan made for the request below.
+
+Request: {{.input}}
+
+The plan was:
+{{.plan}}
+
+These steps are done, with their results:
+{{.past_steps}}
+
+If the results answer the request, respond with "Final Answer:" followed by the answer to the request.
+Otherwise respond with the steps still to do, leaving out the steps already done. {{.format_instructions}}`
+
+	_stepExecutorTemplate = `You are carrying out one step of a plan made for the request below.
+
+Request: %s
+
+The plan is:
+%s
+
+%sYour task is step %d: %s
+Give the result of this step.`
+)
+
+func createPlannerPrompt() prompts.PromptTemplate {
+	return prompts.PromptTemplate{
+		Template:       _defaultPlannerTemplate,
+		TemplateFormat: prompts.TemplateFormatGoTemplate,
+		InputVariables: []string{"input", "format_instructions"},
+	}
+}
+
+func createReplannerPrompt() prompts.PromptTemplate {
+	return prompts.PromptTemplate{
+		Template:       _defaultReplannerTemplate,
+		TemplateFormat: prompts.TemplateFormatGoTemplate,
+		InputVariables: []string{"input", "plan", "past_steps", "format_instructions"},
+	}
+}
+
+// formatPlan formats the steps of a plan as a numbered list.
+func formatPlan(steps []string) string {
+	var sb strings.Builder
+	for i, step := range steps {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, step)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// formatPastSteps formats the steps done so far with their results.
+func formatPastSteps(results []PlanStepResult) string {
+	var sb strings.Builder
+	for i, result := range results {
+		fmt.Fprintf(&sb, "Step %d: %s\nResult: %s\n\n", i+1, result.Step, result.Output)
+	}
+	return strings.TrimSuffix(sb.String(), "\n\n")
+}
+
+// formatStepInput formats the input given to the step executor for the next
+// step of the plan.
+func formatStepInput(input string, plan []string, results []PlanStepResult) string {
+	var past string
+	if len(results) > 0 {
+		past = "These steps are done, with their results:\n" + formatPastSteps(results) + "\n\n"
+	}
+	step := len(results)
+	return fmt.Sprintf(_stepExecutorTemplate, input, formatPlan(plan), past, step+1, plan[step])
+}
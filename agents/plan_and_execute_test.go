@@ -0,0 +1,144 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/outputparser"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func textResponses(texts ...string) []*llms.ContentResponse {
+	responses := make([]*llms.ContentResponse, 0, len(texts))
+	for _, text := range texts {
+		responses = append(responses, &llms.ContentResponse{
+			Choices: []*llms.ContentChoice{{Content: text}},
+		})
+	}
+	return responses
+}
+
+// emptyPlanParser parses the outputs starting a numbered list as first, and the
+// others as no steps without an error.
+type emptyPlanParser struct {
+	outputparser.NumberedList
+	first []string
+}
+
+func (p emptyPlanParser) Parse(text string) ([]string, error) {
+	if strings.HasPrefix(text, "1.") {
+		return p.first, nil
+	}
+	return nil, nil
+}
+
+func TestPlanAndExecute(t *testing.T) {
+	t.Parallel()
+
+	planner := &toolCallingLLM{responses: textResponses(
+		"Plan:\n1. Upper-case a\n2. Look up b\n3. Combine the results",
+		"1. Combine the results with b",
+		"Final Answer: A and b",
+	)}
+	stepLLM := &toolCallingLLM{responses: []*llms.ContentResponse{
+		{Choices: []*llms.ContentChoice{{ToolCalls: []llms.ToolCall{toolCall("call_1", "upper", `{"input": "a"}`)}}}},
+		{Choices: []*llms.ContentChoice{{Content: "A"}}},
+		{Choices: []*llms.ContentChoice{{Content: "A and b"}}},
+	}}
+	chain := NewPlanAndExecute(planner, NewToolCallingAgent(stepLLM, []tools.Tool{upperTool{}}))
+
+	outputs, err := chains.Call(context.Background(), chain, map[string]any{"input": "a and b"})
+	require.NoError(t, err)
+	assert.Equal(t, "A and b", outputs["output"])
+	assert.Equal(t, []string{"Upper-case a", "Combine the results with b"}, outputs[PlanOutputKey])
+	assert.Equal(t, []PlanStepResult{
+		{Step: "Upper-case a", Output: "A"},
+		{Step: "Combine the results with b", Output: "A and b"},
+	}, outputs[StepResultsOutputKey])
+
+	// The second step is given the revised plan and the result of the first.
+	require.Len(t, stepLLM.calls, 3)
+	input := stepLLM.calls[2][len(stepLLM.calls[2])-1].Parts[0].(llms.TextContent).Text
+	assert.Contains(t, input, "1. Upper-case a\n2. Combine the results with b")
+	assert.Contains(t, input, "Step 1: Upper-case a\nResult: A")
+	assert.Contains(t, input, "Your task is step 2: Combine the results with b")
+}
+
+func TestPlanAndExecuteStops(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		planner   []string
+		steps     []string
+		opts      []Option
+		parser    schema.OutputParser[[]string]
+		wantSteps int
+		wantErr   error
+	}{
+		{
+			name:      "bad replan",
+			planner:   []string{"1. foo", "Nothing left to do."},
+			steps:     []string{"bar"},
+			wantSteps: 1,
+			wantErr:   ErrUnableToParseOutput,
+		},
+		{
+			name:      "no final answer",
+			planner:   []string{"1. foo\n2. bar", "1. bar", "1. baz"},
+			steps:     []string{"FOO", "BAR"},
+			opts:      []Option{WithMaxPlanSteps(2)},
+			wantSteps: 2,
+			wantErr:   ErrNotFinished,
+		},
+		{
+			name:      "empty plan",
+			planner:   []string{"Nothing to do."},
+			parser:    emptyPlanParser{},
+			wantSteps: -1,
+			wantErr:   ErrUnableToParseOutput,
+		},
+		{
+			name:      "empty replan",
+			planner:   []string{"1. foo", "Nothing left to do."},
+			steps:     []string{"bar"},
+			parser:    emptyPlanParser{first: []string{"foo"}},
+			wantSteps: 1,
+			wantErr:   ErrUnableToParseOutput,
+		},
+		{
+			name:      "bad plan",
+			planner:   []string{"I cannot plan this."},
+			wantSteps: -1,
+			wantErr:   ErrUnableToParseOutput,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			planner := &toolCallingLLM{responses: textResponses(tc.planner...)}
+			stepLLM := &toolCallingLLM{responses: textResponses(tc.steps...)}
+			chain := NewPlanAndExecute(planner, NewToolCallingAgent(stepLLM, nil), tc.opts...)
+			if tc.parser != nil {
+				chain.Parser = tc.parser
+			}
+
+			outputs, err := chains.Call(context.Background(), chain, map[string]any{"input": "foo"})
+			require.ErrorIs(t, err, tc.wantErr)
+			if tc.wantSteps < 0 {
+				assert.Nil(t, outputs)
+				return
+			}
+			assert.Len(t, outputs[StepResultsOutputKey], tc.wantSteps)
+			assert.NotContains(t, outputs, "output")
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	// DraftsOutputKey is the output key of Reflection holding all the drafts
+	// of the producer as a []string, the last one being the final output.
+	DraftsOutputKey = "drafts"
+	// CritiquesOutputKey is the output key of Reflection holding the critiques
+	// of the drafts as a []string. The critique of a draft has the same index.
+	CritiquesOutputKey = "critiques"
+	// AcceptedOutputKey is the output key of Reflection telling, as a bool,
+	// whether the critic accepted the final output.
+	AcceptedOutputKey = "accepted"
+
+	_defaultMaxReflectionRounds = 3
+
+	_defaultCriticTemplate = `You are reviewing a response to the request below.
+
+Request: {{.input}}
+
+Response:
+{{.draft}}
+
+Point out what is wrong or missing in the response and how to improve it.
+Then end with the line "Verdict: ACCEPT" if the response fully answers the request, or "Verdict: REVISE" if it should be revised.`
+
+	_defaultRevisionTemplate = `{{.input}}
+
+Your previous response to this request was:
+{{.draft}}
+
+A reviewer gave the following feedback on it:
+{{.critique}}
+
+Write an improved response that addresses the feedback.`
+)
+
+// _verdictRegex matches the verdict at the end of a critique.
+var _verdictRegex = regexp.MustCompile(`(?i)verdict\W*(accept|revise)`)
+
+// Reflection is a chain that improves the output of a producer by letting a
+// critic review it. The producer makes a draft for the input, the critic
+// critiques it, and the producer revises the draft based on the critique.
+// This goes on until the critic accepts a draft or MaxRounds revisions were
+// made. All drafts and critiques are returned under DraftsOutputKey and
+// CritiquesOutputKey.
+//
+// The producer can be any chain. With an Executor the agent can use its tools
+// for the revisions as well.
+type Reflection struct {
+	// Producer makes the drafts. It gets the input for the first draft and the
+	// formatted RevisionPrompt for the next ones as "input".
+	Producer chains.Chain
+	// Critic is the LLM critiquing the drafts.
+	Critic llms.Model
+	// CriticPrompt is the prompt of the critic. It gets the "input" and the
+	// "draft" to critique. The critique should end with "Verdict: ACCEPT" or
+	// "Verdict: REVISE", anything else counts as a rejection.
+	CriticPrompt prompts.FormatPrompter
+	// RevisionPrompt is the input given to the producer to revise a draft. It
+	// gets the "input", the "draft" and its "critique".
+	RevisionPrompt prompts.FormatPrompter
+
+	Memory           schema.Memory
+	CallbacksHandler callbacks.Handler
+	// OutputKey is the key where the final draft is placed.
+	OutputKey string
+	// MaxRounds is the number of revisions made at most.
+	MaxRounds int
+}
+
+var (
+	_ chains.Chain           = &Reflection{}
+	_ callbacks.HandlerHaver = &Reflection{}
+)
+
+// NewReflection creates a reflection chain with a producer, such as an
+// Executor running an agent, and an LLM critiquing its drafts.
+func NewReflection(producer chains.Chain, critic llms.Model, opts ...Option) *Reflection {
+	options := reflectionDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &Reflection{
+		Producer:         producer,
+		Critic:           critic,
+		CriticPrompt:     options.criticPrompt,
+		RevisionPrompt:   options.revisionPrompt,
+		Memory:           options.memory,
+		CallbacksHandler: options.callbacksHandler,
+		OutputKey:        options.outputKey,
+		MaxRounds:        options.maxReflectionRounds,
+	}
+}
+
+// Call runs the producer and the critic until the critic accepts a draft or
+// the rounds run out.
+func (r *Reflection) Call(ctx context.Context, values map[string]any, _ ...chains.ChainCallOption) (map[string]any, error) { //nolint:lll
+	input, ok := values["input"].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: input", ErrExecutorInputNotString)
+	}
+
+	draft, err := r.produce(ctx, values, input)
+	if err != nil {
+		return nil, err
+	}
+	drafts := []string{draft}
+	critiques := make([]string, 0, r.MaxRounds)
+	accepted := false
+	for round := 0; round < r.MaxRounds; round++ {
+		promptValues := map[string]any{"input": input, "draft": draft}
+		critique, err := generateFromPrompt(ctx, r.Critic, r.CriticPrompt, promptValues, r.CallbacksHandler)
+		if err != nil {
+			return r.getReturn(drafts, critiques, false), err
+		}
+		critiques = append(critiques, critique)
+		if accepted = isAccepted(critique); accepted {
+			break
+		}
+
+		promptValues["critique"] = critique
+		revision, err := r.RevisionPrompt.FormatPrompt(promptValues)
+		if err != nil {
+			return r.getReturn(drafts, critiques, false), err
+		}
+		if draft, err = r.produce(ctx, values, revision.String()); err != nil {
+			return r.getReturn(drafts, critiques, false), err
+		}
+		drafts = append(drafts, draft)
+	}
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleAgentFinish(ctx, schema.AgentFinish{
+			ReturnValues: map[string]any{r.OutputKey: draft},
+			Log:          draft,
+		})
+	}
+	outputs := r.getReturn(drafts, critiques, accepted)
+	outputs[r.OutputKey] = draft
+	return outputs, nil
+}
+
+// produce calls the producer with the values of the call and the given input.
+func (r *Reflection) produce(ctx context.Context, values map[string]any, input string) (string, error) {
+	producerValues := make(map[string]any, len(values))
+	for key, value := range values {
+		producerValues[key] = value
+	}
+	producerValues["input"] = input
+
+	outputs, err := chains.Call(ctx, r.Producer, producerValues)
+	if err != nil {
+		return "", err
+	}
+	return chainOutput(r.Producer, outputs), nil
+}
+
+func (r *Reflection) getReturn(drafts, critiques []string, accepted bool) map[string]any {
+	return map[string]any{
+		DraftsOutputKey:    drafts,
+		CritiquesOutputKey: critiques,
+		AcceptedOutputKey:  accepted,
+	}
+}
+
+// isAccepted tells whether the last verdict of a critique accepts the draft.
+func isAccepted(critique string) bool {
+	matches := _verdictRegex.FindAllStringSubmatch(critique, -1)
+	if len(matches) == 0 {
+		return false
+	}
+	return strings.EqualFold(matches[len(matches)-1][1], "accept")
+}
+
+// GetInputKeys returns the input keys of the producer.
+func (r *Reflection) GetInputKeys() []string {
+	return r.Producer.GetInputKeys()
+}
+
+// GetOutputKeys returns the output keys of the chain.
+func (r *Reflection) GetOutputKeys() []string {
+	return []string{r.OutputKey, DraftsOutputKey, CritiquesOutputKey, AcceptedOutputKey}
+}
+
+func (r *Reflection) GetMemory() schema.Memory { //nolint:ireturn
+	return r.Memory
+}
+
+func (r *Reflection) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
+	return r.CallbacksHandler
+}
+
+func createCriticPrompt() prompts.PromptTemplate {
+	return prompts.NewPromptTemplate(_defaultCriticTemplate, []string{"input", "draft"})
+}
+
+func createRevisionPrompt() prompts.PromptTemplate {
+	return prompts.NewPromptTemplate(_defaultRevisionTemplate, []string{"input", "draft", "critique"})
+}
@@ -0,0 +1,60 @@
+package agents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestReflection(t *testing.T) {
+	t.Parallel()
+
+	producerLLM := &toolCallingLLM{responses: []*llms.ContentResponse{
+		{Choices: []*llms.ContentChoice{{Content: "a"}}},
+		{Choices: []*llms.ContentChoice{{ToolCalls: []llms.ToolCall{toolCall("call_1", "upper", `{"input": "a"}`)}}}},
+		{Choices: []*llms.ContentChoice{{Content: "A"}}},
+	}}
+	critic := &toolCallingLLM{responses: textResponses(
+		"The response should be upper-cased.\nVerdict: REVISE",
+		"Looks good.\n**Verdict:** Accept",
+	)}
+	producer := NewExecutor(NewToolCallingAgent(producerLLM, []tools.Tool{upperTool{}}))
+	chain := NewReflection(producer, critic)
+
+	outputs, err := chains.Call(context.Background(), chain, map[string]any{"input": "upper-case a"})
+	require.NoError(t, err)
+	assert.Equal(t, "A", outputs["output"])
+	assert.Equal(t, []string{"a", "A"}, outputs[DraftsOutputKey])
+	assert.Equal(t, []string{
+		"The response should be upper-cased.\nVerdict: REVISE",
+		"Looks good.\n**Verdict:** Accept",
+	}, outputs[CritiquesOutputKey])
+	assert.Equal(t, true, outputs[AcceptedOutputKey])
+
+	// The producer revises the draft with the critique, using its tools.
+	require.Len(t, producerLLM.calls, 3)
+	revision := producerLLM.calls[1][1].Parts[0].(llms.TextContent).Text
+	assert.Contains(t, revision, "upper-case a\n\nYour previous response to this request was:\na")
+	assert.Contains(t, revision, "The response should be upper-cased.")
+	assert.Contains(t, critic.calls[1][0].Parts[0].(llms.TextContent).Text, "Response:\nA")
+}
+
+func TestReflectionMaxRounds(t *testing.T) {
+	t.Parallel()
+
+	producerLLM := &toolCallingLLM{responses: textResponses("a", "b")}
+	critic := &toolCallingLLM{responses: textResponses("Verdict: ACCEPT? No. Verdict: REVISE")}
+	chain := NewReflection(NewExecutor(NewToolCallingAgent(producerLLM, nil)), critic, WithMaxReflectionRounds(1))
+
+	outputs, err := chains.Call(context.Background(), chain, map[string]any{"input": "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, "b", outputs["output"])
+	assert.Equal(t, []string{"a", "b"}, outputs[DraftsOutputKey])
+	assert.Len(t, outputs[CritiquesOutputKey], 1)
+	assert.Equal(t, false, outputs[AcceptedOutputKey])
+}
@@ -0,0 +1,199 @@
+package agents
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// EventType is the kind of an event emitted by Executor.Stream.
+type EventType string
+
+const (
+	// EventPlanStart is emitted before the agent plans the next actions.
+	EventPlanStart EventType = "plan_start"
+	// EventToken is emitted for every chunk streamed by the LLM of the agent.
+	EventToken EventType = "token"
+	// EventAction is emitted for every action the agent chose.
+	EventAction EventType = "action"
+	// EventToolOutput is emitted once the tool of an action returned.
+	EventToolOutput EventType = "tool_output"
+	// EventFinalAnswer is emitted when the agent finished.
+	EventFinalAnswer EventType = "final_answer"
+)
+
+// Event is emitted by Executor.Stream while the executor runs. Type tells
+// which of the other fields is set.
+type Event struct {
+	Type EventType
+	// Iteration is the iteration of the executor the event belongs to,
+	// counted from 1.
+	Iteration int
+	// Chunk is a chunk streamed by the LLM, for EventToken.
+	Chunk []byte
+	// Action is the action chosen, for EventAction and EventToolOutput.
+	Action *schema.AgentAction
+	// Observation is the output of the tool, for EventToolOutput.
+	Observation string
+	// Finish is the final answer, for EventFinalAnswer.
+	Finish *schema.AgentFinish
+}
+
+// EventSeq is an iterator over the events of a streamed run. Once the run
+// ends it yields a final zero event with the error of the run, if any. With
+// Go 1.23 or later it can be used with range:
+//
+//	for event, err := range executor.Stream(ctx, inputs) {
+//		...
+//	}
+type EventSeq func(yield func(Event, error) bool)
+
+// Stream runs the executor like Call and returns an iterator over the events
+// of the run. The run starts when the iteration starts and is canceled if the
+// iteration stops early or ctx is canceled.
+//
+// Chunks streamed by the LLM are emitted for the agents of this package. Other
+// agents can take part by passing the function returned by StreamingFunc to
+// their LLM.
+func (e *Executor) Stream(ctx context.Context, inputs map[string]any) EventSeq {
+	return func(yield func(Event, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		em := &emitter{
+			events: make(chan Event),
+			acks:   make(chan struct{}),
+			done:   ctx.Done(),
+		}
+		errc := make(chan error, 1)
+		go func() {
+			defer close(em.events)
+			_, err := e.Call(context.WithValue(ctx, emitterContextKey{}, em), inputs)
+			errc <- err
+		}()
+
+		for event := range em.events {
+			if !yield(event, nil) {
+				cancel()
+				for range em.events { //nolint:revive // Drain until the run stops.
+				}
+				return
+			}
+			select {
+			case em.acks <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if err := <-errc; err != nil {
+			yield(Event{}, err)
+		}
+	}
+}
+
+// StreamChan runs the executor like Stream and sends the events of the run to
+// the returned event channel, which is closed once the run ended. The error of
+// the run is then sent to the error channel. To stop the run early cancel ctx.
+func (e *Executor) StreamChan(ctx context.Context, inputs map[string]any) (<-chan Event, <-chan error) {
+	events := make(chan Event)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		var runErr error
+		e.Stream(ctx, inputs)(func(event Event, err error) bool {
+			if err != nil {
+				runErr = err
+				return false
+			}
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				runErr = ctx.Err()
+				return false
+			}
+		})
+		close(events)
+		errc <- runErr
+	}()
+	return events, errc
+}
+
+type emitterContextKey struct{}
+
+// emitter sends the events of a run to a stream. Every event is acknowledged
+// once the consumer handled it, so the run does not get ahead of the consumer
+// and stops right after the consumer stopped.
+type emitter struct {
+	events chan Event
+	acks   chan struct{}
+	done   <-chan struct{}
+
+	// mu serializes the events of actions running concurrently.
+	mu        sync.Mutex
+	iteration int
+}
+
+func emitterFromContext(ctx context.Context) *emitter {
+	em, _ := ctx.Value(emitterContextKey{}).(*emitter)
+	return em
+}
+
+func (em *emitter) setIteration(iteration int) {
+	if em == nil {
+		return
+	}
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	em.iteration = iteration
+}
+
+func (em *emitter) emit(event Event) {
+	if em == nil {
+		return
+	}
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	event.Iteration = em.iteration
+	select {
+	case em.events <- event:
+	case <-em.done:
+		return
+	}
+	select {
+	case <-em.acks:
+	case <-em.done:
+	}
+}
+
+// StreamingFunc returns a function for llms.WithStreamingFunc that emits the
+// chunks streamed by an LLM as EventToken events of the run of Executor.Stream
+// ctx belongs to. Outside of a streamed run it returns nil.
+func StreamingFunc(ctx context.Context) func(context.Context, []byte) error {
+	em := emitterFromContext(ctx)
+	if em == nil {
+		return nil
+	}
+	return func(_ context.Context, chunk []byte) error {
+		em.emit(Event{Type: EventToken, Chunk: append([]byte(nil), chunk...)})
+		return nil
+	}
+}
+
+// streamingFunc returns the streaming function of an agent, which reports the
+// chunks to the callbacks handler of the agent and emits them as events. It is
+// nil if there is nothing to report the chunks to.
+func streamingFunc(ctx context.Context, handler callbacks.Handler) func(context.Context, []byte) error {
+	emit := StreamingFunc(ctx)
+	if handler == nil {
+		return emit
+	}
+	return func(ctx context.Context, chunk []byte) error {
+		handler.HandleStreamingFunc(ctx, chunk)
+		if emit != nil {
+			return emit(ctx, chunk)
+		}
+		return nil
+	}
+}
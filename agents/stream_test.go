@@ -0,0 +1,72 @@
+package agents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func newStreamExecutor() *Executor {
+	llm := &toolCallingLLM{responses: []*llms.ContentResponse{
+		{Choices: []*llms.ContentChoice{{ToolCalls: []llms.ToolCall{
+			toolCall("call_1", "upper", `{"input": "a"}`),
+		}}}},
+		{Choices: []*llms.ContentChoice{{Content: "A"}}},
+	}}
+	return NewExecutor(NewToolCallingAgent(llm, []tools.Tool{upperTool{}}))
+}
+
+func TestExecutorStream(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	newStreamExecutor().Stream(context.Background(), map[string]any{"input": "a"})(func(event Event, err error) bool {
+		require.NoError(t, err)
+		desc := string(event.Type)
+		switch event.Type {
+		case EventToken:
+			desc += " " + string(event.Chunk)
+		case EventAction:
+			desc += " " + event.Action.Tool
+		case EventToolOutput:
+			desc += " " + event.Observation
+		case EventFinalAnswer:
+			desc += " " + event.Finish.ReturnValues["output"].(string) //nolint:forcetypeassert
+		case EventPlanStart:
+		}
+		got = append(got, desc)
+		return true
+	})
+	assert.Equal(t, []string{
+		"plan_start", "action upper", "tool_output A",
+		"plan_start", "token A", "final_answer A",
+	}, got)
+}
+
+func TestExecutorStreamChan(t *testing.T) {
+	t.Parallel()
+
+	events, errc := newStreamExecutor().StreamChan(context.Background(), map[string]any{"input": "a"})
+	var iterations []int
+	for event := range events {
+		iterations = append(iterations, event.Iteration)
+	}
+	require.NoError(t, <-errc)
+	assert.Equal(t, []int{1, 1, 1, 2, 2, 2}, iterations)
+}
+
+func TestExecutorStreamStop(t *testing.T) {
+	t.Parallel()
+
+	var got []EventType
+	newStreamExecutor().Stream(context.Background(), map[string]any{"input": "a"})(func(event Event, err error) bool {
+		require.NoError(t, err)
+		got = append(got, event.Type)
+		return event.Type != EventAction
+	})
+	assert.Equal(t, []EventType{EventPlanStart, EventAction}, got)
+}
@@ -0,0 +1,269 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// ToolCallingAgent is an Agent driven by the native tool calling of the LLM.
+// It works with every llms.Model that returns llms.ToolCall values in its
+// choices, such as the OpenAI, Anthropic, Google AI, Ollama and Mistral models.
+// When the model asks for several tools at once, one action is returned per
+// tool call.
+type ToolCallingAgent struct {
+	// LLM is the llm used to call with the values. The prompt should have a
+	// messages placeholder called "agent_scratchpad" for the tool calls and
+	// their results.
+	LLM    llms.Model
+	Prompt prompts.FormatPrompter
+	// Tools is a list of the tools the agent can use.
+	Tools []tools.Tool
+	// Output key is the key where the final output is placed.
+	OutputKey string
+	// CallbacksHandler is the handler for callbacks.
+	CallbacksHandler callbacks.Handler
+}
+
+var (
+	_ Agent            = (*ToolCallingAgent)(nil)
+	_ FinalAnswerAgent = (*ToolCallingAgent)(nil)
+)
+
+// NewToolCallingAgent creates a new ToolCallingAgent. The system message and
+// extra messages of the prompt can be set with the options of OpenAIOption.
+func NewToolCallingAgent(llm llms.Model, tools []tools.Tool, opts ...Option) *ToolCallingAgent {
+	options := openAIFunctionsDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &ToolCallingAgent{
+		LLM:              llm,
+		Prompt:           createOpenAIFunctionPrompt(options),
+		Tools:            tools,
+		OutputKey:        options.outputKey,
+		CallbacksHandler: options.callbacksHandler,
+	}
+}
+
+// Plan decides what actions to take or returns the final result of the input.
+func (a *ToolCallingAgent) Plan(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	fullInputs := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		fullInputs[key] = value
+	}
+	fullInputs[agentScratchpad] = a.constructScratchPad(intermediateSteps)
+
+	prompt, err := a.Prompt.FormatPrompt(fullInputs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stream := streamingFunc(ctx, a.CallbacksHandler)
+
+	result, err := a.LLM.GenerateContent(ctx, chatMessagesToContent(prompt.Messages()),
+		llms.WithTools(tools.ToLLMTools(a.Tools)), llms.WithStreamingFunc(stream))
+	if err != nil {
+		return nil, nil, err
+	}
+	addResponseUsage(ctx, result)
+
+	return a.ParseOutput(result)
+}
+
+// ParseOutput turns the tool calls of the first choice of the response into
+// actions, or into a finish if the model did not call any tool.
+func (a *ToolCallingAgent) ParseOutput(contentResp *llms.ContentResponse) (
+	[]schema.AgentAction, *schema.AgentFinish, error,
+) {
+	if len(contentResp.Choices) == 0 {
+		return nil, nil, fmt.Errorf("%w: no choices in response", ErrUnableToParseOutput)
+	}
+	choice := contentResp.Choices[0]
+
+	toolCalls := choice.ToolCalls
+	if len(toolCalls) == 0 && choice.FuncCall != nil {
+		toolCalls = []llms.ToolCall{{Type: "function", FunctionCall: choice.FuncCall}}
+	}
+	if len(toolCalls) == 0 {
+		return nil, &schema.AgentFinish{
+			ReturnValues: map[string]any{a.OutputKey: choice.Content},
+			Log:          choice.Content,
+		}, nil
+	}
+
+	contentMsg := "\n"
+	if choice.Content != "" {
+		contentMsg = fmt.Sprintf("responded: %s\n", choice.Content)
+	}
+
+	actions := make([]schema.AgentAction, 0, len(toolCalls))
+	for _, toolCall := range toolCalls {
+		if toolCall.FunctionCall == nil {
+			return nil, nil, fmt.Errorf("%w: tool call %q has no function", ErrUnableToParseOutput, toolCall.ID)
+		}
+		toolInput, err := toolCallInput(findTool(a.Tools, toolCall.FunctionCall.Name), toolCall.FunctionCall)
+		if err != nil {
+			return nil, nil, err
+		}
+		actions = append(actions, schema.AgentAction{
+			Tool:      toolCall.FunctionCall.Name,
+			ToolInput: toolInput,
+			Log: fmt.Sprintf("Invoking: %s with %s \n %s \n",
+				toolCall.FunctionCall.Name, toolCall.FunctionCall.Arguments, contentMsg),
+			ToolID: toolCall.ID,
+		})
+	}
+	return actions, nil, nil
+}
+
+// FinalAnswer asks the LLM for a final answer based on the tool calls made so
+// far, without offering it any tools.
+func (a *ToolCallingAgent) FinalAnswer(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) (*schema.AgentFinish, error) {
+	fullInputs := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		fullInputs[key] = value
+	}
+	fullInputs[agentScratchpad] = a.constructScratchPad(intermediateSteps)
+
+	return generateMessagesFinalAnswer(ctx, a.LLM, a.Prompt, fullInputs, a.OutputKey,
+		streamingFunc(ctx, a.CallbacksHandler))
+}
+
+// GetInputKeys returns the input keys of the agent, without the scratchpad.
+func (a *ToolCallingAgent) GetInputKeys() []string {
+	chainInputs := a.Prompt.GetInputVariables()
+
+	agentInput := make([]string, 0, len(chainInputs))
+	for _, v := range chainInputs {
+		if v == agentScratchpad {
+			continue
+		}
+		agentInput = append(agentInput, v)
+	}
+
+	return agentInput
+}
+
+// GetOutputKeys returns the output keys of the agent.
+func (a *ToolCallingAgent) GetOutputKeys() []string {
+	return []string{a.OutputKey}
+}
+
+// GetTools returns the tools of the agent.
+func (a *ToolCallingAgent) GetTools() []tools.Tool {
+	return a.Tools
+}
+
+// constructScratchPad turns every step into an AI message holding its tool
+// call followed by a tool message holding the observation. Calls made in
+// parallel are still sent as separate pairs of messages, as not every provider
+// accepts several tool calls in one message. Steps without a tool call, such as
+// parser errors, are sent as human messages.
+func (a *ToolCallingAgent) constructScratchPad(steps []schema.AgentStep) []llms.ChatMessage {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	messages := make([]llms.ChatMessage, 0, 2*len(steps))
+	for _, step := range steps {
+		if step.Action.ToolID == "" {
+			messages = append(messages, llms.HumanChatMessage{Content: step.Observation})
+			continue
+		}
+		messages = append(messages,
+			llms.AIChatMessage{
+				ToolCalls: []llms.ToolCall{{
+					ID:   step.Action.ToolID,
+					Type: "function",
+					FunctionCall: &llms.FunctionCall{
+						Name:      step.Action.Tool,
+						Arguments: tools.ArgumentsFromInput(findTool(a.Tools, step.Action.Tool), step.Action.ToolInput),
+					},
+				}},
+			},
+			llms.ToolChatMessage{
+				ID:      step.Action.ToolID,
+				Name:    step.Action.Tool,
+				Content: step.Observation,
+			},
+		)
+	}
+
+	return messages
+}
+
+// findTool returns the tool with the given name, ignoring case, or nil.
+func findTool(ts []tools.Tool, name string) tools.Tool { //nolint:ireturn
+	for _, t := range ts {
+		if strings.EqualFold(t.Name(), name) {
+			return t
+		}
+	}
+	return nil
+}
+
+// toolCallInput returns the input for the tool of a call, as described for
+// tools.InputFromArguments.
+func toolCallInput(tool tools.Tool, call *llms.FunctionCall) (string, error) {
+	if strings.TrimSpace(call.Arguments) == "" {
+		return "", nil
+	}
+	input, err := tools.InputFromArguments(tool, call.Arguments)
+	if err != nil {
+		return "", fmt.Errorf("%w: arguments of %s: %w", ErrUnableToParseOutput, call.Name, err)
+	}
+	return input, nil
+}
+
+// chatMessagesToContent converts formatted prompt messages into the message
+// contents sent to the LLM, keeping tool calls and tool results as their own
+// parts.
+func chatMessagesToContent(messages []llms.ChatMessage) []llms.MessageContent {
+	mcList := make([]llms.MessageContent, 0, len(messages))
+	for _, msg := range messages {
+		role := msg.GetType()
+
+		switch p := msg.(type) {
+		case llms.ToolChatMessage:
+			mcList = append(mcList, llms.MessageContent{
+				Role: role,
+				Parts: []llms.ContentPart{llms.ToolCallResponse{
+					ToolCallID: p.ID,
+					Name:       p.Name,
+					Content:    p.Content,
+				}},
+			})
+		case llms.AIChatMessage:
+			var parts []llms.ContentPart
+			if p.Content != "" {
+				parts = append(parts, llms.TextContent{Text: p.Content})
+			}
+			for _, toolCall := range p.ToolCalls {
+				parts = append(parts, toolCall)
+			}
+			mcList = append(mcList, llms.MessageContent{Role: role, Parts: parts})
+		default:
+			mcList = append(mcList, llms.MessageContent{
+				Role:  role,
+				Parts: []llms.ContentPart{llms.TextContent{Text: msg.GetContent()}},
+			})
+		}
+	}
+	return mcList
+}
@@ -0,0 +1,158 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// toolCallingLLM returns its responses in order and records the messages and
+// options it was called with.
+type toolCallingLLM struct {
+	responses []*llms.ContentResponse
+	calls     [][]llms.MessageContent
+	options   []llms.CallOptions
+}
+
+func (l *toolCallingLLM) GenerateContent(
+	_ context.Context,
+	messages []llms.MessageContent,
+	options ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	l.calls = append(l.calls, messages)
+	resp := l.responses[0]
+	l.responses = l.responses[1:]
+
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	l.options = append(l.options, opts)
+	if opts.StreamingFunc != nil && resp.Choices[0].Content != "" {
+		if err := opts.StreamingFunc(context.Background(), []byte(resp.Choices[0].Content)); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func (l *toolCallingLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+type upperTool struct{}
+
+func (upperTool) Name() string        { return "upper" }
+func (upperTool) Description() string { return "Upper-cases the input." }
+func (upperTool) Call(_ context.Context, input string) (string, error) {
+	return strings.ToUpper(input), nil
+}
+
+func toolCall(id, name, arguments string) llms.ToolCall {
+	return llms.ToolCall{
+		ID:           id,
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: name, Arguments: arguments},
+	}
+}
+
+func TestToolCallingAgentParseOutput(t *testing.T) {
+	t.Parallel()
+
+	a := NewToolCallingAgent(&toolCallingLLM{}, []tools.Tool{upperTool{}})
+
+	actions, finish, err := a.ParseOutput(&llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		ToolCalls: []llms.ToolCall{
+			toolCall("call_1", "upper", `{"input": "a"}`),
+			toolCall("call_2", "upper", `{"text": "b"}`),
+		},
+	}}})
+	require.NoError(t, err)
+	assert.Nil(t, finish)
+	require.Len(t, actions, 2)
+	assert.Equal(t, "a", actions[0].ToolInput)
+	assert.Equal(t, "call_1", actions[0].ToolID)
+	assert.Equal(t, `{"text": "b"}`, actions[1].ToolInput)
+	assert.Equal(t, "call_2", actions[1].ToolID)
+
+	actions, finish, err = a.ParseOutput(&llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		Content: "done",
+	}}})
+	require.NoError(t, err)
+	assert.Empty(t, actions)
+	assert.Equal(t, map[string]any{"output": "done"}, finish.ReturnValues)
+
+	_, _, err = a.ParseOutput(&llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		ToolCalls: []llms.ToolCall{toolCall("call_1", "upper", `{`)},
+	}}})
+	require.ErrorIs(t, err, ErrUnableToParseOutput)
+}
+
+func TestToolCallingAgentScratchpad(t *testing.T) {
+	t.Parallel()
+
+	llm := &toolCallingLLM{responses: []*llms.ContentResponse{
+		{Choices: []*llms.ContentChoice{{ToolCalls: []llms.ToolCall{
+			toolCall("call_1", "upper", `{"input": "a"}`),
+			toolCall("call_2", "upper", `{"input": "b"}`),
+		}}}},
+		{Choices: []*llms.ContentChoice{{Content: "A and B"}}},
+	}}
+	executor := NewExecutor(NewToolCallingAgent(llm, []tools.Tool{upperTool{}}))
+
+	output, err := chains.Run(context.Background(), executor, "upper-case a and b")
+	require.NoError(t, err)
+	assert.Equal(t, "A and B", output)
+
+	require.Len(t, llm.calls, 2)
+	assert.Equal(t, []llms.MessageContent{
+		{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{toolCall("call_1", "upper", `{"input":"a"}`)}},
+		{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{
+			llms.ToolCallResponse{ToolCallID: "call_1", Name: "upper", Content: "A"},
+		}},
+		{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{toolCall("call_2", "upper", `{"input":"b"}`)}},
+		{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{
+			llms.ToolCallResponse{ToolCallID: "call_2", Name: "upper", Content: "B"},
+		}},
+	}, llm.calls[1][2:])
+}
+
+func TestToolCallingAgentStructuredTool(t *testing.T) {
+	t.Parallel()
+
+	type repeatArgs struct {
+		Text  string `json:"text"`
+		Times int    `json:"times"`
+	}
+	repeat, err := tools.NewFunc("repeat", "Repeats a text.", func(_ context.Context, args repeatArgs) (string, error) {
+		return strings.Repeat(args.Text, args.Times), nil
+	})
+	require.NoError(t, err)
+
+	llm := &toolCallingLLM{responses: []*llms.ContentResponse{
+		{Choices: []*llms.ContentChoice{{ToolCalls: []llms.ToolCall{
+			toolCall("call_1", "repeat", `{"text": "ab"}`),
+		}}}},
+		{Choices: []*llms.ContentChoice{{ToolCalls: []llms.ToolCall{
+			toolCall("call_2", "repeat", `{"text": "ab", "times": 2}`),
+		}}}},
+		{Choices: []*llms.ContentChoice{{Content: "abab"}}},
+	}}
+	executor := NewExecutor(NewToolCallingAgent(llm, []tools.Tool{repeat}), WithReturnIntermediateSteps())
+
+	outputs, err := chains.Call(context.Background(), executor, map[string]any{"input": "repeat ab twice"})
+	require.NoError(t, err)
+	steps, ok := outputs[_intermediateStepsOutputKey].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 2)
+	assert.Contains(t, steps[0].Observation, "$.times is required")
+	assert.Equal(t, `{"text": "ab", "times": 2}`, steps[1].Action.ToolInput)
+	assert.Equal(t, "abab", steps[1].Observation)
+}
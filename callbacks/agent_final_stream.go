@@ -10,6 +10,10 @@ import (
 //nolint:all
 var DefaultKeywords = []string{"Final Answer:", "Final:", "AI:"}
 
+// AgentFinalStreamHandler streams the final answer of an agent by looking for
+// the keywords that precede it in the chunks streamed by the LLM. It does not
+// work with tool-calling models, which do not write such keywords; use the
+// Stream method of agents.Executor instead.
 type AgentFinalStreamHandler struct {
 	SimpleHandler
 	egress          chan []byte
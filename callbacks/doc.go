@@ -1,4 +1,8 @@
 // Package callbacks includes a standard interface for hooking into various
 // stages of your LLM application. The package contains an implementation of
 // this interface that prints to the standard output.
+//
+// UsageTracker adds up the token usage and cost of LLM calls, in total and per
+// usage scope: the chains, agent runs and graph nodes the calls were made in,
+// as well as the scopes set with WithUsageScope.
 package callbacks
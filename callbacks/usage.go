@@ -0,0 +1,135 @@
+package callbacks
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+type usageScopesContextKey struct{}
+
+// WithUsageScope returns a context in which the usage of LLM calls is also
+// accounted to the named scope, nested in the scopes of ctx. Chains called
+// with chains.Call get a scope named after their type, graph nodes a scope
+// named after the node. Applications can add their own, such as the feature
+// making the calls.
+func WithUsageScope(ctx context.Context, name string) context.Context {
+	scopes := UsageScopes(ctx)
+	return context.WithValue(ctx, usageScopesContextKey{}, append(scopes[:len(scopes):len(scopes)], name))
+}
+
+// UsageScopes returns the usage scopes of ctx, from the outermost to the
+// innermost.
+func UsageScopes(ctx context.Context) []string {
+	scopes, _ := ctx.Value(usageScopesContextKey{}).([]string)
+	return scopes
+}
+
+// UsageReport is the usage of the LLM calls of a scope.
+type UsageReport struct {
+	// Calls is the number of LLM calls.
+	Calls int
+	// Usage is the sum of the usage of the calls.
+	Usage llms.Usage
+	// Cost is the cost in dollars of the calls with a priced model.
+	Cost float64
+	// UnpricedCalls is the number of calls whose model has no price, and
+	// which are missing from Cost.
+	UnpricedCalls int
+}
+
+func (r UsageReport) add(usage llms.Usage, cost float64, priced bool) UsageReport {
+	r.Calls++
+	r.Usage = r.Usage.Add(usage)
+	r.Cost += cost
+	if !priced {
+		r.UnpricedCalls++
+	}
+	return r
+}
+
+// UsageTracker is a handler rolling the usage and cost of LLM calls up per
+// usage scope. A call made in the scopes "Executor" and "LLMChain" counts for
+// the total, for "Executor" and for "Executor/LLMChain".
+//
+// The tracker must be set as the callbacks handler of the LLMs, for instance
+// combined with other handlers in a CombiningHandler. It is safe for
+// concurrent use.
+type UsageTracker struct {
+	SimpleHandler
+
+	// Prices is the price table used to compute the cost of the calls.
+	Prices llms.PriceTable
+
+	mu     sync.Mutex
+	total  UsageReport
+	scopes map[string]UsageReport
+}
+
+var _ Handler = &UsageTracker{}
+
+// NewUsageTracker creates a usage tracker with a price table, which can be
+// llms.DefaultPrices. Without prices the tracker only counts tokens.
+func NewUsageTracker(prices llms.PriceTable) *UsageTracker {
+	return &UsageTracker{
+		Prices: prices,
+		scopes: make(map[string]UsageReport),
+	}
+}
+
+// HandleLLMGenerateContentEnd accounts the usage of a response to its scopes.
+func (t *UsageTracker) HandleLLMGenerateContentEnd(ctx context.Context, res *llms.ContentResponse) {
+	if res == nil {
+		return
+	}
+	cost, priced := t.Prices.Cost(res.Model, res.Usage)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.scopes == nil {
+		t.scopes = make(map[string]UsageReport)
+	}
+	t.total = t.total.add(res.Usage, cost, priced)
+	scopes := UsageScopes(ctx)
+	for i := range scopes {
+		path := strings.Join(scopes[:i+1], "/")
+		t.scopes[path] = t.scopes[path].add(res.Usage, cost, priced)
+	}
+}
+
+// Total returns the usage of all the calls.
+func (t *UsageTracker) Total() UsageReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total
+}
+
+// Scope returns the usage of the calls made in a scope, given as the names of
+// the nested scopes joined by "/".
+func (t *UsageTracker) Scope(path string) UsageReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.scopes[path]
+}
+
+// Scopes returns the usage of all the scopes by path.
+func (t *UsageTracker) Scopes() map[string]UsageReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	scopes := make(map[string]UsageReport, len(t.scopes))
+	for path, report := range t.scopes {
+		scopes[path] = report
+	}
+	return scopes
+}
+
+// Reset clears the usage tracked so far.
+func (t *UsageTracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total = UsageReport{}
+	t.scopes = make(map[string]UsageReport)
+}
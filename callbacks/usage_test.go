@@ -0,0 +1,69 @@
+package callbacks
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestUsageTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewUsageTracker(llms.PriceTable{"model": {Prompt: 1, Completion: 2}})
+	ctx := WithUsageScope(context.Background(), "feature")
+	agentCtx := WithUsageScope(ctx, "Executor")
+
+	var wg sync.WaitGroup
+	for _, c := range []context.Context{ctx, agentCtx, agentCtx} {
+		wg.Add(1)
+		go func(ctx context.Context) {
+			defer wg.Done()
+			tracker.HandleLLMGenerateContentEnd(ctx, &llms.ContentResponse{
+				Usage: llms.NewUsage(1_000_000, 1_000_000),
+				Model: "model",
+			})
+		}(c)
+	}
+	wg.Wait()
+	tracker.HandleLLMGenerateContentEnd(context.Background(), &llms.ContentResponse{
+		Usage: llms.NewUsage(10, 10),
+		Model: "unknown",
+	})
+
+	total := tracker.Total()
+	assert.Equal(t, 4, total.Calls)
+	assert.Equal(t, 1, total.UnpricedCalls)
+	assert.Equal(t, 6_000_020, total.Usage.TotalTokens)
+	assert.InDelta(t, 9.0, total.Cost, 1e-9)
+
+	feature := tracker.Scope("feature")
+	assert.Equal(t, 3, feature.Calls)
+	assert.InDelta(t, 9.0, feature.Cost, 1e-9)
+
+	agent := tracker.Scope("feature/Executor")
+	assert.Equal(t, 2, agent.Calls)
+	assert.Equal(t, 2_000_000, agent.Usage.PromptTokens)
+	assert.InDelta(t, 6.0, agent.Cost, 1e-9)
+
+	require.Len(t, tracker.Scopes(), 2)
+	tracker.Reset()
+	assert.Equal(t, UsageReport{}, tracker.Total())
+	assert.Empty(t, tracker.Scopes())
+}
+
+func TestWithUsageScope(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithUsageScope(context.Background(), "a")
+	b := WithUsageScope(ctx, "b")
+	c := WithUsageScope(ctx, "c")
+
+	assert.Equal(t, []string{"a"}, UsageScopes(ctx))
+	assert.Equal(t, []string{"a", "b"}, UsageScopes(b))
+	assert.Equal(t, []string{"a", "c"}, UsageScopes(c))
+	assert.Empty(t, UsageScopes(context.Background()))
+}
@@ -3,6 +3,7 @@ package chains
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"sync"
 
 	"github.com/tmc/langchaingo/callbacks"
@@ -26,8 +27,11 @@ type Chain interface {
 	GetOutputKeys() []string
 }
 
-// Call is the standard function used for executing chains.
+// Call is the standard function used for executing chains. The chain runs in
+// a usage scope named after its type, see callbacks.WithUsageScope.
 func Call(ctx context.Context, c Chain, inputValues map[string]any, options ...ChainCallOption) (map[string]any, error) { // nolint: lll
+	ctx = callbacks.WithUsageScope(ctx, chainName(c))
+
 	fullValues := make(map[string]any, 0)
 	for key, value := range inputValues {
 		fullValues[key] = value
@@ -66,6 +70,15 @@ func Call(ctx context.Context, c Chain, inputValues map[string]any, options ...C
 	return outputValues, nil
 }
 
+// chainName returns the name of the type of a chain, such as "LLMChain".
+func chainName(c Chain) string {
+	t := reflect.TypeOf(c)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
 func callChain(
 	ctx context.Context,
 	c Chain,
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/prompts"
 )
@@ -113,3 +114,30 @@ func TestApplyWithCanceledContext(t *testing.T) {
 		t.Fatal("expected context canceled error, got:", applyErr)
 	}
 }
+
+// scopeRecordingModel records the usage scopes it is called in.
+type scopeRecordingModel struct {
+	testLanguageModel
+	scopes []string
+}
+
+func (l *scopeRecordingModel) GenerateContent(ctx context.Context, mc []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	l.scopes = callbacks.UsageScopes(ctx)
+	return l.testLanguageModel.GenerateContent(ctx, mc, options...)
+}
+
+func TestCallUsageScopes(t *testing.T) {
+	t.Parallel()
+
+	llm := &scopeRecordingModel{}
+	c, err := NewSequentialChain(
+		[]Chain{NewLLMChain(llm, prompts.NewPromptTemplate("{{.input}}", []string{"input"}))},
+		[]string{"input"}, []string{"text"},
+	)
+	require.NoError(t, err)
+
+	ctx := callbacks.WithUsageScope(context.Background(), "feature")
+	_, err = Call(ctx, c, map[string]any{"input": "hello"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"feature", "SequentialChain", "LLMChain"}, llm.scopes)
+}
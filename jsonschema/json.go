@@ -26,7 +26,8 @@ type Definition struct {
 	// Description is the description of the schema.
 	Description string `json:"description,omitempty"`
 	// Enum is used to restrict a value to a fixed set of values. It must be an array with at least
-	// one element, where each element is unique. You will probably only use this with strings.
+	// one element, where each element is unique. The elements of enums of other types than
+	// strings are JSON values, such as "1" for the number 1.
 	Enum []string `json:"enum,omitempty"`
 	// Properties describes the properties of an object, if the schema type is Object.
 	Properties map[string]Definition `json:"properties"`
@@ -0,0 +1,132 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrUnsupportedType is returned by GenerateSchemaForType for Go types that
+// have no JSON schema, such as channels, functions and recursive types.
+var ErrUnsupportedType = errors.New("unsupported type for JSON schema")
+
+// GenerateSchemaForType returns the schema of the JSON encoding of the type of
+// v. Struct fields are named after their json tag and are required unless the
+// tag has the omitempty option or the field has a `required:"false"` tag. The
+// `description` tag sets the description of a field and the `enum` tag a comma
+// separated list of its allowed values.
+func GenerateSchemaForType(v any) (*Definition, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, fmt.Errorf("%w: nil", ErrUnsupportedType)
+	}
+	def, err := reflectType(t, make(map[reflect.Type]bool))
+	if err != nil {
+		return nil, err
+	}
+	return &def, nil
+}
+
+var _timeType = reflect.TypeOf(time.Time{})
+
+func reflectType(t reflect.Type, seen map[reflect.Type]bool) (Definition, error) { //nolint:cyclop
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == _timeType {
+		return Definition{Type: String}, nil
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.String:
+		return Definition{Type: String}, nil
+	case reflect.Bool:
+		return Definition{Type: Boolean}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Definition{Type: Integer}, nil
+	case reflect.Float32, reflect.Float64:
+		return Definition{Type: Number}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Byte slices are encoded as base64 strings.
+			return Definition{Type: String}, nil
+		}
+		items, err := reflectType(t.Elem(), seen)
+		if err != nil {
+			return Definition{}, err
+		}
+		return Definition{Type: Array, Items: &items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return Definition{}, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+		}
+		return Definition{Type: Object}, nil
+	case reflect.Interface:
+		return Definition{}, nil
+	case reflect.Struct:
+		if seen[t] {
+			return Definition{}, fmt.Errorf("%w: recursive type %s", ErrUnsupportedType, t)
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		def := Definition{Type: Object, Properties: make(map[string]Definition)}
+		if err := reflectFields(t, &def, seen); err != nil {
+			return Definition{}, err
+		}
+		return def, nil
+	default:
+		return Definition{}, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+	}
+}
+
+// reflectFields adds the exported fields of the struct type t to def. The
+// fields of embedded structs without a json tag are added as if they were
+// fields of t, like encoding/json does.
+func reflectFields(t reflect.Type, def *Definition, seen map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := reflectFields(ft, def, seen); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop, err := reflectType(field.Type, seen)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		prop.Description = field.Tag.Get("description")
+		if enum := field.Tag.Get("enum"); enum != "" {
+			prop.Enum = strings.Split(enum, ",")
+		}
+		def.Properties[name] = prop
+
+		if !strings.Contains(opts, "omitempty") && field.Tag.Get("required") != "false" {
+			def.Required = append(def.Required, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/jsonschema"
+)
+
+type weatherArgs struct {
+	Location string   `json:"location" description:"The city, e.g. Berlin"`
+	Unit     string   `json:"unit,omitempty" enum:"celsius,fahrenheit"`
+	Days     int      `json:"days" required:"false"`
+	Tags     []string `json:"tags,omitempty"`
+	Embedded
+	Ignored string `json:"-"`
+	private string //nolint:unused
+}
+
+type Embedded struct {
+	Verbose *bool `json:"verbose"`
+}
+
+type node struct {
+	Children []node `json:"children"`
+}
+
+func TestGenerateSchemaForType(t *testing.T) {
+	t.Parallel()
+
+	def, err := jsonschema.GenerateSchemaForType(&weatherArgs{})
+	require.NoError(t, err)
+	assert.Equal(t, &jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"location": {Type: jsonschema.String, Description: "The city, e.g. Berlin"},
+			"unit":     {Type: jsonschema.String, Enum: []string{"celsius", "fahrenheit"}},
+			"days":     {Type: jsonschema.Integer},
+			"tags":     {Type: jsonschema.Array, Items: &jsonschema.Definition{Type: jsonschema.String}},
+			"verbose":  {Type: jsonschema.Boolean},
+		},
+		Required: []string{"location", "verbose"},
+	}, def)
+
+	_, err = jsonschema.GenerateSchemaForType(node{})
+	require.ErrorIs(t, err, jsonschema.ErrUnsupportedType)
+	_, err = jsonschema.GenerateSchemaForType(struct{ C chan int }{})
+	require.ErrorIs(t, err, jsonschema.ErrUnsupportedType)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	def, err := jsonschema.GenerateSchemaForType(weatherArgs{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "valid", data: `{"location": "Berlin", "verbose": true, "days": 3, "tags": ["a"]}`},
+		{name: "missing", data: `{"verbose": true}`, wantErr: "$.location is required"},
+		{name: "type", data: `{"location": 1, "verbose": true}`, wantErr: "$.location must be of type string, got number"},
+		{name: "integer", data: `{"location": "", "verbose": true, "days": 1.5}`, wantErr: "$.days must be of type integer"},
+		{name: "enum", data: `{"location": "", "verbose": true, "unit": "kelvin"}`, wantErr: "$.unit must be one of"},
+		{name: "items", data: `{"location": "", "verbose": true, "tags": [1]}`, wantErr: "$.tags[0] must be of type string"},
+		{name: "not json", data: `{`, wantErr: "unexpected end of JSON input"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := jsonschema.ValidateJSON(*def, []byte(tc.data))
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, jsonschema.ErrInvalidValue)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
+func TestValidateEnum(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		def     jsonschema.Definition
+		value   string
+		wantErr bool
+	}{
+		{name: "string", def: jsonschema.Definition{Type: jsonschema.String, Enum: []string{"a", "1"}}, value: `"1"`},
+		{name: "string number", def: jsonschema.Definition{Enum: []string{"a", "1"}}, value: `1`},
+		{name: "integer", def: jsonschema.Definition{Type: jsonschema.Integer, Enum: []string{"1", "3"}}, value: `3`},
+		{name: "number", def: jsonschema.Definition{Type: jsonschema.Number, Enum: []string{"0.5"}}, value: `0.50`},
+		{name: "boolean", def: jsonschema.Definition{Type: jsonschema.Boolean, Enum: []string{"true"}}, value: `true`},
+		{
+			name: "integer not in enum", def: jsonschema.Definition{Type: jsonschema.Integer, Enum: []string{"1", "3"}},
+			value: `2`, wantErr: true,
+		},
+		{
+			name: "string of integer", def: jsonschema.Definition{Type: jsonschema.Integer, Enum: []string{"1"}},
+			value: `"1"`, wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := jsonschema.ValidateJSON(tc.def, []byte(tc.value))
+			if tc.wantErr {
+				require.ErrorIs(t, err, jsonschema.ErrInvalidValue)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// ErrInvalidValue is returned by Validate for values that do not match the
+// schema.
+var ErrInvalidValue = errors.New("value does not match schema")
+
+// Validate checks a decoded JSON value, as returned by json.Unmarshal into an
+// any, against the schema. It checks types, required properties, enums and the
+// items of arrays. Properties missing from the schema are allowed.
+func Validate(schema Definition, value any) error {
+	return validate(schema, value, "$")
+}
+
+// ValidateJSON decodes data and checks it against the schema.
+func ValidateJSON(schema Definition, data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+	return Validate(schema, value)
+}
+
+func validate(schema Definition, value any, path string) error { //nolint:cyclop
+	if len(schema.Enum) > 0 && !enumContains(schema.Enum, value) {
+		return fmt.Errorf("%w: %s must be one of %v", ErrInvalidValue, path, schema.Enum)
+	}
+
+	switch schema.Type {
+	case Object:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return typeError(schema.Type, value, path)
+		}
+		for _, name := range schema.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%w: %s.%s is required", ErrInvalidValue, path, name)
+			}
+		}
+		for name, prop := range schema.Properties {
+			v, ok := obj[name]
+			if !ok {
+				continue
+			}
+			if err := validate(prop, v, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case Array:
+		arr, ok := value.([]any)
+		if !ok {
+			return typeError(schema.Type, value, path)
+		}
+		if schema.Items == nil {
+			return nil
+		}
+		for i, v := range arr {
+			if err := validate(*schema.Items, v, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case String:
+		if _, ok := value.(string); !ok {
+			return typeError(schema.Type, value, path)
+		}
+	case Number:
+		if _, ok := value.(float64); !ok {
+			return typeError(schema.Type, value, path)
+		}
+	case Integer:
+		if f, ok := value.(float64); !ok || f != math.Trunc(f) {
+			return typeError(schema.Type, value, path)
+		}
+	case Boolean:
+		if _, ok := value.(bool); !ok {
+			return typeError(schema.Type, value, path)
+		}
+	case Null:
+		if value != nil {
+			return typeError(schema.Type, value, path)
+		}
+	}
+	return nil
+}
+
+func typeError(want DataType, value any, path string) error {
+	got := "null"
+	switch value.(type) {
+	case map[string]any:
+		got = string(Object)
+	case []any:
+		got = string(Array)
+	case string:
+		got = string(String)
+	case float64:
+		got = string(Number)
+	case bool:
+		got = string(Boolean)
+	}
+	return fmt.Errorf("%w: %s must be of type %s, got %s", ErrInvalidValue, path, want, got)
+}
+
+// enumContains reports whether an enum holds a value. Strings are compared to
+// the entries as is, and other values to the entries decoded as JSON, so that
+// the entry "1" holds the number 1.
+func enumContains(enum []string, value any) bool {
+	if s, ok := value.(string); ok {
+		return slices.Contains(enum, s)
+	}
+	for _, entry := range enum {
+		var v any
+		if json.Unmarshal([]byte(entry), &v) == nil && reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -84,10 +84,21 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		opt(opts)
 	}
 
+	var resp *llms.ContentResponse
+	var err error
 	if o.client.UseLegacyTextCompletionsAPI {
-		return generateCompletionsContent(ctx, o, messages, opts)
+		resp, err = generateCompletionsContent(ctx, o, messages, opts)
+	} else {
+		resp, err = generateMessagesContent(ctx, o, messages, opts)
 	}
-	return generateMessagesContent(ctx, o, messages, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
+	}
+	return resp, nil
 }
 
 func generateCompletionsContent(ctx context.Context, o *LLM, messages []llms.MessageContent, opts *llms.CallOptions) (*llms.ContentResponse, error) {
@@ -135,6 +146,13 @@ func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.Messag
 	}
 
 	tools := toolsToTools(opts.Tools)
+	// Anthropic has no response schemas, the model is forced to call a tool
+	// taking the response as arguments instead.
+	var toolChoice *anthropicclient.ToolChoice
+	if opts.ResponseSchema != nil {
+		tools = append(tools, toolsToTools([]llms.Tool{llms.StructuredOutputTool(*opts.ResponseSchema)})...)
+		toolChoice = &anthropicclient.ToolChoice{Type: "tool", Name: llms.StructuredOutputToolName}
+	}
 	result, err := o.client.CreateMessage(ctx, &anthropicclient.MessageRequest{
 		Model:         opts.Model,
 		Messages:      chatMessages,
@@ -144,7 +162,10 @@ func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.Messag
 		Temperature:   opts.Temperature,
 		TopP:          opts.TopP,
 		Tools:         tools,
+		ToolChoice:    toolChoice,
 		StreamingFunc: opts.StreamingFunc,
+
+		StreamingChunkFunc: opts.StreamingChunkFunc,
 	})
 	if err != nil {
 		if o.CallbacksHandler != nil {
@@ -199,8 +220,14 @@ func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.Messag
 		}
 	}
 
+	if opts.ResponseSchema != nil {
+		llms.SetStructuredOutputContent(choices)
+	}
+
 	resp := &llms.ContentResponse{
 		Choices: choices,
+		Usage:   result.TokenUsage(),
+		Model:   result.Model,
 	}
 	return resp, nil
 }
@@ -0,0 +1,49 @@
+package anthropic
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestGenerateContentUsage(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/messages", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "msg_1",
+			"type": "message",
+			"role": "assistant",
+			"model": "claude-3-haiku-20240307",
+			"content": [{"type": "text", "text": "yes"}],
+			"stop_reason": "end_turn",
+			"usage": {"input_tokens": 10, "output_tokens": 3, "cache_read_input_tokens": 4}
+		}`))
+	}))
+	defer server.Close()
+
+	llm, err := New(WithToken("test"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	tracker := callbacks.NewUsageTracker(nil)
+	llm.CallbacksHandler = tracker
+
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Brazil is a country?"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "yes", resp.Choices[0].Content)
+
+	want := llms.NewUsage(14, 3)
+	want.CachedTokens = 4
+	total := tracker.Total()
+	assert.Equal(t, 1, total.Calls)
+	assert.Equal(t, want, total.Usage)
+}
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 const (
@@ -131,10 +133,12 @@ type MessageRequest struct {
 	MaxTokens   int           `json:"max_tokens,omitempty"`
 	TopP        float64       `json:"top_p,omitempty"`
 	Tools       []Tool        `json:"tools,omitempty"`
+	ToolChoice  *ToolChoice   `json:"tool_choice,omitempty"`
 	StopWords   []string      `json:"stop_sequences,omitempty"`
 	Stream      bool          `json:"stream,omitempty"`
 
-	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+	StreamingFunc      func(ctx context.Context, chunk []byte) error           `json:"-"`
+	StreamingChunkFunc func(ctx context.Context, chunk llms.StreamChunk) error `json:"-"`
 }
 
 // CreateMessage creates message for the messages api.
@@ -148,8 +152,11 @@ func (c *Client) CreateMessage(ctx context.Context, r *MessageRequest) (*Message
 		StopWords:     r.StopWords,
 		TopP:          r.TopP,
 		Tools:         r.Tools,
+		ToolChoice:    r.ToolChoice,
 		Stream:        r.Stream,
 		StreamingFunc: r.StreamingFunc,
+
+		StreamingChunkFunc: r.StreamingChunkFunc,
 	})
 	if err != nil {
 		return nil, err
@@ -202,7 +209,20 @@ func (c *Client) decodeError(resp *http.Response) error {
 
 	var errResp errorMessage
 	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
-		return errors.New(msg) // nolint:goerr113
+		return llms.NewError(resp.StatusCode, resp.Header, errors.New(msg)) // nolint:goerr113
+	}
+	if errResp.Error.Type != "" {
+		msg = fmt.Sprintf("%s: %s", msg, errResp.Error.Type)
+	}
+	return llms.NewError(resp.StatusCode, resp.Header, fmt.Errorf("%s: %s", msg, errResp.Error.Message)) // nolint:goerr113
+}
+
+// decodeStreamError returns the error of an error event sent in the middle of
+// a stream, such as an overloaded_error.
+func decodeStreamError(event map[string]interface{}) error {
+	var errResp errorMessage
+	if data, err := json.Marshal(event); err == nil {
+		_ = json.Unmarshal(data, &errResp)
 	}
-	return fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+	return llms.NewError(0, nil, fmt.Errorf("stream error: %s: %s", errResp.Error.Type, errResp.Error.Message)) // nolint:goerr113
 }
@@ -11,6 +11,8 @@ import (
 	"log"
 	"net/http"
 	"strings"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 var (
@@ -23,6 +25,7 @@ var (
 	ErrInvalidDeltaTextField   = fmt.Errorf("invalid delta text field type")
 	ErrContentIndexOutOfRange  = fmt.Errorf("content index out of range")
 	ErrFailedCastToTextContent = fmt.Errorf("failed to cast content to TextContent")
+	ErrFailedCastToToolUse     = fmt.Errorf("failed to cast content to ToolUseContent")
 	ErrInvalidFieldType        = fmt.Errorf("invalid field type")
 )
 
@@ -40,9 +43,11 @@ type messagePayload struct {
 	Stream      bool          `json:"stream,omitempty"`
 	Temperature float64       `json:"temperature"`
 	Tools       []Tool        `json:"tools,omitempty"`
+	ToolChoice  *ToolChoice   `json:"tool_choice,omitempty"`
 	TopP        float64       `json:"top_p,omitempty"`
 
-	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+	StreamingFunc      func(ctx context.Context, chunk []byte) error           `json:"-"`
+	StreamingChunkFunc func(ctx context.Context, chunk llms.StreamChunk) error `json:"-"`
 }
 
 // Tool used for the request message payload.
@@ -52,6 +57,13 @@ type Tool struct {
 	InputSchema any    `json:"input_schema,omitempty"`
 }
 
+// ToolChoice tells how the model uses the tools: "auto", "any" tool, or the
+// "tool" of the given name.
+type ToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
 // Content can be TextContent or ToolUseContent depending on the type.
 type Content interface {
 	GetType() string
@@ -71,6 +83,10 @@ type ToolUseContent struct {
 	ID    string                 `json:"id"`
 	Name  string                 `json:"name"`
 	Input map[string]interface{} `json:"input"`
+
+	// partialInput holds the fragments of the JSON input of a streamed tool
+	// use, decoded into Input at the end of the block.
+	partialInput string
 }
 
 func (tuc ToolUseContent) GetType() string {
@@ -96,11 +112,24 @@ type MessageResponsePayload struct {
 	StopSequence string    `json:"stop_sequence"`
 	Type         string    `json:"type"`
 	Usage        struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
+		InputTokens              int `json:"input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
 	} `json:"usage"`
 }
 
+// TokenUsage returns the usage of the message. The input tokens reported by
+// Anthropic do not include the tokens written to or read from the prompt
+// cache, which are added to the prompt tokens.
+func (m *MessageResponsePayload) TokenUsage() llms.Usage {
+	cachedTokens := m.Usage.CacheReadInputTokens
+	promptTokens := m.Usage.InputTokens + m.Usage.CacheCreationInputTokens + cachedTokens
+	usage := llms.NewUsage(promptTokens, m.Usage.OutputTokens)
+	usage.CachedTokens = cachedTokens
+	return usage
+}
+
 func (m *MessageResponsePayload) UnmarshalJSON(data []byte) error {
 	type Alias MessageResponsePayload
 	aux := &struct {
@@ -163,7 +192,7 @@ func (c *Client) setMessageDefaults(payload *messagePayload) {
 	default:
 		payload.Model = defaultModel
 	}
-	if payload.StreamingFunc != nil {
+	if payload.StreamingFunc != nil || payload.StreamingChunkFunc != nil {
 		payload.Stream = true
 	}
 }
@@ -186,7 +215,7 @@ func (c *Client) createMessage(ctx context.Context, payload *messagePayload) (*M
 		return nil, c.decodeError(resp)
 	}
 
-	if payload.StreamingFunc != nil {
+	if payload.Stream {
 		return parseStreamingMessageResponse(ctx, resp, payload)
 	}
 
@@ -257,17 +286,19 @@ func processStreamEvent(ctx context.Context, event map[string]interface{}, paylo
 	case "message_start":
 		return handleMessageStartEvent(event, response)
 	case "content_block_start":
-		return handleContentBlockStartEvent(event, response)
+		return handleContentBlockStartEvent(ctx, event, response, payload)
 	case "content_block_delta":
 		return handleContentBlockDeltaEvent(ctx, event, response, payload)
 	case "content_block_stop":
-		// Nothing to do here
+		return handleContentBlockStopEvent(event, response)
 	case "message_delta":
-		return handleMessageDeltaEvent(event, response)
+		return handleMessageDeltaEvent(ctx, event, response, payload)
 	case "message_stop":
 		eventChan <- MessageEvent{Response: &response, Err: nil}
 	case "ping":
 		// Nothing to do here
+	case "error":
+		return response, decodeStreamError(event)
 	default:
 		log.Printf("unknown event type: %s", eventType)
 	}
@@ -295,11 +326,17 @@ func handleMessageStartEvent(event map[string]interface{}, response MessageRespo
 	response.Role = getString(message, "role")
 	response.Type = getString(message, "type")
 	response.Usage.InputTokens = int(inputTokens)
+	if tokens, err := getFloat64(usage, "cache_creation_input_tokens"); err == nil {
+		response.Usage.CacheCreationInputTokens = int(tokens)
+	}
+	if tokens, err := getFloat64(usage, "cache_read_input_tokens"); err == nil {
+		response.Usage.CacheReadInputTokens = int(tokens)
+	}
 
 	return response, nil
 }
 
-func handleContentBlockStartEvent(event map[string]interface{}, response MessageResponsePayload) (MessageResponsePayload, error) {
+func handleContentBlockStartEvent(ctx context.Context, event map[string]interface{}, response MessageResponsePayload, payload *messagePayload) (MessageResponsePayload, error) {
 	indexValue, ok := event["index"].(float64)
 	if !ok {
 		return response, ErrInvalidIndexField
@@ -307,17 +344,31 @@ func handleContentBlockStartEvent(event map[string]interface{}, response Message
 	index := int(indexValue)
 
 	var eventType string
-	if cb, ok := event["content_block"].(map[string]any); ok {
-		typ, _ := cb["type"].(string)
+	cb, _ := event["content_block"].(map[string]any)
+	if typ, ok := cb["type"].(string); ok {
 		eventType = typ
 	}
+	if len(response.Content) > index {
+		return response, nil
+	}
 
-	if len(response.Content) <= index {
+	if eventType != "tool_use" {
 		response.Content = append(response.Content, &TextContent{
 			Type: eventType,
 		})
-	}
-	return response, nil
+		return response, nil
+	}
+	toolUse := &ToolUseContent{
+		Type: eventType,
+		ID:   getString(cb, "id"),
+		Name: getString(cb, "name"),
+	}
+	response.Content = append(response.Content, toolUse)
+	return response, sendChunk(ctx, payload, llms.StreamChunk{ToolCall: &llms.ToolCallChunk{
+		Index: index,
+		ID:    toolUse.ID,
+		Name:  toolUse.Name,
+	}})
 }
 
 func handleContentBlockDeltaEvent(ctx context.Context, event map[string]interface{}, response MessageResponsePayload, payload *messagePayload) (MessageResponsePayload, error) {
@@ -336,41 +387,76 @@ func handleContentBlockDeltaEvent(ctx context.Context, event map[string]interfac
 		return response, ErrInvalidDeltaTypeField
 	}
 
-	if deltaType == "text_delta" {
+	if len(response.Content) <= index {
+		return response, ErrContentIndexOutOfRange
+	}
+
+	switch deltaType {
+	case "text_delta":
 		text, ok := delta["text"].(string)
 		if !ok {
 			return response, ErrInvalidDeltaTextField
 		}
-		if len(response.Content) <= index {
-			return response, ErrContentIndexOutOfRange
-		}
 		textContent, ok := response.Content[index].(*TextContent)
 		if !ok {
 			return response, ErrFailedCastToTextContent
 		}
 		textContent.Text += text
-	}
-
-	if payload.StreamingFunc != nil {
-		text, ok := delta["text"].(string)
+		return response, sendChunk(ctx, payload, llms.StreamChunk{Text: text})
+	case "input_json_delta":
+		partialJSON := getString(delta, "partial_json")
+		toolUse, ok := response.Content[index].(*ToolUseContent)
 		if !ok {
-			return response, ErrInvalidDeltaTextField
-		}
-		err := payload.StreamingFunc(ctx, []byte(text))
-		if err != nil {
-			return response, fmt.Errorf("streaming func returned an error: %w", err)
+			return response, ErrFailedCastToToolUse
 		}
+		toolUse.partialInput += partialJSON
+		return response, sendChunk(ctx, payload, llms.StreamChunk{ToolCall: &llms.ToolCallChunk{
+			Index:     index,
+			Arguments: partialJSON,
+		}})
 	}
 	return response, nil
 }
 
-func handleMessageDeltaEvent(event map[string]interface{}, response MessageResponsePayload) (MessageResponsePayload, error) {
+func handleContentBlockStopEvent(event map[string]interface{}, response MessageResponsePayload) (MessageResponsePayload, error) {
+	indexValue, ok := event["index"].(float64)
+	if !ok {
+		return response, ErrInvalidIndexField
+	}
+	index := int(indexValue)
+	if len(response.Content) <= index {
+		return response, nil
+	}
+
+	toolUse, ok := response.Content[index].(*ToolUseContent)
+	if !ok || toolUse.partialInput == "" {
+		return response, nil
+	}
+	if err := json.Unmarshal([]byte(toolUse.partialInput), &toolUse.Input); err != nil {
+		return response, fmt.Errorf("parse tool use input: %w", err)
+	}
+	return response, nil
+}
+
+// sendChunk sends a chunk to the streaming functions of the payload.
+func sendChunk(ctx context.Context, payload *messagePayload, chunk llms.StreamChunk) error {
+	err := llms.SendStreamChunk(ctx, payload.StreamingFunc, payload.StreamingChunkFunc, chunk)
+	if err != nil {
+		return fmt.Errorf("streaming func returned an error: %w", err)
+	}
+	return nil
+}
+
+func handleMessageDeltaEvent(ctx context.Context, event map[string]interface{}, response MessageResponsePayload, payload *messagePayload) (MessageResponsePayload, error) {
 	delta, ok := event["delta"].(map[string]interface{})
 	if !ok {
 		return response, ErrInvalidDeltaField
 	}
 	if stopReason, ok := delta["stop_reason"].(string); ok {
 		response.StopReason = stopReason
+		if err := sendChunk(ctx, payload, llms.StreamChunk{StopReason: stopReason}); err != nil {
+			return response, err
+		}
 	}
 
 	usage, ok := event["usage"].(map[string]interface{})
@@ -380,7 +466,8 @@ func handleMessageDeltaEvent(event map[string]interface{}, response MessageRespo
 	if outputTokens, ok := usage["output_tokens"].(float64); ok {
 		response.Usage.OutputTokens = int(outputTokens)
 	}
-	return response, nil
+	tokenUsage := response.TokenUsage()
+	return response, sendChunk(ctx, payload, llms.StreamChunk{Usage: &tokenUsage})
 }
 
 func getString(m map[string]interface{}, key string) string {
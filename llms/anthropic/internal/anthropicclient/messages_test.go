@@ -0,0 +1,82 @@
+package anthropicclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestParseStreamingMessageResponse_Chunks(t *testing.T) {
+	t.Parallel()
+	mockBody := `event: message_start
+data: {"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude-3-5-sonnet","usage":{"input_tokens":10,"output_tokens":1}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Let me search."}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: content_block_start
+data: {"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"search","input":{}}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"q\":"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"go\"}"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":1}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"tool_use","stop_sequence":null},"usage":{"output_tokens":5}}
+
+event: message_stop
+data: {"type":"message_stop"}
+`
+	r := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(mockBody)),
+	}
+
+	var chunks []llms.StreamChunk
+	var text string
+	payload := &messagePayload{
+		Stream: true,
+		StreamingFunc: func(_ context.Context, chunk []byte) error {
+			text += string(chunk)
+			return nil
+		},
+		StreamingChunkFunc: func(_ context.Context, chunk llms.StreamChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		},
+	}
+	resp, err := parseStreamingMessageResponse(context.Background(), r, payload)
+	require.NoError(t, err)
+	require.Len(t, resp.Content, 2)
+	toolUse, ok := resp.Content[1].(*ToolUseContent)
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"q": "go"}, toolUse.Input)
+	assert.Equal(t, "tool_use", resp.StopReason)
+	assert.Equal(t, "Let me search.", text)
+
+	assert.Equal(t, []llms.StreamChunk{
+		{Text: "Let me search."},
+		{ToolCall: &llms.ToolCallChunk{Index: 1, ID: "toolu_1", Name: "search"}},
+		{ToolCall: &llms.ToolCallChunk{Index: 1, Arguments: `{"q":`}},
+		{ToolCall: &llms.ToolCallChunk{Index: 1, Arguments: `"go"}`}},
+		{StopReason: "tool_use"},
+		{Usage: &llms.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}},
+	}, chunks)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
 	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/jsonschema"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/bedrock/internal/bedrockclient"
 )
@@ -71,18 +72,20 @@ func (l *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		opt(&opts)
 	}
 
-	m, err := processMessages(messages)
+	m, err := processMessages(messages, opts.ResponseSchema)
 	if err != nil {
 		return nil, err
 	}
 
 	res, err := l.client.CreateCompletion(ctx, opts.Model, m, opts)
 	if err != nil {
+		err = providerError(err)
 		if l.CallbacksHandler != nil {
 			l.CallbacksHandler.HandleLLMError(ctx, err)
 		}
 		return nil, err
 	}
+	res.Model = opts.Model
 
 	if l.CallbacksHandler != nil {
 		l.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, res)
@@ -91,7 +94,10 @@ func (l *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	return res, nil
 }
 
-func processMessages(messages []llms.MessageContent) ([]bedrockclient.Message, error) {
+// processMessages converts the messages to the messages of the client. As
+// Bedrock has no native support for response schemas, the response schema, if
+// any, is given in the last text message.
+func processMessages(messages []llms.MessageContent, schema *jsonschema.Definition) ([]bedrockclient.Message, error) {
 	bedrockMsgs := make([]bedrockclient.Message, 0, len(messages))
 
 	for _, m := range messages {
@@ -115,7 +121,27 @@ func processMessages(messages []llms.MessageContent) ([]bedrockclient.Message, e
 			}
 		}
 	}
+	for i := len(bedrockMsgs) - 1; i >= 0; i-- {
+		if bedrockMsgs[i].Type == "text" {
+			var err error
+			bedrockMsgs[i].Content, err = llms.AppendResponseSchemaPrompt(bedrockMsgs[i].Content, schema)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
 	return bedrockMsgs, nil
 }
 
 var _ llms.Model = (*LLM)(nil)
+
+// providerError classifies the errors of the AWS SDK, which carry the HTTP
+// status code of the response.
+func providerError(err error) error {
+	var respErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &respErr) {
+		return llms.NewError(respErr.HTTPStatusCode(), nil, err)
+	}
+	return llms.NewError(0, nil, err)
+}
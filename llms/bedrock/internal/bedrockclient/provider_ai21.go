@@ -119,7 +119,9 @@ func createAi21Completion(ctx context.Context, client *bedrockruntime.Client, mo
 	}
 
 	choices := make([]*llms.ContentChoice, len(output.Completions))
+	outputTokens := 0
 	for i, completion := range output.Completions {
+		outputTokens += len(completion.Data.Tokens)
 		choices[i] = &llms.ContentChoice{
 			Content:    completion.Data.Text,
 			StopReason: completion.FinishReason.Reason,
@@ -131,5 +133,8 @@ func createAi21Completion(ctx context.Context, client *bedrockruntime.Client, mo
 		}
 	}
 
-	return &llms.ContentResponse{Choices: choices}, nil
+	return &llms.ContentResponse{
+		Choices: choices,
+		Usage:   llms.NewUsage(len(output.Prompt.Tokens), outputTokens),
+	}, nil
 }
@@ -101,8 +101,10 @@ func createAmazonCompletion(ctx context.Context,
 	}
 
 	contentChoices := make([]*llms.ContentChoice, len(output.Results))
+	outputTokens := 0
 
 	for i, result := range output.Results {
+		outputTokens += result.TokenCount
 		contentChoices[i] = &llms.ContentChoice{
 			Content:    result.OutputText,
 			StopReason: result.CompletionReason,
@@ -115,5 +117,6 @@ func createAmazonCompletion(ctx context.Context,
 
 	return &llms.ContentResponse{
 		Choices: contentChoices,
+		Usage:   llms.NewUsage(output.InputTextTokenCount, outputTokens),
 	}, nil
 }
@@ -148,7 +148,7 @@ func createAnthropicCompletion(ctx context.Context,
 		return nil, err
 	}
 
-	if options.StreamingFunc != nil {
+	if options.StreamingFunc != nil || options.StreamingChunkFunc != nil {
 		modelInput := &bedrockruntime.InvokeModelWithResponseStreamInput{
 			ModelId:     aws.String(modelID),
 			Accept:      aws.String("*/*"),
@@ -193,6 +193,7 @@ func createAnthropicCompletion(ctx context.Context,
 	}
 	return &llms.ContentResponse{
 		Choices: Contentchoices,
+		Usage:   llms.NewUsage(output.Usage.InputTokens, output.Usage.OutputTokens),
 	}, nil
 }
 
@@ -240,39 +241,73 @@ func parseStreamingCompletionResponse(ctx context.Context, client *bedrockruntim
 	}
 	defer stream.Close()
 
-	contentchoices := []*llms.ContentChoice{{GenerationInfo: map[string]interface{}{}}}
+	message := &anthropicStream{choice: &llms.ContentChoice{GenerationInfo: map[string]interface{}{}}}
 	for e := range stream.Events() {
 		if err = stream.Err(); err != nil {
 			return nil, err
 		}
 
 		if v, ok := e.(*types.ResponseStreamMemberChunk); ok {
-			var resp streamingCompletionResponseChunk
-			err := json.NewDecoder(bytes.NewReader(v.Value.Bytes)).Decode(&resp)
-			if err != nil {
+			if err := message.handleChunk(ctx, options, v.Value.Bytes); err != nil {
 				return nil, err
 			}
-
-			switch resp.Type {
-			case "message_start":
-				contentchoices[0].GenerationInfo["input_tokens"] = resp.Message.Usage.InputTokens
-			case "content_block_delta":
-				if err = options.StreamingFunc(ctx, []byte(resp.Delta.Text)); err != nil {
-					return nil, err
-				}
-				contentchoices[0].Content += resp.Delta.Text
-			case "message_delta":
-				contentchoices[0].StopReason = resp.Delta.StopReason
-				contentchoices[0].GenerationInfo["output_tokens"] = resp.Usage.OutputTokens
-			}
 		}
 	}
 
 	return &llms.ContentResponse{
-		Choices: contentchoices,
+		Choices: []*llms.ContentChoice{message.choice},
+		Usage:   llms.NewUsage(message.inputTokens, message.outputTokens),
 	}, nil
 }
 
+// anthropicStream accumulates the chunks of a streamed message.
+type anthropicStream struct {
+	choice                    *llms.ContentChoice
+	inputTokens, outputTokens int
+}
+
+// handleChunk adds a chunk of a streamed message to the stream and sends it
+// to the streaming functions of the options.
+func (s *anthropicStream) handleChunk(ctx context.Context, options llms.CallOptions, data []byte) error {
+	var resp streamingCompletionResponseChunk
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&resp); err != nil {
+		return err
+	}
+
+	switch resp.Type {
+	case "message_start":
+		s.inputTokens = resp.Message.Usage.InputTokens
+		s.choice.GenerationInfo["input_tokens"] = s.inputTokens
+	case "content_block_delta":
+		chunk := llms.StreamChunk{Text: resp.Delta.Text}
+		if err := llms.SendStreamChunk(ctx, options.StreamingFunc, options.StreamingChunkFunc, chunk); err != nil {
+			return err
+		}
+		s.choice.Content += resp.Delta.Text
+	case "message_delta":
+		s.choice.StopReason = resp.Delta.StopReason
+		s.outputTokens = resp.Usage.OutputTokens
+		s.choice.GenerationInfo["output_tokens"] = s.outputTokens
+		return streamMessageDelta(ctx, options, resp.Delta.StopReason, s.inputTokens, s.outputTokens)
+	}
+	return nil
+}
+
+// streamMessageDelta sends the stop reason and the usage of a streamed message
+// to the StreamingChunkFunc of the options, if any.
+func streamMessageDelta(ctx context.Context, options llms.CallOptions, stopReason string, inputTokens, outputTokens int) error {
+	if options.StreamingChunkFunc == nil {
+		return nil
+	}
+	if stopReason != "" {
+		if err := options.StreamingChunkFunc(ctx, llms.StreamChunk{StopReason: stopReason}); err != nil {
+			return err
+		}
+	}
+	usage := llms.NewUsage(inputTokens, outputTokens)
+	return options.StreamingChunkFunc(ctx, llms.StreamChunk{Usage: &usage})
+}
+
 // process the input messages to anthropic supported input
 // returns the input content and system prompt.
 func processInputMessagesAnthropic(messages []Message) ([]*anthropicTextGenerationInputMessage, string, error) {
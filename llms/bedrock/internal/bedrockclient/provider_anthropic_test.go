@@ -0,0 +1,51 @@
+package bedrockclient
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestAnthropicStreamChunks(t *testing.T) {
+	t.Parallel()
+	events := `{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude-3-haiku","usage":{"input_tokens":10,"output_tokens":1}}}
+{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hello"}}
+{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":" world"}}
+{"type":"content_block_stop","index":0}
+{"type":"message_delta","delta":{"stop_reason":"end_turn","stop_sequence":null},"usage":{"output_tokens":5}}
+{"type":"message_stop","amazon-bedrock-invocationMetrics":{"inputTokenCount":10,"outputTokenCount":5}}`
+
+	var chunks []llms.StreamChunk
+	var text string
+	options := llms.CallOptions{
+		StreamingFunc: func(_ context.Context, chunk []byte) error {
+			text += string(chunk)
+			return nil
+		},
+		StreamingChunkFunc: func(_ context.Context, chunk llms.StreamChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		},
+	}
+	message := &anthropicStream{choice: &llms.ContentChoice{GenerationInfo: map[string]any{}}}
+	for _, event := range strings.Split(events, "\n") {
+		require.NoError(t, message.handleChunk(context.Background(), options, []byte(event)))
+	}
+
+	assert.Equal(t, "Hello world", message.choice.Content)
+	assert.Equal(t, "end_turn", message.choice.StopReason)
+	assert.Equal(t, "Hello world", text)
+	// Tools are not supported for Anthropic models on Bedrock, so there are no
+	// tool call chunks.
+	assert.Equal(t, []llms.StreamChunk{
+		{Text: "Hello"},
+		{Text: " world"},
+		{StopReason: "end_turn"},
+		{Usage: &llms.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}},
+	}, chunks)
+}
@@ -67,6 +67,11 @@ func (c *Cacher) GenerateContent(ctx context.Context, messages []llms.MessageCon
 				return nil, err
 			}
 		}
+		if opts.StreamingChunkFunc != nil && len(response.Choices) > 0 {
+			if err := streamChoice(ctx, opts.StreamingChunkFunc, response.Choices[0]); err != nil {
+				return nil, err
+			}
+		}
 
 		return response, nil
 	}
@@ -94,3 +99,32 @@ func hashKeyForCache(messages []llms.MessageContent, opts llms.CallOptions) (str
 	}
 	return hex.EncodeToString(hash.Sum(nil)), nil
 }
+
+// streamChoice replays a cached choice as typed chunks: its text, its tool
+// calls and its stop reason.
+func streamChoice(ctx context.Context, chunkFunc func(context.Context, llms.StreamChunk) error, choice *llms.ContentChoice) error { //nolint:lll
+	var chunks []llms.StreamChunk
+	if choice.Content != "" {
+		chunks = append(chunks, llms.StreamChunk{Text: choice.Content})
+	}
+	for i, tc := range choice.ToolCalls {
+		if tc.FunctionCall == nil {
+			continue
+		}
+		chunks = append(chunks, llms.StreamChunk{ToolCall: &llms.ToolCallChunk{
+			Index:     i,
+			ID:        tc.ID,
+			Name:      tc.FunctionCall.Name,
+			Arguments: tc.FunctionCall.Arguments,
+		}})
+	}
+	if choice.StopReason != "" {
+		chunks = append(chunks, llms.StreamChunk{StopReason: choice.StopReason})
+	}
+	for _, chunk := range chunks {
+		if err := chunkFunc(ctx, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -172,3 +172,40 @@ func TestCache_Call_Streaming(t *testing.T) {
 	rq.True(mockCache.hit)
 	rq.True(stream)
 }
+
+func TestCache_GenerateContent_StreamingChunks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rq := require.New(t)
+
+	exp := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content:    "world",
+			StopReason: "stop",
+			ToolCalls: []llms.ToolCall{{
+				ID:           "call_1",
+				FunctionCall: &llms.FunctionCall{Name: "search", Arguments: `{"q":"go"}`},
+			}},
+		}},
+	}
+	llm := New(newMockLLM(exp, nil), newMockCache())
+
+	var chunks []llms.StreamChunk
+	streamChunk := llms.WithStreamingChunkFunc(func(_ context.Context, chunk llms.StreamChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	_, err := llm.Call(ctx, "hello", streamChunk)
+	rq.NoError(err)
+
+	// expect that the cached value is replayed as chunks
+	chunks = nil
+	_, err = llm.Call(ctx, "hello", streamChunk)
+	rq.NoError(err)
+	rq.Equal([]llms.StreamChunk{
+		{Text: "world"},
+		{ToolCall: &llms.ToolCallChunk{ID: "call_1", Name: "search", Arguments: `{"q":"go"}`}},
+		{StopReason: "stop"},
+	}, chunks)
+}
@@ -1,3 +1,7 @@
+// Package cloudflare provides a model for Cloudflare Workers AI.
+//
+// Workers AI does not report the tokens used, so the Usage of the responses
+// is zero and callbacks.UsageTracker only counts the calls.
 package cloudflare
 
 import (
@@ -107,6 +111,16 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		chatMsgs = append(chatMsgs, msg)
 	}
 
+	// There is no native support for response schemas, the schema is given
+	// in the last message.
+	if len(chatMsgs) > 0 {
+		last := &chatMsgs[len(chatMsgs)-1]
+		var err error
+		if last.Content, err = llms.AppendResponseSchemaPrompt(last.Content, opts.ResponseSchema); err != nil {
+			return nil, err
+		}
+	}
+
 	stream := func(b bool) *bool { return &b }(opts.StreamingFunc != nil)
 
 	res, err := o.client.GenerateContent(ctx, &cloudflareclient.GenerateContentRequest{
@@ -43,8 +43,12 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	// Assume we get a single text message
 	msg0 := messages[0]
 	part := msg0.Parts[0]
+	prompt, err := llms.AppendResponseSchemaPrompt(part.(llms.TextContent).Text, opts.ResponseSchema)
+	if err != nil {
+		return nil, err
+	}
 	result, err := o.client.CreateGeneration(ctx, &cohereclient.GenerationRequest{
-		Prompt: part.(llms.TextContent).Text,
+		Prompt: prompt,
 	})
 	if err != nil {
 		if o.CallbacksHandler != nil {
@@ -59,6 +63,11 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 				Content: result.Text,
 			},
 		},
+		Usage: llms.NewUsage(result.InputTokens, result.OutputTokens),
+	}
+
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
 	}
 	return resp, nil
 }
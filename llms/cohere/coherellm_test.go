@@ -0,0 +1,44 @@
+package cohere
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestGenerateContentUsage(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/generate", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "1",
+			"generations": [{"id": "1", "text": "yes"}],
+			"meta": {"api_version": {"version": "1"}, "billed_units": {"input_tokens": 10, "output_tokens": 3}}
+		}`))
+	}))
+	defer server.Close()
+
+	llm, err := New(WithToken("test"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	tracker := callbacks.NewUsageTracker(nil)
+	llm.CallbacksHandler = tracker
+
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Brazil is a country?"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "yes", resp.Choices[0].Content)
+	assert.Equal(t, llms.NewUsage(10, 3), resp.Usage)
+
+	total := tracker.Total()
+	assert.Equal(t, 1, total.Calls)
+	assert.Equal(t, llms.NewUsage(10, 3), total.Usage)
+}
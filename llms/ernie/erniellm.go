@@ -78,8 +78,12 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	// Assume we get a single text message
 	msg0 := messages[0]
 	part := msg0.Parts[0]
+	prompt, err := llms.AppendResponseSchemaPrompt(part.(llms.TextContent).Text, opts.ResponseSchema)
+	if err != nil {
+		return nil, err
+	}
 	result, err := o.client.CreateCompletion(ctx, o.getModelPath(*opts), &ernieclient.CompletionRequest{
-		Messages:      []ernieclient.Message{{Role: "user", Content: part.(llms.TextContent).Text}},
+		Messages:      []ernieclient.Message{{Role: "user", Content: prompt}},
 		Temperature:   opts.Temperature,
 		TopP:          opts.TopP,
 		PenaltyScore:  opts.RepetitionPenalty,
@@ -107,6 +111,11 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 				Content: result.Result,
 			},
 		},
+		Usage: llms.Usage{
+			PromptTokens:     result.Usage.PromptTokens,
+			CompletionTokens: result.Usage.CompletionTokens,
+			TotalTokens:      result.Usage.TotalTokens,
+		},
 	}
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
@@ -0,0 +1,163 @@
+package llms
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The kinds of errors of the providers. Errors returned by the providers wrap
+// them when the failure is known, so that callers can check them with
+// errors.Is.
+var (
+	// ErrRateLimited is the kind of the errors of requests rejected because a
+	// rate limit was reached. Requests rejected because the account ran out of
+	// quota are not rate limited, as retrying them does not help.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrContextLengthExceeded is the kind of the errors of requests whose
+	// prompt does not fit in the context of the model.
+	ErrContextLengthExceeded = errors.New("context length exceeded")
+	// ErrAuthentication is the kind of the errors of requests with missing or
+	// invalid credentials, or without access to the model.
+	ErrAuthentication = errors.New("authentication failed")
+	// ErrContentFiltered is the kind of the errors of requests rejected by the
+	// content filter of the provider.
+	ErrContentFiltered = errors.New("content filtered")
+	// ErrProviderUnavailable is the kind of the errors of requests that failed
+	// because the provider is down, overloaded or timed out.
+	ErrProviderUnavailable = errors.New("provider unavailable")
+)
+
+// ErrEmptyResponse is returned when the model responds without any choice.
+var ErrEmptyResponse = errors.New("empty response from model")
+
+// Error is an error returned by a provider, classified by kind.
+type Error struct {
+	// Kind is one of ErrRateLimited, ErrContextLengthExceeded,
+	// ErrAuthentication, ErrContentFiltered and ErrProviderUnavailable, or nil
+	// if the error could not be classified.
+	Kind error
+	// StatusCode is the HTTP status code of the response, if any.
+	StatusCode int
+	// RetryAfter is how long the provider asked to wait before retrying, if it
+	// did.
+	RetryAfter time.Duration
+	// Err is the error of the provider.
+	Err error
+}
+
+// Error returns the message of the error of the provider.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the kind and the error of the provider.
+func (e *Error) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// NewError classifies an error of a provider from the HTTP status code and
+// headers of the response, if any, and the message of err. The status code is
+// zero for errors that did not come with a response.
+func NewError(statusCode int, header http.Header, err error) *Error {
+	e := &Error{
+		Kind:       classifyError(statusCode, strings.ToLower(err.Error())),
+		StatusCode: statusCode,
+		Err:        err,
+	}
+	if header != nil {
+		e.RetryAfter = parseRetryAfter(header)
+	}
+	return e
+}
+
+// nolint:gochecknoglobals
+var (
+	_quotaExceededMessages = []string{
+		"insufficient_quota", "exceeded your current quota",
+	}
+	_contentFilteredMessages = []string{
+		"content_filter", "content filter", "content management policy", "content_policy_violation",
+	}
+	_contextLengthMessages = []string{
+		"context_length_exceeded", "maximum context length", "context window", "prompt is too long",
+		"input is too long", "too many input tokens", "exceeds the maximum number of tokens",
+	}
+	_rateLimitedMessages = []string{
+		"rate limit", "rate_limit", "too many requests", "throttlingexception", "resource_exhausted",
+		"resource exhausted",
+	}
+	_authenticationMessages = []string{
+		"api key", "api_key", "unauthorized", "unauthenticated", "permission denied",
+		"permission_denied", "access denied", "accessdenied",
+	}
+	_unavailableMessages = []string{
+		"overloaded", "unavailable", "internal server error", "bad gateway", "timed out",
+	}
+)
+
+// classifyError returns the kind of an error with the given status code and
+// lower case message. Errors without a status code can only be classified by
+// their message. Errors of accounts out of quota are left unclassified even
+// though some providers send them with a 429 status code.
+func classifyError(statusCode int, message string) error {
+	switch {
+	case containsAny(message, _quotaExceededMessages):
+		return nil
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrAuthentication
+	case statusCode == http.StatusRequestEntityTooLarge:
+		return ErrContextLengthExceeded
+	case statusCode == http.StatusRequestTimeout || statusCode >= http.StatusInternalServerError:
+		return ErrProviderUnavailable
+	case containsAny(message, _contentFilteredMessages):
+		return ErrContentFiltered
+	case containsAny(message, _contextLengthMessages):
+		return ErrContextLengthExceeded
+	case containsAny(message, _rateLimitedMessages):
+		return ErrRateLimited
+	case containsAny(message, _authenticationMessages):
+		return ErrAuthentication
+	case statusCode == 0 && containsAny(message, _unavailableMessages):
+		return ErrProviderUnavailable
+	default:
+		return nil
+	}
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRetryAfter returns the delay of the Retry-After header, given in
+// seconds or as a date, or of the retry-after-ms header some providers send.
+func parseRetryAfter(header http.Header) time.Duration {
+	if ms, err := strconv.ParseFloat(header.Get("Retry-After-Ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
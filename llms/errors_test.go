@@ -0,0 +1,91 @@
+package llms
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		statusCode int
+		message    string
+		kind       error
+	}{
+		{"rate limited", http.StatusTooManyRequests, "slow down", ErrRateLimited},
+		{"unauthorized", http.StatusUnauthorized, "invalid key", ErrAuthentication},
+		{"server error", http.StatusBadGateway, "oops", ErrProviderUnavailable},
+		{"overloaded", 529, "Overloaded", ErrProviderUnavailable},
+		{
+			"context length", http.StatusBadRequest,
+			"This model's maximum context length is 8192 tokens", ErrContextLengthExceeded,
+		},
+		{"prompt too long", http.StatusBadRequest, "prompt is too long: 300000 tokens", ErrContextLengthExceeded},
+		{
+			"content filter", http.StatusBadRequest,
+			"The response was filtered due to the prompt triggering the content management policy", ErrContentFiltered,
+		},
+		{"throttling without status", 0, "ThrottlingException: Too many requests", ErrRateLimited},
+		{"overloaded without status", 0, "stream error: overloaded_error: Overloaded", ErrProviderUnavailable},
+		{
+			"openai context length code", http.StatusBadRequest,
+			"API returned unexpected status code: 400: context_length_exceeded: too long", ErrContextLengthExceeded,
+		},
+		{
+			"googleai input token count", http.StatusBadRequest,
+			"The input token count (40000) exceeds the maximum number of tokens allowed (32768).",
+			ErrContextLengthExceeded,
+		},
+		{"anthropic rate limit", http.StatusBadRequest, "rate_limit_error: slow down", ErrRateLimited},
+		{
+			"insufficient quota", http.StatusTooManyRequests,
+			"API returned unexpected status code: 429: insufficient_quota: You exceeded your current quota, " +
+				"please check your plan and billing details.", nil,
+		},
+		{"insufficient quota without status", 0, "You exceeded your current quota", nil},
+		{"safety setting", http.StatusBadRequest, "invalid value for safety_settings", nil},
+		{"token count", http.StatusBadRequest, "max_tokens must be a positive token count", nil},
+		{"exceeds the maximum", http.StatusBadRequest, "temperature exceeds the maximum of 2", nil},
+		{"quota project", http.StatusBadRequest, "quota project not set", nil},
+		{"unavailable model", http.StatusNotFound, "model unavailable", nil},
+		{"unknown", http.StatusBadRequest, "invalid temperature", nil},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cause := errors.New(tc.message)
+			err := NewError(tc.statusCode, nil, cause)
+			assert.Equal(t, tc.kind, err.Kind)
+			assert.Equal(t, tc.message, err.Error())
+			assert.ErrorIs(t, err, cause)
+			if tc.kind != nil {
+				assert.ErrorIs(t, err, tc.kind)
+			}
+		})
+	}
+}
+
+func TestNewErrorRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{}
+	header.Set("Retry-After", "2")
+	assert.Equal(t, 2*time.Second, NewError(http.StatusTooManyRequests, header, errors.New("")).RetryAfter)
+
+	header.Set("Retry-After-Ms", "150")
+	assert.Equal(t, 150*time.Millisecond, NewError(http.StatusTooManyRequests, header, errors.New("")).RetryAfter)
+
+	header = http.Header{}
+	header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	retryAfter := NewError(http.StatusServiceUnavailable, header, errors.New("")).RetryAfter
+	assert.InDelta(t, time.Hour, retryAfter, float64(2*time.Second))
+
+	assert.Zero(t, NewError(http.StatusTooManyRequests, nil, errors.New("")).RetryAfter)
+}
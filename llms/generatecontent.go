@@ -122,6 +122,11 @@ func (ToolCallResponse) isPart() {}
 // It can potentially return multiple content choices.
 type ContentResponse struct {
 	Choices []*ContentChoice
+
+	// Usage is the number of tokens used to generate all the choices.
+	Usage Usage
+	// Model is the model that generated the response, if known.
+	Model string
 }
 
 // ContentChoice is one of the response choices returned by GenerateContent
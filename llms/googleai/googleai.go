@@ -7,12 +7,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 
 	"github.com/google/generative-ai-go/genai"
 	"github.com/tmc/langchaingo/internal/util"
+	"github.com/tmc/langchaingo/jsonschema"
 	"github.com/tmc/langchaingo/llms"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -86,6 +91,10 @@ func (g *GoogleAI) GenerateContent(
 	if model.Tools, err = convertTools(opts.Tools); err != nil {
 		return nil, err
 	}
+	if opts.ResponseSchema != nil {
+		model.ResponseMIMEType = "application/json"
+		model.ResponseSchema = convertSchema(*opts.ResponseSchema)
+	}
 
 	var response *llms.ContentResponse
 
@@ -99,8 +108,9 @@ func (g *GoogleAI) GenerateContent(
 		response, err = generateFromMessages(ctx, model, messages, &opts)
 	}
 	if err != nil {
-		return nil, err
+		return nil, providerError(err)
 	}
+	response.Model = opts.Model
 
 	if g.CallbacksHandler != nil {
 		g.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
@@ -161,6 +171,14 @@ func convertCandidates(candidates []*genai.Candidate, usage *genai.UsageMetadata
 				ToolCalls:      toolCalls,
 			})
 	}
+
+	if usage != nil {
+		contentResponse.Usage = llms.Usage{
+			PromptTokens:     int(usage.PromptTokenCount),
+			CompletionTokens: int(usage.CandidatesTokenCount),
+			TotalTokens:      int(usage.TotalTokenCount),
+		}
+	}
 	return &contentResponse, nil
 }
 
@@ -249,7 +267,7 @@ func generateFromSingleMessage(
 		return nil, err
 	}
 
-	if opts.StreamingFunc == nil {
+	if opts.StreamingFunc == nil && opts.StreamingChunkFunc == nil {
 		// When no streaming is requested, just call GenerateContent and return
 		// the complete response with a list of candidates.
 		resp, err := model.GenerateContent(ctx, convertedParts...)
@@ -294,7 +312,7 @@ func generateFromMessages(
 	session := model.StartChat()
 	session.History = history
 
-	if opts.StreamingFunc == nil {
+	if opts.StreamingFunc == nil && opts.StreamingChunkFunc == nil {
 		resp, err := session.SendMessage(ctx, reqContent.Parts...)
 		if err != nil {
 			return nil, err
@@ -311,7 +329,7 @@ func generateFromMessages(
 
 // convertAndStreamFromIterator takes an iterator of GenerateContentResponse
 // and produces a llms.ContentResponse reply from it, while streaming the
-// resulting text into the opts-provided streaming function.
+// resulting text into the opts-provided streaming functions.
 // Note that this is tricky in the face of multiple
 // candidates, so this code assumes only a single candidate for now.
 func convertAndStreamFromIterator(
@@ -322,6 +340,7 @@ func convertAndStreamFromIterator(
 	candidate := &genai.Candidate{
 		Content: &genai.Content{},
 	}
+	toolCalls := 0
 DoStream:
 	for {
 		resp, err := iter.Next()
@@ -347,16 +366,62 @@ DoStream:
 		candidate.CitationMetadata = respCandidate.CitationMetadata
 		candidate.TokenCount += respCandidate.TokenCount
 
-		for _, part := range respCandidate.Content.Parts {
-			if text, ok := part.(genai.Text); ok {
-				if opts.StreamingFunc(ctx, []byte(text)) != nil {
-					break DoStream
-				}
-			}
+		if err := streamParts(ctx, opts, respCandidate.Content.Parts, &toolCalls); err != nil {
+			return nil, err
 		}
 	}
 	mresp := iter.MergedResponse()
-	return convertCandidates([]*genai.Candidate{candidate}, mresp.UsageMetadata)
+	resp, err := convertCandidates([]*genai.Candidate{candidate}, mresp.UsageMetadata)
+	if err != nil || opts.StreamingChunkFunc == nil {
+		return resp, err
+	}
+
+	// The stop reason and the usage are known once the stream is done.
+	if candidate.FinishReason != genai.FinishReasonUnspecified {
+		stopReason := llms.StreamChunk{StopReason: candidate.FinishReason.String()}
+		if err := opts.StreamingChunkFunc(ctx, stopReason); err != nil {
+			return nil, fmt.Errorf("streaming chunk func returned an error: %w", err)
+		}
+	}
+	if mresp.UsageMetadata != nil {
+		if err := opts.StreamingChunkFunc(ctx, llms.StreamChunk{Usage: &resp.Usage}); err != nil {
+			return nil, fmt.Errorf("streaming chunk func returned an error: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+// streamParts sends the parts of a streamed candidate to the streaming
+// functions. Gemini streams whole function calls, indexed after the toolCalls
+// already streamed. An error of the streaming functions ends the stream.
+func streamParts(ctx context.Context, opts *llms.CallOptions, parts []genai.Part, toolCalls *int) error {
+	for _, part := range parts {
+		switch v := part.(type) {
+		case genai.Text:
+			err := llms.SendStreamChunk(ctx, opts.StreamingFunc, opts.StreamingChunkFunc, llms.StreamChunk{Text: string(v)})
+			if err != nil {
+				return fmt.Errorf("streaming chunk func returned an error: %w", err)
+			}
+		case genai.FunctionCall:
+			if opts.StreamingChunkFunc == nil {
+				continue
+			}
+			args, err := json.Marshal(v.Args)
+			if err != nil {
+				return err
+			}
+			err = opts.StreamingChunkFunc(ctx, llms.StreamChunk{ToolCall: &llms.ToolCallChunk{
+				Index:     *toolCalls,
+				Name:      v.Name,
+				Arguments: string(args),
+			}})
+			if err != nil {
+				return fmt.Errorf("streaming chunk func returned an error: %w", err)
+			}
+			*toolCalls++
+		}
+	}
+	return nil
 }
 
 // convertTools converts from a list of langchaingo tools to a list of genai
@@ -447,6 +512,29 @@ func convertTools(tools []llms.Tool) ([]*genai.Tool, error) {
 	return genaiTools, nil
 }
 
+// convertSchema converts a JSON schema to a genai schema.
+func convertSchema(def jsonschema.Definition) *genai.Schema {
+	schema := &genai.Schema{
+		Type:        convertToolSchemaType(string(def.Type)),
+		Description: def.Description,
+		Enum:        def.Enum,
+		Required:    def.Required,
+	}
+	if len(def.Enum) > 0 {
+		schema.Format = "enum"
+	}
+	if def.Items != nil {
+		schema.Items = convertSchema(*def.Items)
+	}
+	if len(def.Properties) > 0 {
+		schema.Properties = make(map[string]*genai.Schema, len(def.Properties))
+		for name, prop := range def.Properties {
+			schema.Properties[name] = convertSchema(prop)
+		}
+	}
+	return schema
+}
+
 // convertToolSchemaType converts a tool's schema type from its langchaingo
 // representation (string) to a genai enum.
 func convertToolSchemaType(ty string) genai.Type {
@@ -490,3 +578,31 @@ func showContent(w io.Writer, cs []*genai.Content) {
 		}
 	}
 }
+
+// _grpcStatusCodes maps the gRPC codes of the API to the HTTP status codes
+// llms.NewError classifies.
+var _grpcStatusCodes = map[codes.Code]int{
+	codes.ResourceExhausted: http.StatusTooManyRequests,
+	codes.Unauthenticated:   http.StatusUnauthorized,
+	codes.PermissionDenied:  http.StatusForbidden,
+	codes.Unavailable:       http.StatusServiceUnavailable,
+	codes.Internal:          http.StatusInternalServerError,
+	codes.DeadlineExceeded:  http.StatusGatewayTimeout,
+}
+
+// providerError classifies the errors of the API, as returned over REST or
+// gRPC. Other errors are returned as is.
+func providerError(err error) error {
+	var blocked *genai.BlockedError
+	if errors.As(err, &blocked) {
+		return &llms.Error{Kind: llms.ErrContentFiltered, Err: err}
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return llms.NewError(apiErr.Code, apiErr.Header, err)
+	}
+	if st, ok := status.FromError(err); ok {
+		return llms.NewError(_grpcStatusCodes[st.Code()], nil, err)
+	}
+	return err
+}
@@ -0,0 +1,42 @@
+package googleai
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestStreamParts(t *testing.T) {
+	t.Parallel()
+
+	errStop := errors.New("stop")
+	var chunks []llms.StreamChunk
+	opts := &llms.CallOptions{StreamingChunkFunc: func(_ context.Context, chunk llms.StreamChunk) error {
+		chunks = append(chunks, chunk)
+		if len(chunks) == 3 {
+			return errStop
+		}
+		return nil
+	}}
+	parts := []genai.Part{
+		genai.Text("Hello"),
+		genai.FunctionCall{Name: "getWeather", Args: map[string]any{"city": "Paris"}},
+		genai.FunctionCall{Name: "getTime", Args: map[string]any{}},
+		genai.Text("never sent"),
+	}
+
+	toolCalls := 1
+	err := streamParts(context.Background(), opts, parts, &toolCalls)
+	require.ErrorIs(t, err, errStop)
+	assert.Equal(t, []llms.StreamChunk{
+		{Text: "Hello"},
+		{ToolCall: &llms.ToolCallChunk{Index: 1, Name: "getWeather", Arguments: `{"city":"Paris"}`}},
+		{ToolCall: &llms.ToolCallChunk{Index: 2, Name: "getTime", Arguments: `{}`}},
+	}, chunks)
+	assert.Equal(t, 2, toolCalls)
+}
@@ -99,6 +99,7 @@ var testConfigs = []testConfig{
 		[]googleai.Option{googleai.WithHarmThreshold(googleai.HarmBlockMediumAndAbove)},
 	},
 	{testWithStreaming, nil},
+	{testGenerateStructured, nil},
 	{testWithHTTPClient, getHTTPTestClientOptions()},
 }
 
@@ -174,6 +175,23 @@ func testGenerateFromSinglePrompt(t *testing.T, llm llms.Model) {
 	assert.Regexp(t, "(?i)jupiter", rsp)
 }
 
+func testGenerateStructured(t *testing.T, llm llms.Model) {
+	t.Helper()
+	t.Parallel()
+
+	type planet struct {
+		Name  string `json:"name"`
+		Moons int    `json:"moons"`
+	}
+	content := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Which planet of the solar system is the largest?"),
+	}
+	rsp, err := llms.GenerateStructured[planet](context.Background(), llm, content)
+	require.NoError(t, err)
+
+	assert.Regexp(t, "(?i)jupiter", rsp.Name)
+}
+
 func testMultiContentTextChatSequence(t *testing.T, llm llms.Model) {
 	t.Helper()
 	t.Parallel()
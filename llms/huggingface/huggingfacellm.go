@@ -1,3 +1,7 @@
+// Package huggingface provides a model for the Hugging Face inference API.
+//
+// The inference API does not report the tokens used, so the Usage of the
+// responses is zero and callbacks.UsageTracker only counts the calls.
 package huggingface
 
 import (
@@ -43,9 +47,13 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	// Assume we get a single text message
 	msg0 := messages[0]
 	part := msg0.Parts[0]
+	prompt, err := llms.AppendResponseSchemaPrompt(part.(llms.TextContent).Text, opts.ResponseSchema)
+	if err != nil {
+		return nil, err
+	}
 	result, err := o.client.RunInference(ctx, &huggingfaceclient.InferenceRequest{
 		Model:             o.client.Model,
-		Prompt:            part.(llms.TextContent).Text,
+		Prompt:            prompt,
 		Task:              huggingfaceclient.InferenceTaskTextGeneration,
 		Temperature:       opts.Temperature,
 		TopP:              opts.TopP,
@@ -69,6 +77,10 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 			},
 		},
 	}
+
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
+	}
 	return resp, nil
 }
 
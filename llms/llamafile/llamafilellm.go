@@ -90,6 +90,16 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		chatMsgs = append(chatMsgs, msg)
 	}
 
+	// There is no native support for response schemas, the schema is given
+	// in the last message.
+	if len(chatMsgs) > 0 {
+		last := chatMsgs[len(chatMsgs)-1]
+		var err error
+		if last.Content, err = llms.AppendResponseSchemaPrompt(last.Content, opts.ResponseSchema); err != nil {
+			return nil, err
+		}
+	}
+
 	req := &llamafileclient.ChatRequest{
 		Messages: chatMsgs,
 		Stream:   func(b bool) *bool { return &b }(opts.StreamingFunc != nil),
@@ -98,6 +108,8 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	req = makeLlamaOptionsFromOptions(req, opts)
 
 	streamedResponse := ""
+	var usage llms.Usage
+	var model string
 	fn := func(response llamafileclient.ChatResponse) error {
 		if opts.StreamingFunc != nil && response.Content != "" {
 			if err := opts.StreamingFunc(ctx, []byte(response.Content)); err != nil {
@@ -107,6 +119,12 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		if response.Content != "" {
 			streamedResponse += response.Content
 		}
+		// The token counts come with the last response.
+		if response.Stop {
+			usage = llms.NewUsage(response.TokensEvaluated, response.TokensPredicted)
+			usage.CachedTokens = response.TokensCached
+			model = response.Model
+		}
 
 		return nil
 	}
@@ -119,13 +137,20 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		return nil, err
 	}
 
-	return &llms.ContentResponse{
+	resp := &llms.ContentResponse{
 		Choices: []*llms.ContentChoice{
 			{
 				Content: streamedResponse,
 			},
 		},
-	}, nil
+		Usage: usage,
+		Model: model,
+	}
+
+	if o.CallbacksHandler != nil {
+		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
+	}
+	return resp, nil
 }
 
 func (o *LLM) CreateEmbedding(ctx context.Context, texts []string) ([][]float32, error) {
@@ -2,11 +2,14 @@ package llamafile
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/llms"
 )
 
@@ -44,6 +47,34 @@ func TestGenerateContent(t *testing.T) {
 	assert.Regexp(t, "yes", strings.ToLower(c1.Content))
 }
 
+// The server URL is set with LLAMAFILE_HOST, so the test can't be parallel.
+func TestGenerateContentUsage(t *testing.T) { //nolint:paralleltest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/completion", r.URL.Path)
+		_, _ = w.Write([]byte(`{"content":"yes","model":"llava","stop":true,` +
+			`"tokens_cached":4,"tokens_evaluated":10,"tokens_predicted":3}` + "\n"))
+	}))
+	defer server.Close()
+	t.Setenv("LLAMAFILE_HOST", server.URL)
+
+	llm, err := New()
+	require.NoError(t, err)
+	tracker := callbacks.NewUsageTracker(nil)
+	llm.CallbacksHandler = tracker
+
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Brazil is a country?"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "yes", resp.Choices[0].Content)
+
+	want := llms.NewUsage(10, 3)
+	want.CachedTokens = 4
+	total := tracker.Total()
+	assert.Equal(t, 1, total.Calls)
+	assert.Equal(t, want, total.Usage)
+}
+
 func TestWithStreaming(t *testing.T) {
 	t.Skip("llamafile is not available")
 	t.Parallel()
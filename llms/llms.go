@@ -2,7 +2,6 @@ package llms
 
 import (
 	"context"
-	"errors"
 )
 
 // LLM is an alias for model, for backwards compatibility.
@@ -45,7 +44,7 @@ func GenerateFromSinglePrompt(ctx context.Context, llm Model, prompt string, opt
 
 	choices := resp.Choices
 	if len(choices) < 1 {
-		return "", errors.New("empty response from model")
+		return "", ErrEmptyResponse
 	}
 	c1 := choices[0]
 	return c1.Content, nil
@@ -1,3 +1,7 @@
+// Package local provides a model running a local LLM binary.
+//
+// The binary does not report the tokens used, so the Usage of the responses
+// is zero and callbacks.UsageTracker only counts the calls.
 package local
 
 import (
@@ -78,8 +82,12 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	// Assume we get a single text message
 	msg0 := messages[0]
 	part := msg0.Parts[0]
+	prompt, err := llms.AppendResponseSchemaPrompt(part.(llms.TextContent).Text, opts.ResponseSchema)
+	if err != nil {
+		return nil, err
+	}
 	result, err := o.client.CreateCompletion(ctx, &localclient.CompletionRequest{
-		Prompt: part.(llms.TextContent).Text,
+		Prompt: prompt,
 	})
 	if err != nil {
 		return nil, err
@@ -99,8 +99,18 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		chatMsgs = append(chatMsgs, msg)
 	}
 
+	// There is no native support for response schemas, the schema is given
+	// in the last message.
+	if len(chatMsgs) > 0 {
+		last := chatMsgs[len(chatMsgs)-1]
+		var err error
+		if last.Content, err = llms.AppendResponseSchemaPrompt(last.Content, opts.ResponseSchema); err != nil {
+			return nil, err
+		}
+	}
+
 	format := o.options.format
-	if opts.JSONMode {
+	if opts.JSONMode || opts.ResponseSchema != nil {
 		format = "json"
 	}
 
@@ -146,7 +156,15 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 
 	choices := createChoice(resp)
 
-	response := &llms.ContentResponse{Choices: choices}
+	response := &llms.ContentResponse{
+		Choices: choices,
+		Usage: llms.Usage{
+			PromptTokens:     resp.Metrics.Usage.PromptTokens,
+			CompletionTokens: resp.Metrics.Usage.CompletionTokens,
+			TotalTokens:      resp.Metrics.Usage.TotalTokens,
+		},
+		Model: model,
+	}
 
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ConcurrencyLimiter is an LLM wrapper limiting the number of calls running at
+// the same time. Calls beyond the limit wait for a running call to return.
+type ConcurrencyLimiter struct {
+	llm llms.Model
+	sem chan struct{}
+}
+
+// assert that `ConcurrencyLimiter` implements the `llms.Model` interface.
+var _ llms.Model = (*ConcurrencyLimiter)(nil)
+
+// NewConcurrencyLimiter wraps a Model and runs at most n of its calls at the
+// same time.
+func NewConcurrencyLimiter(llm llms.Model, n int) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		llm: llm,
+		sem: make(chan struct{}, max(n, 1)),
+	}
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (l *ConcurrencyLimiter) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+// GenerateContent waits for a free slot and calls the wrapped model.
+func (l *ConcurrencyLimiter) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	select {
+	case l.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-l.sem }()
+
+	return l.llm.GenerateContent(ctx, messages, options...)
+}
@@ -0,0 +1,26 @@
+// Package middleware provides wrappers adding retries, rate limits,
+// concurrency limits and timeouts to any `llms.Model`. Every wrapper is itself
+// a `llms.Model`, so they compose by wrapping each other, like `cache.Cacher`:
+//
+//	llm = middleware.NewRetrier(
+//		middleware.NewRateLimiter(
+//			middleware.NewTimeout(llm, 30*time.Second),
+//			middleware.WithRequestsPerMinute(500),
+//		),
+//	)
+//
+// The outermost wrapper sees every call first. With the order above every
+// retry waits for the rate limiter, and every attempt gets its own timeout.
+//
+// The Retrier classifies errors with IsRetryable: rate limits, unavailable
+// providers, timeouts of attempts and transient network errors are retried,
+// while errors such as llms.ErrContextLengthExceeded or llms.ErrAuthentication
+// are returned right away.
+//
+// Fallback and Router spread the calls over several models. A Fallback moves
+// on to the next model of a list when a model is rate limited, down or given a
+// prompt too long for its context. A Router picks the model of every call with
+// rules such as PromptTokensAbove, HasTools, HasImages or CostTier. Both report
+// the model that served a call in the Model of the response. The calls should
+// not pick a model with llms.WithModel, as it would be sent to every model.
+package middleware
@@ -0,0 +1,6 @@
+package middleware
+
+import "errors"
+
+// ErrNoModels is returned by a Fallback without models.
+var ErrNoModels = errors.New("no models to call")
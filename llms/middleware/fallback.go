@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Fallback is an LLM holding an ordered list of models. Calls go to the first
+// model, and move on to the next one when a model fails with an error worth
+// falling back on, such as a rate limit, an outage or a prompt too long for
+// its context. The Model of the response tells which model served the call,
+// see Named for models that do not report it.
+//
+// Like for the Retrier, calls that already streamed chunks do not fall back.
+type Fallback struct {
+	models   []llms.Model
+	fallback func(error) bool
+}
+
+// assert that `Fallback` implements the `llms.Model` interface.
+var _ llms.Model = (*Fallback)(nil)
+
+// FallbackOption is a function that configures a Fallback.
+type FallbackOption func(*Fallback)
+
+// WithFallbackIf sets the function telling whether to fall back on the next
+// model after an error. The default is ShouldFallback.
+func WithFallbackIf(fallback func(error) bool) FallbackOption {
+	return func(f *Fallback) {
+		f.fallback = fallback
+	}
+}
+
+// NewFallback creates a Fallback calling the models in order.
+func NewFallback(models []llms.Model, opts ...FallbackOption) *Fallback {
+	f := &Fallback{
+		models:   models,
+		fallback: ShouldFallback,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (f *Fallback) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, f, prompt, options...)
+}
+
+// GenerateContent calls the models in order until one of them succeeds or
+// fails with an error that does not fall back. The errors of all the models
+// called are returned joined.
+func (f *Fallback) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	if len(f.models) == 0 {
+		return nil, ErrNoModels
+	}
+
+	options, streamed := trackStreaming(options)
+	errs := make([]error, 0, len(f.models))
+	for _, llm := range f.models {
+		resp, err := llm.GenerateContent(ctx, messages, options...)
+		if err == nil {
+			return resp, nil
+		}
+		errs = append(errs, err)
+		if streamed.Load() || ctx.Err() != nil || !f.fallback(err) {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// ShouldFallback tells whether to fall back on the next model after an error:
+// the retryable errors, and prompts too long for the context of the model.
+func ShouldFallback(err error) bool {
+	return IsRetryable(err) || errors.Is(err, llms.ErrContextLengthExceeded)
+}
+
+// NamedModel is an LLM wrapper reporting its name as the Model of the
+// responses of models that do not report it.
+type NamedModel struct {
+	llm  llms.Model
+	name string
+}
+
+// assert that `NamedModel` implements the `llms.Model` interface.
+var _ llms.Model = (*NamedModel)(nil)
+
+// Named wraps a model to report name as the model serving the calls, when the
+// model does not report it itself.
+func Named(name string, llm llms.Model) *NamedModel {
+	return &NamedModel{llm: llm, name: name}
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (n *NamedModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, n, prompt, options...)
+}
+
+// GenerateContent calls the wrapped model and sets the Model of the response
+// if it is empty.
+func (n *NamedModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	resp, err := n.llm.GenerateContent(ctx, messages, options...)
+	if resp != nil && resp.Model == "" {
+		resp.Model = n.name
+	}
+	return resp, err
+}
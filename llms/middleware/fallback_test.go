@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestFallback(t *testing.T) {
+	t.Parallel()
+
+	tooLong := llms.NewError(http.StatusBadRequest, nil, errors.New("prompt is too long"))
+	unauthorized := llms.NewError(http.StatusUnauthorized, nil, errors.New("invalid api key"))
+
+	tests := []struct {
+		name      string
+		errs      [][]error
+		stream    bool
+		wantModel string
+		wantCalls []int
+		wantErr   []error
+	}{
+		{name: "first model", wantModel: "first", wantCalls: []int{1, 0, 0}},
+		{
+			name:      "rate limited and too long",
+			errs:      [][]error{{rateLimited(0)}, {tooLong}},
+			wantModel: "third",
+			wantCalls: []int{1, 1, 1},
+		},
+		{
+			name:      "does not fall back on authentication errors",
+			errs:      [][]error{{unauthorized}},
+			wantCalls: []int{1, 0, 0},
+			wantErr:   []error{unauthorized},
+		},
+		{
+			name:      "all models fail",
+			errs:      [][]error{{rateLimited(0)}, {rateLimited(0)}, {tooLong}},
+			wantCalls: []int{1, 1, 1},
+			wantErr:   []error{llms.ErrRateLimited, llms.ErrContextLengthExceeded},
+		},
+		{
+			name:      "does not fall back after streaming",
+			errs:      [][]error{{rateLimited(0)}},
+			stream:    true,
+			wantCalls: []int{1, 0, 0},
+			wantErr:   []error{llms.ErrRateLimited},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			names := []string{"first", "second", "third"}
+			mocks := make([]*mockLLM, len(names))
+			models := make([]llms.Model, len(names))
+			for i, name := range names {
+				mocks[i] = &mockLLM{content: name}
+				if i < len(tc.errs) {
+					mocks[i].errs = tc.errs[i]
+				}
+				models[i] = Named(name, mocks[i])
+			}
+			var options []llms.CallOption
+			if tc.stream {
+				options = append(options, llms.WithStreamingFunc(func(context.Context, []byte) error { return nil }))
+			}
+
+			resp, err := NewFallback(models).GenerateContent(context.Background(), nil, options...)
+			for i, m := range mocks {
+				assert.Equal(t, tc.wantCalls[i], m.callCount(), names[i])
+			}
+			if tc.wantErr != nil {
+				for _, wantErr := range tc.wantErr {
+					require.ErrorIs(t, err, wantErr)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantModel, resp.Model)
+			assert.Equal(t, tc.wantModel, resp.Choices[0].Content)
+		})
+	}
+}
+
+func TestFallbackWithoutModels(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewFallback(nil).GenerateContent(context.Background(), nil)
+	require.ErrorIs(t, err, ErrNoModels)
+}
+
+func TestNamedKeepsReportedModel(t *testing.T) {
+	t.Parallel()
+
+	llm := Named("name", llmFunc(func() *llms.ContentResponse {
+		return &llms.ContentResponse{Model: "gpt-4o-2024-08-06"}
+	}))
+	resp, err := llm.GenerateContent(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-4o-2024-08-06", resp.Model)
+}
+
+// llmFunc is a model returning the response of a function.
+type llmFunc func() *llms.ContentResponse
+
+func (f llmFunc) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, f, prompt, options...)
+}
+
+func (f llmFunc) GenerateContent(context.Context, []llms.MessageContent, ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	return f(), nil
+}
+
+func TestRouter(t *testing.T) {
+	t.Parallel()
+
+	named := func(name string) llms.Model { return Named(name, &mockLLM{content: name}) }
+	router := NewRouter(named("default"),
+		Route{Match: CostTier("cheap"), Model: named("cheap")},
+		Route{Match: HasImages(), Model: named("vision")},
+		Route{Match: HasTools(), Model: named("tools")},
+		Route{Match: PromptTokensAbove("gpt-4", 50), Model: named("long")},
+	)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		messages []llms.MessageContent
+		options  []llms.CallOption
+		want     string
+	}{
+		{
+			name:     "default",
+			messages: []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")},
+			want:     "default",
+		},
+		{
+			name: "cost tier",
+			ctx:  ContextWithCostTier(context.Background(), "cheap"),
+			want: "cheap",
+		},
+		{
+			name: "images",
+			messages: []llms.MessageContent{{
+				Role:  llms.ChatMessageTypeHuman,
+				Parts: []llms.ContentPart{llms.BinaryPart("image/png", []byte{1})},
+			}},
+			want: "vision",
+		},
+		{
+			name:    "tools",
+			options: []llms.CallOption{llms.WithTools([]llms.Tool{{Type: "function"}})},
+			want:    "tools",
+		},
+		{
+			name:     "long prompt",
+			messages: []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, strings.Repeat("word ", 200))},
+			want:     "long",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := tc.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			resp, err := router.GenerateContent(ctx, tc.messages, tc.options...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, resp.Model)
+		})
+	}
+}
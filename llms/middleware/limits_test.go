@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestRateLimiterRequests(t *testing.T) {
+	t.Parallel()
+
+	llm := &mockLLM{}
+	l := NewRateLimiter(llm, WithRequestsPerMinute(2))
+
+	for i := 0; i < 2; i++ {
+		_, err := l.GenerateContent(context.Background(), nil)
+		require.NoError(t, err)
+	}
+
+	// The third call has to wait 30s for the bucket to refill.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := l.GenerateContent(ctx, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 2, llm.callCount())
+}
+
+func TestRateLimiterTokens(t *testing.T) {
+	t.Parallel()
+
+	llm := &mockLLM{usage: llms.Usage{TotalTokens: 1000}}
+	l := NewRateLimiter(llm, WithTokensPerMinute(1000), WithTokenEstimator(
+		func([]llms.MessageContent, llms.CallOptions) int { return 10 },
+	))
+
+	// The estimate fits, but the actual usage drains the bucket.
+	_, err := l.GenerateContent(context.Background(), nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = l.GenerateContent(ctx, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, llm.callCount())
+}
+
+func TestTokenBucket(t *testing.T) {
+	t.Parallel()
+
+	b := newTokenBucket(10, 100*time.Millisecond)
+	require.NoError(t, b.wait(context.Background(), 10))
+
+	// 5 tokens are refilled in 50ms.
+	start := time.Now()
+	require.NoError(t, b.wait(context.Background(), 5))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	// Giving tokens back does not overflow the capacity.
+	b.take(-100)
+	assert.InDelta(t, 10, b.tokens, 0.5)
+}
+
+func TestEstimateTokens(t *testing.T) {
+	t.Parallel()
+
+	messages := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "12345678"),
+		llms.TextParts(llms.ChatMessageTypeHuman, "1234"),
+	}
+	assert.Equal(t, 3+100, estimateTokens(messages, llms.CallOptions{MaxTokens: 100}))
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	t.Parallel()
+
+	llm := &mockLLM{work: 10 * time.Millisecond}
+	l := NewConcurrencyLimiter(llm, 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := l.GenerateContent(context.Background(), nil)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 6, llm.callCount())
+	assert.Equal(t, 2, llm.maxAtOne)
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	llm := &mockLLM{work: time.Second}
+	_, err := NewTimeout(llm, 10*time.Millisecond).GenerateContent(context.Background(), nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestTimeoutRetried(t *testing.T) {
+	t.Parallel()
+
+	// The first attempt times out, the second one succeeds.
+	llm := &slowOnceLLM{mockLLM: mockLLM{content: "ok"}}
+	r := NewRetrier(NewTimeout(llm, 10*time.Millisecond), WithBackoff(time.Millisecond, time.Millisecond))
+
+	resp, err := r.GenerateContent(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.Choices[0].Content)
+	assert.Equal(t, 2, llm.callCount())
+}
+
+// slowOnceLLM hangs on its first call.
+type slowOnceLLM struct {
+	mockLLM
+	once sync.Once
+}
+
+func (m *slowOnceLLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	slow := false
+	m.once.Do(func() { slow = true })
+	if slow {
+		m.mu.Lock()
+		m.calls++
+		m.mu.Unlock()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return m.mockLLM.GenerateContent(ctx, messages, options...)
+}
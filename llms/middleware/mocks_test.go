@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// mockLLM fails with the given errors, in order, and then succeeds. It can
+// stream its content and take some time to answer.
+type mockLLM struct {
+	mu       sync.Mutex
+	errs     []error
+	calls    int
+	content  string
+	usage    llms.Usage
+	work     time.Duration
+	running  int
+	maxAtOne int
+}
+
+func (m *mockLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *mockLLM) GenerateContent(ctx context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	m.mu.Lock()
+	m.calls++
+	m.running++
+	m.maxAtOne = max(m.maxAtOne, m.running)
+	var err error
+	if len(m.errs) > 0 {
+		err, m.errs = m.errs[0], m.errs[1:]
+	}
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.running--
+		m.mu.Unlock()
+	}()
+
+	if m.work > 0 {
+		if err := sleep(ctx, m.work); err != nil {
+			return nil, err
+		}
+	}
+	if err := llms.SendStreamChunk(ctx, opts.StreamingFunc, opts.StreamingChunkFunc,
+		llms.StreamChunk{Text: m.content}); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: m.content}},
+		Usage:   m.usage,
+	}, nil
+}
+
+func (m *mockLLM) callCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
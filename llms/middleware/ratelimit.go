@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// _charsPerToken is the number of characters per token used to estimate the
+// tokens of a prompt.
+const _charsPerToken = 4
+
+// RateLimiter is an LLM wrapper keeping the calls within a number of requests
+// and tokens per minute, as the rate limits of most providers. Calls wait for
+// the limits with token buckets, which allow bursts up to a minute worth of
+// requests and tokens.
+//
+// The tokens of a call are not known before it is made. They are estimated
+// from the length of the prompt and the maximum tokens of the call, and the
+// estimate is corrected with the Usage of the response.
+type RateLimiter struct {
+	llm      llms.Model
+	requests *tokenBucket
+	tokens   *tokenBucket
+	estimate func(messages []llms.MessageContent, opts llms.CallOptions) int
+}
+
+// assert that `RateLimiter` implements the `llms.Model` interface.
+var _ llms.Model = (*RateLimiter)(nil)
+
+// RateLimitOption is a function that configures a RateLimiter.
+type RateLimitOption func(*RateLimiter)
+
+// WithRequestsPerMinute limits the number of calls per minute.
+func WithRequestsPerMinute(requests int) RateLimitOption {
+	return func(l *RateLimiter) {
+		l.requests = newTokenBucket(requests, time.Minute)
+	}
+}
+
+// WithTokensPerMinute limits the number of tokens used per minute.
+func WithTokensPerMinute(tokens int) RateLimitOption {
+	return func(l *RateLimiter) {
+		l.tokens = newTokenBucket(tokens, time.Minute)
+	}
+}
+
+// WithTokenEstimator sets the function estimating the tokens of a call before
+// it is made. The default counts a token per four characters of the text of
+// the messages, plus the maximum tokens of the call.
+func WithTokenEstimator(estimate func(messages []llms.MessageContent, opts llms.CallOptions) int) RateLimitOption {
+	return func(l *RateLimiter) {
+		l.estimate = estimate
+	}
+}
+
+// NewRateLimiter wraps a Model and limits the rate of its calls. Without
+// options the calls are not limited.
+func NewRateLimiter(llm llms.Model, opts ...RateLimitOption) *RateLimiter {
+	l := &RateLimiter{
+		llm:      llm,
+		estimate: estimateTokens,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (l *RateLimiter) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+// GenerateContent waits for the limits and calls the wrapped model.
+func (l *RateLimiter) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	if l.requests != nil {
+		if err := l.requests.wait(ctx, 1); err != nil {
+			return nil, err
+		}
+	}
+	if l.tokens == nil {
+		return l.llm.GenerateContent(ctx, messages, options...)
+	}
+
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	estimate := l.estimate(messages, opts)
+	if err := l.tokens.wait(ctx, estimate); err != nil {
+		return nil, err
+	}
+	resp, err := l.llm.GenerateContent(ctx, messages, options...)
+	if resp != nil && resp.Usage.TotalTokens > 0 {
+		l.tokens.take(resp.Usage.TotalTokens - estimate)
+	}
+	return resp, err
+}
+
+// estimateTokens estimates the tokens of a call from the text of the messages
+// and the maximum tokens of the call.
+func estimateTokens(messages []llms.MessageContent, opts llms.CallOptions) int {
+	chars := 0
+	for _, m := range messages {
+		for _, part := range m.Parts {
+			if text, ok := part.(llms.TextContent); ok {
+				chars += len(text.Text)
+			}
+		}
+	}
+	return chars/_charsPerToken + opts.MaxTokens
+}
+
+// tokenBucket is a token bucket refilled at a constant rate up to its
+// capacity. Tokens can be taken beyond what the bucket holds, the next calls
+// then wait until the debt is refilled.
+type tokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	perSec   float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(tokens int, per time.Duration) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(tokens),
+		perSec:   float64(tokens) / per.Seconds(),
+		tokens:   float64(tokens),
+		last:     time.Now(),
+	}
+}
+
+// wait waits until n tokens are available and takes them. Taking more tokens
+// than the capacity waits for a full bucket.
+func (b *tokenBucket) wait(ctx context.Context, n int) error {
+	need := math.Min(float64(n), b.capacity)
+	for {
+		b.mu.Lock()
+		b.refill()
+		if b.tokens >= need {
+			b.tokens -= float64(n)
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((need - b.tokens) / b.perSec * float64(time.Second))
+		b.mu.Unlock()
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// take takes n tokens without waiting, or gives them back if n is negative.
+func (b *tokenBucket) take(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	b.tokens = math.Min(b.tokens-float64(n), b.capacity)
+}
+
+func (b *tokenBucket) refill() {
+	now := time.Now()
+	b.tokens = math.Min(b.tokens+now.Sub(b.last).Seconds()*b.perSec, b.capacity)
+	b.last = now
+}
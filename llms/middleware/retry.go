@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	_defaultMaxAttempts  = 4
+	_defaultInitialDelay = 500 * time.Millisecond
+	_defaultMaxDelay     = 30 * time.Second
+)
+
+// Retrier is an LLM wrapper retrying the calls that failed with a retryable
+// error, waiting a jittered exponential backoff between the attempts. The
+// delay asked by the provider with Retry-After is honored.
+//
+// Calls that already streamed chunks are not retried, as the chunks cannot be
+// taken back.
+type Retrier struct {
+	llm          llms.Model
+	maxAttempts  int
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	retryable    func(error) bool
+	onRetry      func(ctx context.Context, attempt int, err error, delay time.Duration)
+}
+
+// assert that `Retrier` implements the `llms.Model` interface.
+var _ llms.Model = (*Retrier)(nil)
+
+// RetryOption is a function that configures a Retrier.
+type RetryOption func(*Retrier)
+
+// WithMaxAttempts sets the number of attempts made at most, the first one
+// included. The default is 4.
+func WithMaxAttempts(attempts int) RetryOption {
+	return func(r *Retrier) {
+		r.maxAttempts = attempts
+	}
+}
+
+// WithBackoff sets the delay before the first retry and the maximum delay
+// between two attempts. The delay doubles after every attempt, and a random
+// delay up to it is waited. The defaults are 500ms and 30s.
+//
+// When the provider asks for a delay above the maximum delay, the error is
+// returned without retrying.
+func WithBackoff(initialDelay, maxDelay time.Duration) RetryOption {
+	return func(r *Retrier) {
+		r.initialDelay = initialDelay
+		r.maxDelay = maxDelay
+	}
+}
+
+// WithRetryIf sets the function telling whether an error is retried. The
+// default is IsRetryable.
+func WithRetryIf(retryable func(error) bool) RetryOption {
+	return func(r *Retrier) {
+		r.retryable = retryable
+	}
+}
+
+// WithOnRetry sets a function called before waiting for a retry, for instance
+// to log the errors.
+func WithOnRetry(onRetry func(ctx context.Context, attempt int, err error, delay time.Duration)) RetryOption {
+	return func(r *Retrier) {
+		r.onRetry = onRetry
+	}
+}
+
+// NewRetrier wraps a Model and retries its calls.
+func NewRetrier(llm llms.Model, opts ...RetryOption) *Retrier {
+	r := &Retrier{
+		llm:          llm,
+		maxAttempts:  _defaultMaxAttempts,
+		initialDelay: _defaultInitialDelay,
+		maxDelay:     _defaultMaxDelay,
+		retryable:    IsRetryable,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (r *Retrier) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, r, prompt, options...)
+}
+
+// GenerateContent calls the wrapped model until it succeeds, fails with an
+// error that is not retryable or the attempts run out.
+func (r *Retrier) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	options, streamed := trackStreaming(options)
+	for attempt := 1; ; attempt++ {
+		resp, err := r.llm.GenerateContent(ctx, messages, options...)
+		if err == nil {
+			return resp, nil
+		}
+		// The error of a canceled call is not retried, but the timeouts of
+		// attempts set by inner wrappers are.
+		if attempt >= r.maxAttempts || streamed.Load() || ctx.Err() != nil || !r.retryable(err) {
+			return resp, err
+		}
+
+		delay, ok := r.delay(attempt, err)
+		if !ok {
+			return resp, err
+		}
+		if r.onRetry != nil {
+			r.onRetry(ctx, attempt, err, delay)
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// delay returns the delay to wait after a failed attempt, and false if the
+// provider asked to wait for longer than the maximum delay.
+func (r *Retrier) delay(attempt int, err error) (time.Duration, bool) {
+	var providerErr *llms.Error
+	if errors.As(err, &providerErr) && providerErr.RetryAfter > 0 {
+		return providerErr.RetryAfter, providerErr.RetryAfter <= r.maxDelay
+	}
+
+	backoff := r.maxDelay
+	// Shifting by more than this overflows.
+	const maxShift = 32
+	if attempt <= maxShift {
+		backoff = min(r.initialDelay<<(attempt-1), r.maxDelay)
+	}
+	if backoff <= 0 {
+		return 0, true
+	}
+	return rand.N(backoff) + 1, true //nolint:gosec
+}
+
+// IsRetryable tells whether an error is worth retrying: rate limits,
+// unavailable providers, timeouts and transient network errors.
+func IsRetryable(err error) bool {
+	switch {
+	case errors.Is(err, llms.ErrRateLimited), errors.Is(err, llms.ErrProviderUnavailable):
+		return true
+	case errors.Is(err, llms.ErrContextLengthExceeded), errors.Is(err, llms.ErrAuthentication),
+		errors.Is(err, llms.ErrContentFiltered):
+		return false
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.EPIPE):
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// trackStreaming wraps the streaming funcs of the options, if any, to tell
+// whether chunks were streamed. Such calls cannot be made again, as the chunks
+// cannot be taken back.
+func trackStreaming(options []llms.CallOption) ([]llms.CallOption, *atomic.Bool) {
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	streamed := &atomic.Bool{}
+	options = options[:len(options):len(options)]
+	if opts.StreamingFunc != nil {
+		stream := opts.StreamingFunc
+		options = append(options, llms.WithStreamingFunc(
+			func(ctx context.Context, chunk []byte) error {
+				streamed.Store(true)
+				return stream(ctx, chunk)
+			}))
+	}
+	if opts.StreamingChunkFunc != nil {
+		stream := opts.StreamingChunkFunc
+		options = append(options, llms.WithStreamingChunkFunc(
+			func(ctx context.Context, chunk llms.StreamChunk) error {
+				streamed.Store(true)
+				return stream(ctx, chunk)
+			}))
+	}
+	return options, streamed
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
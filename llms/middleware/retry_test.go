@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func rateLimited(retryAfter time.Duration) error {
+	err := llms.NewError(http.StatusTooManyRequests, nil, errors.New("rate limit reached"))
+	err.RetryAfter = retryAfter
+	return err
+}
+
+func TestRetrier(t *testing.T) {
+	t.Parallel()
+
+	unauthorized := llms.NewError(http.StatusUnauthorized, nil, errors.New("invalid api key"))
+
+	tests := []struct {
+		name    string
+		errs    []error
+		opts    []RetryOption
+		stream  bool
+		chunks  bool
+		calls   int
+		wantErr error
+	}{
+		{name: "success", calls: 1},
+		{
+			name:  "retries rate limits and server errors",
+			errs:  []error{rateLimited(0), llms.NewError(http.StatusServiceUnavailable, nil, errors.New("down"))},
+			calls: 3,
+		},
+		{
+			name:  "retries network errors",
+			errs:  []error{fmt.Errorf("send request: %w", syscall.ECONNRESET)},
+			calls: 2,
+		},
+		{name: "does not retry authentication errors", errs: []error{unauthorized}, calls: 1, wantErr: unauthorized},
+		{
+			name:    "gives up after max attempts",
+			errs:    []error{rateLimited(0), rateLimited(0), rateLimited(0)},
+			opts:    []RetryOption{WithMaxAttempts(2)},
+			calls:   2,
+			wantErr: llms.ErrRateLimited,
+		},
+		{
+			name:    "gives up when asked to wait too long",
+			errs:    []error{rateLimited(time.Hour)},
+			calls:   1,
+			wantErr: llms.ErrRateLimited,
+		},
+		{
+			name:    "does not retry after streaming",
+			errs:    []error{rateLimited(0)},
+			stream:  true,
+			calls:   1,
+			wantErr: llms.ErrRateLimited,
+		},
+		{
+			name:    "does not retry after streaming typed chunks",
+			errs:    []error{rateLimited(0)},
+			chunks:  true,
+			calls:   1,
+			wantErr: llms.ErrRateLimited,
+		},
+		{
+			name:  "custom retry policy",
+			errs:  []error{unauthorized},
+			opts:  []RetryOption{WithRetryIf(func(error) bool { return true })},
+			calls: 2,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			llm := &mockLLM{errs: tc.errs, content: "ok"}
+			opts := append([]RetryOption{WithBackoff(time.Millisecond, 10*time.Millisecond)}, tc.opts...)
+			var options []llms.CallOption
+			if tc.stream {
+				options = append(options, llms.WithStreamingFunc(func(context.Context, []byte) error { return nil }))
+			}
+			if tc.chunks {
+				options = append(options, llms.WithStreamingChunkFunc(func(context.Context, llms.StreamChunk) error { return nil }))
+			}
+
+			resp, err := NewRetrier(llm, opts...).GenerateContent(context.Background(), nil, options...)
+			assert.Equal(t, tc.calls, llm.callCount())
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "ok", resp.Choices[0].Content)
+		})
+	}
+}
+
+func TestRetrierHonorsRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	llm := &mockLLM{errs: []error{rateLimited(50 * time.Millisecond)}}
+	var delays []time.Duration
+	r := NewRetrier(llm,
+		WithBackoff(time.Millisecond, time.Second),
+		WithOnRetry(func(_ context.Context, _ int, _ error, delay time.Duration) {
+			delays = append(delays, delay)
+		}),
+	)
+
+	start := time.Now()
+	_, err := r.GenerateContent(context.Background(), nil)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, []time.Duration{50 * time.Millisecond}, delays)
+}
+
+func TestRetrierCanceled(t *testing.T) {
+	t.Parallel()
+
+	llm := &mockLLM{errs: []error{rateLimited(0), rateLimited(0)}}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := NewRetrier(llm,
+		WithBackoff(time.Hour, time.Hour),
+		WithOnRetry(func(context.Context, int, error, time.Duration) { cancel() }),
+	)
+
+	_, err := r.GenerateContent(ctx, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, llm.callCount())
+}
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err       error
+		retryable bool
+	}{
+		{rateLimited(0), true},
+		{llms.NewError(http.StatusInternalServerError, nil, errors.New("boom")), true},
+		{llms.NewError(http.StatusBadRequest, nil, errors.New("maximum context length is 10 tokens")), false},
+		{llms.NewError(http.StatusBadRequest, nil, errors.New("content_filter")), false},
+		{fmt.Errorf("attempt: %w", context.DeadlineExceeded), true},
+		{fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true},
+		{context.Canceled, false},
+		{errors.New("invalid temperature"), false},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.retryable, IsRetryable(tc.err), tc.err.Error())
+	}
+}
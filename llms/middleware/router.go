@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Matcher tells whether a call matches a route of a Router.
+type Matcher func(ctx context.Context, messages []llms.MessageContent, opts llms.CallOptions) bool
+
+// Route sends the calls matching Match to Model.
+type Route struct {
+	Match Matcher
+	Model llms.Model
+}
+
+// Router is an LLM picking the model serving every call from a list of
+// routes. The first route matching the call is taken, and calls matching no
+// route go to the default model. The Model of the response tells which model
+// served the call, see Named for models that do not report it.
+//
+// Routes can send calls to a Fallback, and the default model can be one.
+type Router struct {
+	routes       []Route
+	defaultModel llms.Model
+}
+
+// assert that `Router` implements the `llms.Model` interface.
+var _ llms.Model = (*Router)(nil)
+
+// NewRouter creates a Router with a default model and routes, checked in
+// order.
+func NewRouter(defaultModel llms.Model, routes ...Route) *Router {
+	return &Router{
+		routes:       routes,
+		defaultModel: defaultModel,
+	}
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (r *Router) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, r, prompt, options...)
+}
+
+// GenerateContent calls the model of the first route matching the call.
+func (r *Router) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	return r.Pick(ctx, messages, options...).GenerateContent(ctx, messages, options...)
+}
+
+// Pick returns the model a call is routed to.
+func (r *Router) Pick(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) llms.Model { //nolint:ireturn,lll
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	for _, route := range r.routes {
+		if route.Match(ctx, messages, opts) {
+			return route.Model
+		}
+	}
+	return r.defaultModel
+}
+
+// PromptTokensAbove matches the calls whose prompt has more than the given
+// number of tokens, as counted by llms.CountTokens with the tokenizer of
+// model.
+func PromptTokensAbove(model string, tokens int) Matcher {
+	return func(_ context.Context, messages []llms.MessageContent, _ llms.CallOptions) bool {
+		var sb strings.Builder
+		for _, m := range messages {
+			for _, part := range m.Parts {
+				if text, ok := part.(llms.TextContent); ok {
+					sb.WriteString(text.Text)
+				}
+			}
+		}
+		return llms.CountTokens(model, sb.String()) > tokens
+	}
+}
+
+// HasTools matches the calls offering tools or functions to the model, or
+// holding tool calls and their responses.
+func HasTools() Matcher {
+	return func(_ context.Context, messages []llms.MessageContent, opts llms.CallOptions) bool {
+		if len(opts.Tools) > 0 || len(opts.Functions) > 0 {
+			return true
+		}
+		return hasPart(messages, func(part llms.ContentPart) bool {
+			switch part.(type) {
+			case llms.ToolCall, llms.ToolCallResponse:
+				return true
+			default:
+				return false
+			}
+		})
+	}
+}
+
+// HasImages matches the calls holding images.
+func HasImages() Matcher {
+	return func(_ context.Context, messages []llms.MessageContent, _ llms.CallOptions) bool {
+		return hasPart(messages, func(part llms.ContentPart) bool {
+			switch p := part.(type) {
+			case llms.ImageURLContent:
+				return true
+			case llms.BinaryContent:
+				return strings.HasPrefix(p.MIMEType, "image/")
+			default:
+				return false
+			}
+		})
+	}
+}
+
+type costTierContextKey struct{}
+
+// ContextWithCostTier returns a context for calls of the given cost tier, such
+// as "cheap" or "premium", matched by CostTier.
+func ContextWithCostTier(ctx context.Context, tier string) context.Context {
+	return context.WithValue(ctx, costTierContextKey{}, tier)
+}
+
+// CostTier matches the calls made with a context of the given cost tier.
+func CostTier(tier string) Matcher {
+	return func(ctx context.Context, _ []llms.MessageContent, _ llms.CallOptions) bool {
+		t, ok := ctx.Value(costTierContextKey{}).(string)
+		return ok && t == tier
+	}
+}
+
+func hasPart(messages []llms.MessageContent, match func(llms.ContentPart) bool) bool {
+	for _, m := range messages {
+		for _, part := range m.Parts {
+			if match(part) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Timeout is an LLM wrapper canceling the calls that run for longer than a
+// timeout. Inside a Retrier, every attempt gets its own timeout and the calls
+// that timed out are retried.
+type Timeout struct {
+	llm     llms.Model
+	timeout time.Duration
+}
+
+// assert that `Timeout` implements the `llms.Model` interface.
+var _ llms.Model = (*Timeout)(nil)
+
+// NewTimeout wraps a Model and cancels its calls after timeout.
+func NewTimeout(llm llms.Model, timeout time.Duration) *Timeout {
+	return &Timeout{
+		llm:     llm,
+		timeout: timeout,
+	}
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (t *Timeout) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, t, prompt, options...)
+}
+
+// GenerateContent calls the wrapped model with a context canceled after the
+// timeout.
+func (t *Timeout) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.llm.GenerateContent(ctx, messages, options...)
+}
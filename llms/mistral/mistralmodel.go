@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"os"
+	"regexp"
+	"strconv"
 
 	sdk "github.com/gage-technologies/mistral-go"
 	"github.com/tmc/langchaingo/callbacks"
@@ -23,11 +25,12 @@ var _ llms.Model = (*Model)(nil)
 // Instantiates a new Mistral Model.
 func New(opts ...Option) (*Model, error) {
 	options := &clientOptions{
-		apiKey:     os.Getenv("MISTRAL_API_KEY"),
-		endpoint:   sdk.Endpoint,
-		maxRetries: sdk.DefaultMaxRetries,
-		timeout:    sdk.DefaultTimeout,
-		model:      sdk.ModelOpenMistral7b,
+		apiKey:           os.Getenv("MISTRAL_API_KEY"),
+		endpoint:         sdk.Endpoint,
+		maxRetries:       sdk.DefaultMaxRetries,
+		timeout:          sdk.DefaultTimeout,
+		model:            sdk.ModelOpenMistral7b,
+		callbacksHandler: callbacks.SimpleHandler{},
 	}
 
 	for _, opt := range opts {
@@ -37,7 +40,7 @@ func New(opts ...Option) (*Model, error) {
 	return &Model{
 		clientOptions:    options,
 		client:           sdk.NewMistralClient(options.apiKey, options.endpoint, options.maxRetries, options.timeout),
-		CallbacksHandler: callbacks.SimpleHandler{},
+		CallbacksHandler: options.callbacksHandler,
 	}, nil
 }
 
@@ -78,7 +81,7 @@ func (m *Model) GenerateContent(ctx context.Context, langchainMessages []llms.Me
 		return nil, err
 	}
 
-	if callOptions.StreamingFunc != nil {
+	if callOptions.StreamingFunc != nil || callOptions.StreamingChunkFunc != nil {
 		return generateStreamingContent(ctx, m, callOptions, messages, chatOpts)
 	}
 	return generateNonStreamingContent(ctx, m, callOptions, messages, chatOpts)
@@ -127,13 +130,27 @@ func mistralChatParamsFromCallOptions(callOpts *llms.CallOptions) sdk.ChatReques
 			},
 		})
 	}
+	// Mistral has no response schemas, the model is forced to call a tool
+	// taking the response as arguments instead.
+	if callOpts.ResponseSchema != nil {
+		tool := llms.StructuredOutputTool(*callOpts.ResponseSchema)
+		chatOpts.Tools = append(chatOpts.Tools, sdk.Tool{
+			Type: "function",
+			Function: sdk.Function{
+				Name:        tool.Function.Name,
+				Description: tool.Function.Description,
+				Parameters:  tool.Function.Parameters,
+			},
+		})
+		chatOpts.ToolChoice = sdk.ToolChoiceAny
+	}
 	return chatOpts
 }
 
 func generateNonStreamingContent(ctx context.Context, m *Model, callOptions *llms.CallOptions, messages []sdk.ChatMessage, chatOpts sdk.ChatRequestParams) (*llms.ContentResponse, error) {
 	res, err := m.client.Chat(callOptions.Model, messages, &chatOpts)
-	m.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, nil)
 	if err != nil {
+		err = sdkError(err)
 		m.CallbacksHandler.HandleLLMError(ctx, err)
 		return nil, err
 	}
@@ -145,6 +162,8 @@ func generateNonStreamingContent(ctx context.Context, m *Model, callOptions *llm
 
 	langchainContentResponse := &llms.ContentResponse{
 		Choices: make([]*llms.ContentChoice, 0),
+		Usage:   usageFromInfo(res.Usage),
+		Model:   res.Model,
 	}
 	for idx, choice := range res.Choices {
 		langchainContentResponse.Choices = append(langchainContentResponse.Choices, &llms.ContentChoice{
@@ -161,6 +180,9 @@ func generateNonStreamingContent(ctx context.Context, m *Model, callOptions *llm
 			langchainContentResponse.Choices[idx].FuncCall = (*llms.FunctionCall)(&toolCalls[0].Function)
 		}
 	}
+	if callOptions.ResponseSchema != nil {
+		llms.SetStructuredOutputContent(langchainContentResponse.Choices)
+	}
 	m.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, langchainContentResponse)
 
 	return langchainContentResponse, nil
@@ -169,6 +191,7 @@ func generateNonStreamingContent(ctx context.Context, m *Model, callOptions *llm
 func generateStreamingContent(ctx context.Context, m *Model, callOptions *llms.CallOptions, messages []sdk.ChatMessage, chatOpts sdk.ChatRequestParams) (*llms.ContentResponse, error) {
 	chatResChan, err := m.client.ChatStream(callOptions.Model, messages, &chatOpts)
 	if err != nil {
+		err = sdkError(err)
 		m.CallbacksHandler.HandleLLMError(ctx, err)
 		return nil, err
 	}
@@ -180,11 +203,18 @@ func generateStreamingContent(ctx context.Context, m *Model, callOptions *llms.C
 		GenerationInfo: map[string]any{},
 	}
 
+	toolCalls := 0
 	for chatResChunk := range chatResChan {
 		chunkStr := ""
 		langchainContentResponse.Choices[0].GenerationInfo["created"] = chatResChunk.Created
 		langchainContentResponse.Choices[0].GenerationInfo["model"] = chatResChunk.Model
 		langchainContentResponse.Choices[0].GenerationInfo["usage"] = chatResChunk.Usage
+		if chatResChunk.Usage != (sdk.UsageInfo{}) {
+			langchainContentResponse.Usage = usageFromInfo(chatResChunk.Usage)
+		}
+		if chatResChunk.Model != "" {
+			langchainContentResponse.Model = chatResChunk.Model
+		}
 		if chatResChunk.Error == nil {
 			for _, choice := range chatResChunk.Choices {
 				chunkStr += choice.Delta.Content
@@ -194,18 +224,80 @@ func generateStreamingContent(ctx context.Context, m *Model, callOptions *llms.C
 					langchainContentResponse.Choices[0].FuncCall = (*llms.FunctionCall)(&choice.Delta.ToolCalls[0].Function)
 				}
 			}
-			err := callOptions.StreamingFunc(ctx, []byte(chunkStr))
-			if err != nil {
-				return langchainContentResponse, err
+			if callOptions.StreamingFunc != nil {
+				err := callOptions.StreamingFunc(ctx, []byte(chunkStr))
+				if err != nil {
+					return langchainContentResponse, err
+				}
+			}
+			if callOptions.StreamingChunkFunc != nil {
+				for _, chunk := range streamChunks(chatResChunk, &toolCalls) {
+					if err := callOptions.StreamingChunkFunc(ctx, chunk); err != nil {
+						return langchainContentResponse, err
+					}
+				}
 			}
 		} else {
 			return langchainContentResponse, chatResChunk.Error
 		}
 	}
+	if callOptions.ResponseSchema != nil {
+		llms.SetStructuredOutputContent(langchainContentResponse.Choices)
+	}
+	m.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, langchainContentResponse)
 
 	return langchainContentResponse, nil
 }
 
+// streamChunks returns the typed chunks of a streamed response. Mistral streams
+// whole tool calls, indexed after the toolCalls already streamed.
+func streamChunks(res sdk.ChatCompletionStreamResponse, toolCalls *int) []llms.StreamChunk {
+	var chunks []llms.StreamChunk
+	for _, choice := range res.Choices {
+		if choice.Delta.Content != "" {
+			chunks = append(chunks, llms.StreamChunk{Text: choice.Delta.Content})
+		}
+		for _, tc := range choice.Delta.ToolCalls {
+			chunks = append(chunks, llms.StreamChunk{ToolCall: &llms.ToolCallChunk{
+				Index:     *toolCalls,
+				ID:        tc.Id,
+				Name:      tc.Function.Name,
+				Arguments: tc.Function.Arguments,
+			}})
+			*toolCalls++
+		}
+		if choice.FinishReason != "" {
+			chunks = append(chunks, llms.StreamChunk{StopReason: string(choice.FinishReason)})
+		}
+	}
+	if res.Usage != (sdk.UsageInfo{}) {
+		usage := usageFromInfo(res.Usage)
+		chunks = append(chunks, llms.StreamChunk{Usage: &usage})
+	}
+	return chunks
+}
+
+func usageFromInfo(info sdk.UsageInfo) llms.Usage {
+	usage := llms.NewUsage(info.PromptTokens, info.CompletionTokens)
+	if info.TotalTokens > 0 {
+		usage.TotalTokens = info.TotalTokens
+	}
+	return usage
+}
+
+// _sdkErrorRegex matches the status code of the errors of the Mistral SDK.
+var _sdkErrorRegex = regexp.MustCompile(`^\(HTTP Error (\d+)\)`)
+
+// sdkError classifies an error of the Mistral SDK, which only gives the status
+// code in the message.
+func sdkError(err error) error {
+	var statusCode int
+	if m := _sdkErrorRegex.FindStringSubmatch(err.Error()); m != nil {
+		statusCode, _ = strconv.Atoi(m[1])
+	}
+	return llms.NewError(statusCode, nil, err)
+}
+
 func convertToMistralChatMessages(langchainMessages []llms.MessageContent) ([]sdk.ChatMessage, error) {
 	messages := make([]sdk.ChatMessage, 0)
 	for _, msg := range langchainMessages {
@@ -0,0 +1,118 @@
+package mistral
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestGenerateContentUsage(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "1",
+			"object": "chat.completion",
+			"created": 1,
+			"model": "open-mistral-7b",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "yes"}, "finish_reason": "stop"}],
+			"usage": {"prompt_tokens": 10, "completion_tokens": 3, "total_tokens": 13}
+		}`))
+	}))
+	defer server.Close()
+
+	tracker := callbacks.NewUsageTracker(nil)
+	llm, err := New(WithAPIKey("test"), WithEndpoint(server.URL), WithCallbacksHandler(tracker))
+	require.NoError(t, err)
+
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Brazil is a country?"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "yes", resp.Choices[0].Content)
+
+	total := tracker.Total()
+	assert.Equal(t, 1, total.Calls)
+	assert.Equal(t, llms.NewUsage(10, 3), total.Usage)
+}
+
+func TestGenerateContentStreamingUsage(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(`data: {"id":"1","model":"open-mistral-7b","choices":[{"index":0,"delta":{"content":"ye"}}]}
+
+data: {"id":"1","model":"open-mistral-7b","choices":[{"index":0,"delta":{"content":"s"},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":3,"total_tokens":13}}
+
+data: [DONE]
+
+`))
+	}))
+	defer server.Close()
+
+	tracker := callbacks.NewUsageTracker(nil)
+	llm, err := New(WithAPIKey("test"), WithEndpoint(server.URL), WithCallbacksHandler(tracker))
+	require.NoError(t, err)
+
+	var streamed string
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Brazil is a country?"),
+	}, llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+		streamed += string(chunk)
+		return nil
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "yes", resp.Choices[0].Content)
+	assert.Equal(t, "yes", streamed)
+
+	total := tracker.Total()
+	assert.Equal(t, 1, total.Calls)
+	assert.Equal(t, llms.NewUsage(10, 3), total.Usage)
+}
+
+func TestGenerateContentStreamingChunks(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(`data: {"id":"1","model":"mistral-large-latest","choices":[{"index":0,"delta":{"role":"assistant","content":"Searching."}}]}
+
+data: {"id":"1","model":"mistral-large-latest","choices":[{"index":0,"delta":{"tool_calls":[{"id":"call_1","function":{"name":"search","arguments":"{\"q\":\"go\"}"}}]}}]}
+
+data: {"id":"1","model":"mistral-large-latest","choices":[{"index":0,"delta":{"tool_calls":[{"id":"call_2","function":{"name":"time","arguments":"{}"}}]},"finish_reason":"tool_calls"}],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}
+
+data: [DONE]
+
+`))
+	}))
+	defer server.Close()
+
+	llm, err := New(WithAPIKey("test"), WithEndpoint(server.URL))
+	require.NoError(t, err)
+
+	var chunks []llms.StreamChunk
+	_, err = llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Search for go"),
+	}, llms.WithStreamingChunkFunc(func(_ context.Context, chunk llms.StreamChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []llms.StreamChunk{
+		{Text: "Searching."},
+		{ToolCall: &llms.ToolCallChunk{Index: 0, ID: "call_1", Name: "search", Arguments: `{"q":"go"}`}},
+		{ToolCall: &llms.ToolCallChunk{Index: 1, ID: "call_2", Name: "time", Arguments: `{}`}},
+		{StopReason: "tool_calls"},
+		{Usage: &llms.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}},
+	}, chunks)
+}
@@ -13,6 +13,8 @@ import (
 	"os"
 	"runtime"
 	"strings"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 type Client struct {
@@ -33,7 +35,7 @@ func checkError(resp *http.Response, body []byte) error {
 		apiError.ErrorMessage = string(body)
 	}
 
-	return apiError
+	return llms.NewError(resp.StatusCode, resp.Header, apiError)
 }
 
 func NewClient(ourl *url.URL, ohttp *http.Client) (*Client, error) {
@@ -164,15 +166,19 @@ func (c *Client) stream(ctx context.Context, method, path string, data any, fn f
 		}
 
 		if errorResponse.Error != "" {
-			return fmt.Errorf(errorResponse.Error) //nolint
+			statusCode := 0
+			if response.StatusCode >= http.StatusBadRequest {
+				statusCode = response.StatusCode
+			}
+			return llms.NewError(statusCode, response.Header, fmt.Errorf(errorResponse.Error)) //nolint
 		}
 
 		if response.StatusCode >= http.StatusBadRequest {
-			return StatusError{
+			return llms.NewError(response.StatusCode, response.Header, StatusError{
 				StatusCode:   response.StatusCode,
 				Status:       response.Status,
 				ErrorMessage: errorResponse.Error,
-			}
+			})
 		}
 
 		if err := fn(bts); err != nil {
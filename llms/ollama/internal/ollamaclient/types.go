@@ -50,7 +50,7 @@ type ChatRequest struct {
 	Model     string     `json:"model"`
 	Messages  []*Message `json:"messages"`
 	Stream    bool       `json:"stream,omitempty"`
-	Format    string     `json:"format"`
+	Format    any        `json:"format"` // "json" or the JSON schema of the response
 	KeepAlive string     `json:"keep_alive,omitempty"`
 
 	Options Options `json:"options"`
@@ -95,7 +95,8 @@ type ChatResponse struct {
 	CreatedAt time.Time `json:"created_at"`
 	Message   *Message  `json:"message,omitempty"`
 
-	Done bool `json:"done"`
+	Done       bool   `json:"done"`
+	DoneReason string `json:"done_reason,omitempty"`
 
 	Metrics
 }
@@ -3,6 +3,8 @@ package ollama
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -76,6 +78,22 @@ func TestWithFormat(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestGenerateStructured(t *testing.T) {
+	t.Parallel()
+	llm := newTestClient(t)
+
+	type distance struct {
+		Feet float64 `json:"feet"`
+	}
+	content := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "How many feet are in a nautical mile?"),
+	}
+
+	rsp, err := llms.GenerateStructured[distance](context.Background(), llm, content)
+	require.NoError(t, err)
+	assert.InDelta(t, 6076, rsp.Feet, 10)
+}
+
 func TestWithStreaming(t *testing.T) {
 	t.Parallel()
 	llm := newTestClient(t)
@@ -104,6 +122,40 @@ func TestWithStreaming(t *testing.T) {
 	assert.Regexp(t, "feet", strings.ToLower(sb.String()))
 }
 
+func TestWithStreamingChunks(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/chat", r.URL.Path)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte(`{"model":"llama3","message":{"role":"assistant","content":"Hello"},"done":false}
+{"model":"llama3","message":{"role":"assistant","content":" world"},"done":false}
+{"model":"llama3","message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":10,"eval_count":5}
+`))
+	}))
+	defer server.Close()
+
+	llm, err := New(WithModel("llama3"), WithServerURL(server.URL))
+	require.NoError(t, err)
+
+	var chunks []llms.StreamChunk
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Say hello"),
+	}, llms.WithStreamingChunkFunc(func(_ context.Context, chunk llms.StreamChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello world", resp.Choices[0].Content)
+	// Ollama does not support tools here, so there are no tool call chunks.
+	assert.Equal(t, []llms.StreamChunk{
+		{Text: "Hello"},
+		{Text: " world"},
+		{StopReason: "stop"},
+		{Usage: &llms.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}},
+	}, chunks)
+}
+
 func TestWithKeepAlive(t *testing.T) {
 	t.Parallel()
 	llm := newTestClient(t, WithKeepAlive("1m"))
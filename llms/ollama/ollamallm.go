@@ -96,10 +96,13 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		chatMsgs = append(chatMsgs, msg)
 	}
 
-	format := o.options.format
+	var format any = o.options.format
 	if opts.JSONMode {
 		format = "json"
 	}
+	if opts.ResponseSchema != nil {
+		format = opts.ResponseSchema
+	}
 
 	// Get our ollamaOptions from llms.CallOptions
 	ollamaOptions := makeOllamaOptionsFromOptions(o.options.ollamaOptions, opts)
@@ -108,7 +111,7 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		Format:   format,
 		Messages: chatMsgs,
 		Options:  ollamaOptions,
-		Stream:   opts.StreamingFunc != nil,
+		Stream:   opts.StreamingFunc != nil || opts.StreamingChunkFunc != nil,
 	}
 
 	keepAlive := o.options.keepAlive
@@ -121,8 +124,8 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	var resp ollamaclient.ChatResponse
 
 	fn = func(response ollamaclient.ChatResponse) error {
-		if opts.StreamingFunc != nil && response.Message != nil {
-			if err := opts.StreamingFunc(ctx, []byte(response.Message.Content)); err != nil {
+		if req.Stream {
+			if err := streamChatResponse(ctx, opts, response); err != nil {
 				return err
 			}
 		}
@@ -158,7 +161,11 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		},
 	}
 
-	response := &llms.ContentResponse{Choices: choices}
+	response := &llms.ContentResponse{
+		Choices: choices,
+		Usage:   llms.NewUsage(resp.PromptEvalCount, resp.EvalCount),
+		Model:   resp.Model,
+	}
 
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
@@ -167,6 +174,33 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	return response, nil
 }
 
+// streamChatResponse sends a streamed response to the streaming functions of
+// the call. The last response holds the stop reason and the usage.
+func streamChatResponse(ctx context.Context, opts llms.CallOptions, response ollamaclient.ChatResponse) error {
+	if response.Message != nil {
+		if opts.StreamingFunc != nil {
+			if err := opts.StreamingFunc(ctx, []byte(response.Message.Content)); err != nil {
+				return err
+			}
+		}
+		if opts.StreamingChunkFunc != nil && response.Message.Content != "" {
+			if err := opts.StreamingChunkFunc(ctx, llms.StreamChunk{Text: response.Message.Content}); err != nil {
+				return err
+			}
+		}
+	}
+	if !response.Done || opts.StreamingChunkFunc == nil {
+		return nil
+	}
+	if response.DoneReason != "" {
+		if err := opts.StreamingChunkFunc(ctx, llms.StreamChunk{StopReason: response.DoneReason}); err != nil {
+			return err
+		}
+	}
+	usage := llms.NewUsage(response.PromptEvalCount, response.EvalCount)
+	return opts.StreamingChunkFunc(ctx, llms.StreamChunk{Usage: &usage})
+}
+
 func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float32, error) {
 	embeddings := [][]float32{}
 
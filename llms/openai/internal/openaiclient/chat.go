@@ -64,6 +64,9 @@ type ChatRequest struct {
 	// StreamingFunc is a function to be called for each chunk of a streaming response.
 	// Return an error to stop streaming early.
 	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+	// StreamingChunkFunc is a function to be called for each typed chunk of a
+	// streaming response. Return an error to stop streaming early.
+	StreamingChunkFunc func(ctx context.Context, chunk llms.StreamChunk) error `json:"-"`
 
 	// Deprecated: use Tools instead.
 	Functions []FunctionDefinition `json:"functions,omitempty"`
@@ -109,6 +112,16 @@ type ToolCall struct {
 // ResponseFormat is the format of the response.
 type ResponseFormat struct {
 	Type string `json:"type"`
+	// JSONSchema is the schema of the response, for the json_schema type.
+	JSONSchema *ResponseFormatJSONSchema `json:"json_schema,omitempty"`
+}
+
+// ResponseFormatJSONSchema is the schema of a response of the json_schema
+// type.
+type ResponseFormatJSONSchema struct {
+	Name   string `json:"name"`
+	Schema any    `json:"schema"`
+	Strict bool   `json:"strict,omitempty"`
 }
 
 // ChatMessage is a message in a chat request.
@@ -260,9 +273,21 @@ type ChatCompletionChoice struct {
 
 // ChatUsage is the usage of a chat completion request.
 type ChatUsage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
+	PromptTokens            int                     `json:"prompt_tokens"`
+	CompletionTokens        int                     `json:"completion_tokens"`
+	TotalTokens             int                     `json:"total_tokens"`
+	PromptTokensDetails     PromptTokensDetails     `json:"prompt_tokens_details"`
+	CompletionTokensDetails CompletionTokensDetails `json:"completion_tokens_details"`
+}
+
+// PromptTokensDetails is the breakdown of the tokens of the prompt.
+type PromptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// CompletionTokensDetails is the breakdown of the tokens of the completion.
+type CompletionTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"`
 }
 
 // ChatCompletionResponse is a response to a chat request.
@@ -276,11 +301,7 @@ type ChatCompletionResponse struct {
 	SystemFingerprint string                  `json:"system_fingerprint"`
 }
 
-type Usage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
-}
+type Usage = ChatUsage
 
 // StreamedChatResponsePayload is a chunk from the stream.
 type StreamedChatResponsePayload struct {
@@ -338,7 +359,7 @@ type FunctionCall struct {
 }
 
 func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatCompletionResponse, error) {
-	if payload.StreamingFunc != nil {
+	if payload.StreamingFunc != nil || payload.StreamingChunkFunc != nil {
 		payload.Stream = true
 		if payload.StreamOptions == nil {
 			payload.StreamOptions = &StreamOptions{IncludeUsage: true}
@@ -371,18 +392,9 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatCom
 	defer r.Body.Close()
 
 	if r.StatusCode != http.StatusOK {
-		msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
-
-		// No need to check the error here: if it fails, we'll just return the
-		// status code.
-		var errResp errorMessage
-		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
-			return nil, errors.New(msg) // nolint:goerr113
-		}
-
-		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+		return nil, decodeError(r)
 	}
-	if payload.StreamingFunc != nil {
+	if payload.Stream {
 		return parseStreamingChatResponse(ctx, r, payload)
 	}
 	// Parse response
@@ -442,16 +454,27 @@ func combineStreamingChatResponse(
 			return nil, streamResponse.Error
 		}
 
+		if streamResponse.Model != "" {
+			response.Model = streamResponse.Model
+		}
 		if streamResponse.Usage != nil {
-			response.Usage.CompletionTokens = streamResponse.Usage.CompletionTokens
-			response.Usage.PromptTokens = streamResponse.Usage.PromptTokens
-			response.Usage.TotalTokens = streamResponse.Usage.TotalTokens
+			response.Usage = *streamResponse.Usage
+			if err := sendChunk(ctx, payload, llms.StreamChunk{Usage: streamUsage(streamResponse.Usage)}); err != nil {
+				return nil, err
+			}
 		}
 
 		if len(streamResponse.Choices) == 0 {
 			continue
 		}
 		choice := streamResponse.Choices[0]
+		for _, c := range typedChunks(choice.Delta.Content, choice.Delta.FunctionCall,
+			len(response.Choices[0].Message.ToolCalls), choice.Delta.ToolCalls, choice.FinishReason) {
+			if err := sendChunk(ctx, payload, c); err != nil {
+				return nil, err
+			}
+		}
+
 		chunk := []byte(choice.Delta.Content)
 		response.Choices[0].Message.Content += choice.Delta.Content
 		response.Choices[0].FinishReason = choice.FinishReason
@@ -475,6 +498,70 @@ func combineStreamingChatResponse(
 	return &response, nil
 }
 
+// sendChunk sends a typed chunk to the StreamingChunkFunc of the payload, if
+// any. The StreamingFunc gets the legacy chunks.
+func sendChunk(ctx context.Context, payload *ChatRequest, chunk llms.StreamChunk) error {
+	if payload.StreamingChunkFunc == nil {
+		return nil
+	}
+	if err := payload.StreamingChunkFunc(ctx, chunk); err != nil {
+		return fmt.Errorf("streaming chunk func returned an error: %w", err)
+	}
+	return nil
+}
+
+// typedChunks returns the typed chunks of a streamed delta. The tool calls of
+// the delta are indexed after the n tool calls already streamed, the deltas
+// holding only arguments being appended to the last one, as in
+// updateToolCalls.
+func typedChunks(content string, functionCall *FunctionCall, n int, toolCalls []*ToolCall,
+	finishReason FinishReason,
+) []llms.StreamChunk {
+	var chunks []llms.StreamChunk
+	if content != "" {
+		chunks = append(chunks, llms.StreamChunk{Text: content})
+	}
+	if functionCall != nil {
+		chunks = append(chunks, llms.StreamChunk{ToolCall: &llms.ToolCallChunk{
+			Name:      functionCall.Name,
+			Arguments: functionCall.Arguments,
+		}})
+	}
+	for _, t := range toolCalls {
+		if t.Type == `` && t.Function.Arguments != `` {
+			if n == 0 {
+				continue
+			}
+			chunks = append(chunks, llms.StreamChunk{ToolCall: &llms.ToolCallChunk{
+				Index:     n - 1,
+				Arguments: t.Function.Arguments,
+			}})
+			continue
+		}
+		chunks = append(chunks, llms.StreamChunk{ToolCall: &llms.ToolCallChunk{
+			Index:     n,
+			ID:        t.ID,
+			Name:      t.Function.Name,
+			Arguments: t.Function.Arguments,
+		}})
+		n++
+	}
+	if finishReason != "" {
+		chunks = append(chunks, llms.StreamChunk{StopReason: string(finishReason)})
+	}
+	return chunks
+}
+
+func streamUsage(u *Usage) *llms.Usage {
+	return &llms.Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		CachedTokens:     u.PromptTokensDetails.CachedTokens,
+		ReasoningTokens:  u.CompletionTokensDetails.ReasoningTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+}
+
 func updateFunctionCall(message ChatMessage, functionCall *FunctionCall) []byte {
 	if message.FunctionCall == nil {
 		message.FunctionCall = functionCall
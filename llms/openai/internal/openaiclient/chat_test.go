@@ -7,9 +7,11 @@ import (
 	"io"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
 )
 
 func TestParseStreamingChatResponse_FinishReason(t *testing.T) {
@@ -33,6 +35,84 @@ func TestParseStreamingChatResponse_FinishReason(t *testing.T) {
 	assert.Equal(t, FinishReason("stop"), resp.Choices[0].FinishReason)
 }
 
+func TestParseStreamingChatResponse_Chunks(t *testing.T) {
+	t.Parallel()
+	mockBody := `data: {"choices":[{"index":0,"delta":{"role":"assistant","content":"hi"}}]}
+
+data: {"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"search","arguments":""}}]}}]}
+
+data: {"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"q\":"}}]}}]}
+
+data: {"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"go\"}"}}]}}]}
+
+data: {"choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}
+
+data: {"choices":[],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}
+
+data: [DONE]
+`
+	r := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(mockBody)),
+	}
+
+	var chunks []llms.StreamChunk
+	var text string
+	req := &ChatRequest{
+		Stream: true,
+		StreamingFunc: func(_ context.Context, chunk []byte) error {
+			text += string(chunk)
+			return nil
+		},
+		StreamingChunkFunc: func(_ context.Context, chunk llms.StreamChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		},
+	}
+	resp, err := parseStreamingChatResponse(context.Background(), r, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Choices[0].Message.ToolCalls, 1)
+	assert.Equal(t, `{"q":"go"}`, resp.Choices[0].Message.ToolCalls[0].Function.Arguments)
+	assert.Contains(t, text, "hi")
+
+	assert.Equal(t, []llms.StreamChunk{
+		{Text: "hi"},
+		{ToolCall: &llms.ToolCallChunk{ID: "call_1", Name: "search"}},
+		{ToolCall: &llms.ToolCallChunk{Arguments: `{"q":`}},
+		{ToolCall: &llms.ToolCallChunk{Arguments: `"go"}`}},
+		{StopReason: "tool_calls"},
+		{Usage: &llms.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}},
+	}, chunks)
+}
+
+func TestDecodeError(t *testing.T) {
+	t.Parallel()
+	r := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": []string{"3"}},
+		Body: io.NopCloser(bytes.NewBufferString(
+			`{"error":{"message":"Rate limit reached for requests","type":"requests"}}`)),
+	}
+
+	err := decodeError(r)
+
+	require.ErrorIs(t, err, llms.ErrRateLimited)
+	var providerErr *llms.Error
+	require.ErrorAs(t, err, &providerErr)
+	assert.Equal(t, 3*time.Second, providerErr.RetryAfter)
+	assert.Equal(t, "API returned unexpected status code: 429: requests: Rate limit reached for requests", err.Error())
+
+	r = &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Body: io.NopCloser(bytes.NewBufferString(
+			`{"error":{"message":"You exceeded your current quota.","type":"insufficient_quota"}}`)),
+	}
+	err = decodeError(r)
+	require.ErrorAs(t, err, &providerErr)
+	assert.Nil(t, providerErr.Kind)
+	assert.NotErrorIs(t, err, llms.ErrRateLimited)
+}
+
 func TestChatMessage_MarshalUnmarshal(t *testing.T) {
 	t.Parallel()
 	msg := ChatMessage{
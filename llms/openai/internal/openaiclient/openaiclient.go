@@ -2,10 +2,13 @@ package openaiclient
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/tmc/langchaingo/llms"
 )
 
 const (
@@ -176,3 +179,23 @@ func (c *Client) buildAzureURL(suffix string, model string) string {
 		baseURL, model, suffix, c.apiVersion,
 	)
 }
+
+// decodeError returns the error of a response with an unexpected status code,
+// classified by llms.NewError.
+func decodeError(r *http.Response) error {
+	msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
+
+	// No need to check the error here: if it fails, we'll just return the
+	// status code.
+	var errResp errorMessage
+	if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
+		return llms.NewError(r.StatusCode, r.Header, errors.New(msg)) // nolint:goerr113
+	}
+
+	if errResp.Error.Type != "" {
+		// The type holds the code of the error, such as insufficient_quota,
+		// which llms.NewError classifies more reliably than the message.
+		msg = fmt.Sprintf("%s: %s", msg, errResp.Error.Type)
+	}
+	return llms.NewError(r.StatusCode, r.Header, fmt.Errorf("%s: %s", msg, errResp.Error.Message)) // nolint:goerr113
+}
@@ -24,6 +24,10 @@ const (
 	RoleTool      = "tool"
 )
 
+// _responseSchemaName is the name of the schemas set with
+// llms.WithResponseSchema, which OpenAI requires.
+const _responseSchemaName = "response"
+
 var _ llms.Model = (*LLM)(nil)
 
 // New returns a new OpenAI LLM.
@@ -96,15 +100,16 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 		chatMsgs = append(chatMsgs, msg)
 	}
 	req := &openaiclient.ChatRequest{
-		Model:            opts.Model,
-		StopWords:        opts.StopWords,
-		Messages:         chatMsgs,
-		StreamingFunc:    opts.StreamingFunc,
-		Temperature:      opts.Temperature,
-		MaxTokens:        opts.MaxTokens,
-		N:                opts.N,
-		FrequencyPenalty: opts.FrequencyPenalty,
-		PresencePenalty:  opts.PresencePenalty,
+		Model:              opts.Model,
+		StopWords:          opts.StopWords,
+		Messages:           chatMsgs,
+		StreamingFunc:      opts.StreamingFunc,
+		StreamingChunkFunc: opts.StreamingChunkFunc,
+		Temperature:        opts.Temperature,
+		MaxTokens:          opts.MaxTokens,
+		N:                  opts.N,
+		FrequencyPenalty:   opts.FrequencyPenalty,
+		PresencePenalty:    opts.PresencePenalty,
 
 		ToolChoice:           opts.ToolChoice,
 		FunctionCallBehavior: openaiclient.FunctionCallBehavior(opts.FunctionCallBehavior),
@@ -114,6 +119,15 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 	if opts.JSONMode {
 		req.ResponseFormat = ResponseFormatJSON
 	}
+	if opts.ResponseSchema != nil {
+		req.ResponseFormat = &ResponseFormat{
+			Type: "json_schema",
+			JSONSchema: &ResponseFormatJSONSchema{
+				Name:   _responseSchemaName,
+				Schema: opts.ResponseSchema,
+			},
+		}
+	}
 
 	// since req.Functions is deprecated, we need to use the new Tools API.
 	for _, fn := range opts.Functions {
@@ -177,7 +191,17 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 			choices[i].FuncCall = choices[i].ToolCalls[0].FunctionCall
 		}
 	}
-	response := &llms.ContentResponse{Choices: choices}
+	response := &llms.ContentResponse{
+		Choices: choices,
+		Usage: llms.Usage{
+			PromptTokens:     result.Usage.PromptTokens,
+			CompletionTokens: result.Usage.CompletionTokens,
+			CachedTokens:     result.Usage.PromptTokensDetails.CachedTokens,
+			ReasoningTokens:  result.Usage.CompletionTokensDetails.ReasoningTokens,
+			TotalTokens:      result.Usage.TotalTokens,
+		},
+		Model: result.Model,
+	}
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
 	}
@@ -51,6 +51,10 @@ type ResponseFormat = openaiclient.ResponseFormat
 // ResponseFormatJSON is the JSON response format.
 var ResponseFormatJSON = &ResponseFormat{Type: "json_object"} //nolint:gochecknoglobals
 
+// ResponseFormatJSONSchema is the schema of a response of the json_schema
+// type.
+type ResponseFormatJSONSchema = openaiclient.ResponseFormatJSONSchema
+
 // WithToken passes the OpenAI API token to the client. If not set, the token
 // is read from the OPENAI_API_KEY environment variable.
 func WithToken(token string) Option {
@@ -0,0 +1,52 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestResponseSchema(t *testing.T) {
+	t.Parallel()
+
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{\"name\":\"Paris\"}"}}]}`))
+	}))
+	defer srv.Close()
+
+	llm, err := New(WithToken("test"), WithBaseURL(srv.URL), WithModel("gpt-4o"))
+	require.NoError(t, err)
+
+	schema := jsonschema.Definition{
+		Type:       jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{"name": {Type: jsonschema.String}},
+		Required:   []string{"name"},
+	}
+	resp, err := llm.GenerateContent(context.Background(),
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Capital of France?")},
+		llms.WithResponseSchema(schema))
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"Paris"}`, resp.Choices[0].Content)
+
+	assert.Equal(t, map[string]any{
+		"type": "json_schema",
+		"json_schema": map[string]any{
+			"name": "response",
+			"schema": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"name": map[string]any{"type": "string", "properties": map[string]any{}}},
+				"required":   []any{"name"},
+			},
+		},
+	}, body["response_format"])
+}
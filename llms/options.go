@@ -1,6 +1,10 @@
 package llms
 
-import "context"
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/jsonschema"
+)
 
 // CallOption is a function that configures a CallOptions.
 type CallOption func(*CallOptions)
@@ -21,6 +25,9 @@ type CallOptions struct {
 	// StreamingFunc is a function to be called for each chunk of a streaming response.
 	// Return an error to stop streaming early.
 	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+	// StreamingChunkFunc is a function to be called for each typed chunk of a
+	// streaming response. Return an error to stop streaming early.
+	StreamingChunkFunc func(ctx context.Context, chunk StreamChunk) error `json:"-"`
 	// TopK is the number of tokens to consider for top-k sampling.
 	TopK int `json:"top_k"`
 	// TopP is the cumulative probability for top-p sampling.
@@ -42,6 +49,8 @@ type CallOptions struct {
 
 	// JSONMode is a flag to enable JSON mode.
 	JSONMode bool `json:"json"`
+	// ResponseSchema is the schema the JSON response must match.
+	ResponseSchema *jsonschema.Definition `json:"response_schema,omitempty"`
 
 	// Tools is a list of tools to use. Each tool can be a specific tool or a function.
 	Tools []Tool `json:"tools,omitempty"`
@@ -155,6 +164,15 @@ func WithStreamingFunc(streamingFunc func(ctx context.Context, chunk []byte) err
 	}
 }
 
+// WithStreamingChunkFunc specifies the streaming function receiving typed
+// chunks: deltas of the text and of the tool calls, the stop reason and the
+// usage. It can be used along with WithStreamingFunc.
+func WithStreamingChunkFunc(chunkFunc func(ctx context.Context, chunk StreamChunk) error) CallOption {
+	return func(o *CallOptions) {
+		o.StreamingChunkFunc = chunkFunc
+	}
+}
+
 // WithTopK will add an option to use top-k sampling.
 func WithTopK(topK int) CallOption {
 	return func(o *CallOptions) {
@@ -258,6 +276,21 @@ func WithJSONMode() CallOption {
 	}
 }
 
+// WithResponseSchema will add an option to make the model respond with JSON
+// matching the schema. Each provider maps it to its native mechanism. The
+// providers without one force the model to call the StructuredOutputTool if
+// they support tools, and otherwise add the schema to the prompt with
+// AppendResponseSchemaPrompt, which the model is not bound to follow. The JSON
+// is returned as the content of the response either way.
+//
+// The response is not validated, see GenerateStructured for validated and
+// decoded responses.
+func WithResponseSchema(schema jsonschema.Definition) CallOption {
+	return func(o *CallOptions) {
+		o.ResponseSchema = &schema
+	}
+}
+
 // WithMetadata will add an option to set metadata to include in the request.
 // The meaning of this field is specific to the backend in use.
 func WithMetadata(metadata map[string]interface{}) CallOption {
@@ -0,0 +1,96 @@
+package llms
+
+import "strings"
+
+// _tokensPerPriceUnit is the number of tokens prices are given for.
+const _tokensPerPriceUnit = 1_000_000
+
+// Price is the price of the tokens of a model, in dollars per million tokens.
+type Price struct {
+	// Prompt is the price of the prompt tokens.
+	Prompt float64
+	// CachedPrompt is the price of the prompt tokens read from the cache. Zero
+	// means the same price as Prompt.
+	CachedPrompt float64
+	// Completion is the price of the completion tokens, reasoning included.
+	Completion float64
+}
+
+// Cost returns the cost in dollars of a usage.
+func (p Price) Cost(u Usage) float64 {
+	cachedPrice := p.CachedPrompt
+	if cachedPrice == 0 {
+		cachedPrice = p.Prompt
+	}
+	cost := float64(u.PromptTokens-u.CachedTokens)*p.Prompt +
+		float64(u.CachedTokens)*cachedPrice +
+		float64(u.CompletionTokens)*p.Completion
+	return cost / _tokensPerPriceUnit
+}
+
+// PriceTable maps model names to their price. A name also matches the models
+// it is a prefix of, such as "gpt-4o" for "gpt-4o-2024-08-06", the longest
+// matching name winning.
+type PriceTable map[string]Price
+
+// Price returns the price of a model.
+func (t PriceTable) Price(model string) (Price, bool) {
+	if price, ok := t[model]; ok {
+		return price, true
+	}
+	var (
+		price   Price
+		longest string
+	)
+	for name, p := range t {
+		if len(name) > len(longest) && strings.HasPrefix(model, name) {
+			price, longest = p, name
+		}
+	}
+	return price, longest != ""
+}
+
+// Cost returns the cost in dollars of the usage of a model, and false if the
+// model has no price.
+func (t PriceTable) Cost(model string, u Usage) (float64, bool) {
+	price, ok := t.Price(model)
+	if !ok {
+		return 0, false
+	}
+	return price.Cost(u), true
+}
+
+// DefaultPrices holds the list prices of common models. Prices change and
+// differ between regions and contracts, so copy it and adjust it to your own
+// rates when the cost must be exact.
+//
+// nolint:gochecknoglobals
+var DefaultPrices = PriceTable{
+	// OpenAI
+	"gpt-4o":        {Prompt: 2.5, CachedPrompt: 1.25, Completion: 10},
+	"gpt-4o-mini":   {Prompt: 0.15, CachedPrompt: 0.075, Completion: 0.6},
+	"gpt-4-turbo":   {Prompt: 10, Completion: 30},
+	"gpt-4":         {Prompt: 30, Completion: 60},
+	"gpt-4-32k":     {Prompt: 60, Completion: 120},
+	"gpt-3.5-turbo": {Prompt: 0.5, Completion: 1.5},
+	"o1":            {Prompt: 15, CachedPrompt: 7.5, Completion: 60},
+	"o1-mini":       {Prompt: 1.1, CachedPrompt: 0.55, Completion: 4.4},
+	"o3-mini":       {Prompt: 1.1, CachedPrompt: 0.55, Completion: 4.4},
+
+	// Anthropic
+	"claude-3-5-sonnet": {Prompt: 3, CachedPrompt: 0.3, Completion: 15},
+	"claude-3-5-haiku":  {Prompt: 0.8, CachedPrompt: 0.08, Completion: 4},
+	"claude-3-opus":     {Prompt: 15, CachedPrompt: 1.5, Completion: 75},
+	"claude-3-sonnet":   {Prompt: 3, CachedPrompt: 0.3, Completion: 15},
+	"claude-3-haiku":    {Prompt: 0.25, CachedPrompt: 0.03, Completion: 1.25},
+
+	// Google AI
+	"gemini-1.5-pro":   {Prompt: 1.25, Completion: 5},
+	"gemini-1.5-flash": {Prompt: 0.075, Completion: 0.3},
+	"gemini-1.0-pro":   {Prompt: 0.5, Completion: 1.5},
+
+	// Mistral
+	"mistral-large": {Prompt: 2, Completion: 6},
+	"mistral-small": {Prompt: 0.2, Completion: 0.6},
+	"open-mistral":  {Prompt: 0.25, Completion: 0.25},
+}
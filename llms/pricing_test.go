@@ -0,0 +1,59 @@
+package llms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceTableCost(t *testing.T) {
+	t.Parallel()
+
+	prices := PriceTable{
+		"gpt-4":       {Prompt: 30, Completion: 60},
+		"gpt-4o":      {Prompt: 2, CachedPrompt: 1, Completion: 10},
+		"gpt-4o-mini": {Prompt: 0.5, Completion: 1},
+	}
+
+	tests := []struct {
+		name   string
+		model  string
+		usage  Usage
+		cost   float64
+		priced bool
+	}{
+		{"exact", "gpt-4", NewUsage(1000, 500), 0.06, true},
+		{"longest prefix", "gpt-4o-2024-08-06", NewUsage(1_000_000, 0), 2, true},
+		{"nested prefix", "gpt-4o-mini-2024-07-18", NewUsage(0, 1_000_000), 1, true},
+		{
+			"cached tokens", "gpt-4o",
+			Usage{PromptTokens: 1_000_000, CachedTokens: 400_000, CompletionTokens: 100_000},
+			0.6*2 + 0.4*1 + 0.1*10, true,
+		},
+		{"unknown model", "claude-3-haiku", NewUsage(10, 10), 0, false},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cost, ok := prices.Cost(tc.model, tc.usage)
+			assert.Equal(t, tc.priced, ok)
+			assert.InDelta(t, tc.cost, cost, 1e-9)
+		})
+	}
+}
+
+func TestUsageAdd(t *testing.T) {
+	t.Parallel()
+
+	u := NewUsage(10, 5).Add(Usage{PromptTokens: 3, CachedTokens: 2, ReasoningTokens: 1, TotalTokens: 3})
+	assert.Equal(t, Usage{
+		PromptTokens:     13,
+		CompletionTokens: 5,
+		CachedTokens:     2,
+		ReasoningTokens:  1,
+		TotalTokens:      18,
+	}, u)
+	assert.False(t, u.IsZero())
+	assert.True(t, Usage{}.IsZero())
+}
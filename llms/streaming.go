@@ -0,0 +1,54 @@
+package llms
+
+import "context"
+
+// StreamChunk is a typed chunk of a streaming response, passed to the function
+// set with WithStreamingChunkFunc. A chunk holds a single kind of event: a
+// delta of the text, a delta of a tool call, the stop reason or the usage of
+// the call.
+type StreamChunk struct {
+	// Text is a delta of the text of the response.
+	Text string
+	// ToolCall is a delta of a tool call.
+	ToolCall *ToolCallChunk
+	// StopReason is the reason the model stopped generating, as reported by
+	// the provider.
+	StopReason string
+	// Usage is the usage of the call, sent once the provider reports it.
+	Usage *Usage
+}
+
+// ToolCallChunk is a delta of a tool call. The first delta of a tool call holds
+// its ID and name, the next ones hold fragments of its arguments to append.
+type ToolCallChunk struct {
+	// Index is the index of the tool call in the response, telling apart the
+	// tool calls streamed in parallel.
+	Index int
+	// ID is the ID of the tool call.
+	ID string
+	// Name is the name of the function called.
+	Name string
+	// Arguments is a fragment of the JSON arguments of the call.
+	Arguments string
+}
+
+// SendStreamChunk sends a chunk to the streaming functions of a call. The chunk
+// goes to chunkFunc, and its text to streamingFunc, which keeps the functions
+// set with WithStreamingFunc working for the providers streaming typed chunks.
+// Either function can be nil.
+func SendStreamChunk(
+	ctx context.Context,
+	streamingFunc func(ctx context.Context, chunk []byte) error,
+	chunkFunc func(ctx context.Context, chunk StreamChunk) error,
+	chunk StreamChunk,
+) error {
+	if streamingFunc != nil && chunk.Text != "" {
+		if err := streamingFunc(ctx, []byte(chunk.Text)); err != nil {
+			return err
+		}
+	}
+	if chunkFunc != nil {
+		return chunkFunc(ctx, chunk)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendStreamChunk(t *testing.T) {
+	t.Parallel()
+
+	var bytes []string
+	var chunks []StreamChunk
+	streamingFunc := func(_ context.Context, chunk []byte) error {
+		bytes = append(bytes, string(chunk))
+		return nil
+	}
+	chunkFunc := func(_ context.Context, chunk StreamChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}
+
+	ctx := context.Background()
+	require.NoError(t, SendStreamChunk(ctx, streamingFunc, chunkFunc, StreamChunk{Text: "hello"}))
+	require.NoError(t, SendStreamChunk(ctx, streamingFunc, chunkFunc, StreamChunk{StopReason: "stop"}))
+	require.NoError(t, SendStreamChunk(ctx, nil, nil, StreamChunk{Text: "ignored"}))
+
+	assert.Equal(t, []string{"hello"}, bytes)
+	assert.Equal(t, []StreamChunk{{Text: "hello"}, {StopReason: "stop"}}, chunks)
+
+	errStop := errors.New("stop")
+	err := SendStreamChunk(ctx, func(context.Context, []byte) error { return errStop }, chunkFunc,
+		StreamChunk{Text: "hello"})
+	require.ErrorIs(t, err, errStop)
+	assert.Len(t, chunks, 2)
+}
@@ -0,0 +1,189 @@
+package llms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/jsonschema"
+)
+
+// StructuredOutputToolName is the name of the tool the providers with tools
+// but without native support for response schemas force the model to call. The arguments
+// of the call are the response.
+const StructuredOutputToolName = "structured_output"
+
+// _structuredOutputRepairs is the number of times GenerateStructured asks the
+// model to repair a response that does not match the schema.
+const _structuredOutputRepairs = 1
+
+// _structuredOutputValue is the property of the object GenerateStructured asks
+// for when the schema is not of type object.
+const _structuredOutputValue = "value"
+
+const _structuredOutputRepairPrompt = `Your response does not match the JSON schema: %v
+
+Respond again with only JSON matching this schema:
+%s`
+
+const _responseSchemaPrompt = `Respond with only JSON matching this JSON schema:
+%s`
+
+// AppendResponseSchemaPrompt appends instructions to respond with JSON
+// matching the schema to a prompt. It is used by the providers with neither
+// native support for response schemas nor tools. The prompt is returned as is
+// if the schema is nil.
+func AppendResponseSchemaPrompt(prompt string, schema *jsonschema.Definition) (string, error) {
+	if schema == nil {
+		return prompt, nil
+	}
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return "", err
+	}
+	return prompt + "\n\n" + fmt.Sprintf(_responseSchemaPrompt, schemaJSON), nil
+}
+
+// StructuredOutputTool returns the tool the providers with tools but without
+// native support for response schemas force the model to call, with the
+// response as arguments. As the arguments of tools, the schema must be of type object.
+func StructuredOutputTool(schema jsonschema.Definition) Tool {
+	return Tool{
+		Type: "function",
+		Function: &FunctionDefinition{
+			Name:        StructuredOutputToolName,
+			Description: "Responds to the user with JSON. Always call this tool to respond.",
+			Parameters:  schema,
+		},
+	}
+}
+
+// StructuredOutput returns the JSON response of a choice: the arguments of a
+// call to the StructuredOutputTool, or else the content without the markdown
+// code fence models sometimes wrap JSON in.
+func StructuredOutput(choice *ContentChoice) string {
+	for _, tc := range choice.ToolCalls {
+		if tc.FunctionCall != nil && tc.FunctionCall.Name == StructuredOutputToolName {
+			return tc.FunctionCall.Arguments
+		}
+	}
+
+	content := strings.TrimSpace(choice.Content)
+	if strings.HasPrefix(content, "```") {
+		content = strings.TrimPrefix(content, "```json")
+		content = strings.TrimPrefix(content, "```")
+		content = strings.TrimSuffix(content, "```")
+	}
+	return strings.TrimSpace(content)
+}
+
+// SetStructuredOutputContent sets the arguments of the calls to the
+// StructuredOutputTool as the content of their choices, and removes the calls.
+// It is used by the providers forcing the model to call the tool, so that the
+// response is the content either way.
+func SetStructuredOutputContent(choices []*ContentChoice) {
+	for _, choice := range choices {
+		if choice.FuncCall != nil && choice.FuncCall.Name == StructuredOutputToolName {
+			choice.Content = choice.FuncCall.Arguments
+			choice.FuncCall = nil
+		}
+		for i, tc := range choice.ToolCalls {
+			if tc.FunctionCall != nil && tc.FunctionCall.Name == StructuredOutputToolName {
+				choice.Content = tc.FunctionCall.Arguments
+				choice.ToolCalls = append(choice.ToolCalls[:i:i], choice.ToolCalls[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// GenerateStructured generates a response with a schema and decodes it into a
+// value of type T. The schema is generated from T, as described for
+// jsonschema.GenerateSchemaForType, unless one is set with WithResponseSchema.
+// As providers only accept objects as response, a schema of another type, such
+// as the one of a slice, is asked for as the value property of an object.
+//
+// The response is validated against the schema. A response that does not
+// match is sent back to the model along with the violation for it to repair
+// it, and an error wrapping jsonschema.ErrInvalidValue is returned if the
+// repaired response does not match either.
+func GenerateStructured[T any](ctx context.Context, llm Model, messages []MessageContent, options ...CallOption) (T, error) { //nolint:lll
+	var value T
+	var opts CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.ResponseSchema == nil {
+		schema, err := jsonschema.GenerateSchemaForType(value)
+		if err != nil {
+			return value, err
+		}
+		opts.ResponseSchema = schema
+	}
+
+	schema := *opts.ResponseSchema
+	wrapped := schema.Type != jsonschema.Object
+	if wrapped {
+		schema = jsonschema.Definition{
+			Type:       jsonschema.Object,
+			Properties: map[string]jsonschema.Definition{_structuredOutputValue: schema},
+			Required:   []string{_structuredOutputValue},
+		}
+	}
+	options = append(options[:len(options):len(options)], WithResponseSchema(schema))
+
+	output, err := generateStructuredOutput(ctx, llm, messages, schema, options)
+	if err != nil {
+		return value, err
+	}
+	if wrapped {
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(output), &object); err != nil {
+			return value, fmt.Errorf("decode structured output: %w", err)
+		}
+		output = string(object[_structuredOutputValue])
+	}
+	if err := json.Unmarshal([]byte(output), &value); err != nil {
+		return value, fmt.Errorf("decode structured output: %w", err)
+	}
+	return value, nil
+}
+
+// generateStructuredOutput generates a JSON response matching the schema,
+// asking the model to repair the responses that do not.
+func generateStructuredOutput(
+	ctx context.Context,
+	llm Model,
+	messages []MessageContent,
+	schema jsonschema.Definition,
+	options []CallOption,
+) (string, error) {
+	for repairs := 0; ; repairs++ {
+		resp, err := llm.GenerateContent(ctx, messages, options...)
+		if err != nil {
+			return "", err
+		}
+		if len(resp.Choices) == 0 {
+			return "", ErrEmptyResponse
+		}
+
+		output := StructuredOutput(resp.Choices[0])
+		violation := jsonschema.ValidateJSON(schema, []byte(output))
+		if violation == nil {
+			return output, nil
+		}
+		if repairs >= _structuredOutputRepairs {
+			return "", fmt.Errorf("structured output: %w", violation)
+		}
+
+		schemaJSON, err := json.Marshal(schema)
+		if err != nil {
+			return "", err
+		}
+		messages = append(messages[:len(messages):len(messages)],
+			TextParts(ChatMessageTypeAI, output),
+			TextParts(ChatMessageTypeHuman, fmt.Sprintf(_structuredOutputRepairPrompt, violation, schemaJSON)),
+		)
+	}
+}
@@ -0,0 +1,190 @@
+package llms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/jsonschema"
+)
+
+// structuredModel returns its responses in order and records the calls.
+type structuredModel struct {
+	responses []*ContentChoice
+	messages  [][]MessageContent
+	opts      []CallOptions
+}
+
+func (m *structuredModel) Call(ctx context.Context, prompt string, options ...CallOption) (string, error) {
+	return GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *structuredModel) GenerateContent(_ context.Context, messages []MessageContent, options ...CallOption) (*ContentResponse, error) { //nolint:lll
+	var opts CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	m.messages = append(m.messages, messages)
+	m.opts = append(m.opts, opts)
+	choice := m.responses[0]
+	m.responses = m.responses[1:]
+	return &ContentResponse{Choices: []*ContentChoice{choice}}, nil
+}
+
+type city struct {
+	Name       string `json:"name"`
+	Population int    `json:"population"`
+}
+
+func TestGenerateStructured(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		responses []*ContentChoice
+		want      city
+		calls     int
+		wantErr   error
+	}{
+		{
+			name:      "valid response",
+			responses: []*ContentChoice{{Content: `{"name":"Paris","population":2100000}`}},
+			want:      city{Name: "Paris", Population: 2100000},
+			calls:     1,
+		},
+		{
+			name:      "code fence",
+			responses: []*ContentChoice{{Content: "```json\n{\"name\":\"Paris\",\"population\":1}\n```"}},
+			want:      city{Name: "Paris", Population: 1},
+			calls:     1,
+		},
+		{
+			name: "structured output tool",
+			responses: []*ContentChoice{{ToolCalls: []ToolCall{{FunctionCall: &FunctionCall{
+				Name:      StructuredOutputToolName,
+				Arguments: `{"name":"Paris","population":1}`,
+			}}}}},
+			want:  city{Name: "Paris", Population: 1},
+			calls: 1,
+		},
+		{
+			name: "repairs violations",
+			responses: []*ContentChoice{
+				{Content: `{"name":"Paris"}`},
+				{Content: `{"name":"Paris","population":1}`},
+			},
+			want:  city{Name: "Paris", Population: 1},
+			calls: 2,
+		},
+		{
+			name: "gives up after a repair",
+			responses: []*ContentChoice{
+				{Content: `{"name":"Paris"}`},
+				{Content: `not json`},
+			},
+			calls:   2,
+			wantErr: jsonschema.ErrInvalidValue,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			llm := &structuredModel{responses: tc.responses}
+			got, err := GenerateStructured[city](context.Background(), llm,
+				[]MessageContent{TextParts(ChatMessageTypeHuman, "Largest city of France?")})
+			require.Len(t, llm.messages, tc.calls)
+			require.NotNil(t, llm.opts[0].ResponseSchema)
+			assert.Equal(t, []string{"name", "population"}, llm.opts[0].ResponseSchema.Required)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestGenerateStructuredRepairMessages(t *testing.T) {
+	t.Parallel()
+
+	llm := &structuredModel{responses: []*ContentChoice{
+		{Content: `{"name":"Paris"}`},
+		{Content: `{"name":"Paris","population":1}`},
+	}}
+	schema := jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"name":       {Type: jsonschema.String},
+			"population": {Type: jsonschema.Integer},
+		},
+		Required: []string{"name", "population"},
+	}
+	_, err := GenerateStructured[map[string]any](context.Background(), llm,
+		[]MessageContent{TextParts(ChatMessageTypeHuman, "Largest city of France?")},
+		WithResponseSchema(schema))
+	require.NoError(t, err)
+
+	assert.Equal(t, &schema, llm.opts[0].ResponseSchema)
+	repair := llm.messages[1]
+	require.Len(t, repair, 3)
+	assert.Equal(t, TextParts(ChatMessageTypeAI, `{"name":"Paris"}`), repair[1])
+	assert.Contains(t, repair[2].Parts[0].(TextContent).Text, "$.population is required")
+}
+
+func TestSetStructuredOutputContent(t *testing.T) {
+	t.Parallel()
+
+	structured := &FunctionCall{Name: StructuredOutputToolName, Arguments: `{"name":"Paris"}`}
+	search := ToolCall{ID: "call_2", FunctionCall: &FunctionCall{Name: "search", Arguments: `{}`}}
+	choices := []*ContentChoice{
+		{ToolCalls: []ToolCall{{ID: "call_1", FunctionCall: structured}, search}, FuncCall: structured},
+		{Content: "unchanged", ToolCalls: []ToolCall{search}},
+	}
+	SetStructuredOutputContent(choices)
+
+	assert.Equal(t, []*ContentChoice{
+		{Content: `{"name":"Paris"}`, ToolCalls: []ToolCall{search}},
+		{Content: "unchanged", ToolCalls: []ToolCall{search}},
+	}, choices)
+}
+
+func TestAppendResponseSchemaPrompt(t *testing.T) {
+	t.Parallel()
+
+	prompt, err := AppendResponseSchemaPrompt("Describe Paris.", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Describe Paris.", prompt)
+
+	schema := jsonschema.Definition{
+		Type:       jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{"name": {Type: jsonschema.String}},
+	}
+	prompt, err = AppendResponseSchemaPrompt("Describe Paris.", &schema)
+	require.NoError(t, err)
+	assert.Equal(t, "Describe Paris.\n\nRespond with only JSON matching this JSON schema:\n"+
+		`{"type":"object","properties":{"name":{"type":"string","properties":{}}}}`, prompt)
+}
+
+func TestGenerateStructuredNonObject(t *testing.T) {
+	t.Parallel()
+
+	llm := &structuredModel{responses: []*ContentChoice{
+		{Content: `{"value":"Paris"}`},
+		{Content: `{"value":["Paris","Lyon"]}`},
+	}}
+	got, err := GenerateStructured[[]string](context.Background(), llm,
+		[]MessageContent{TextParts(ChatMessageTypeHuman, "Largest cities of France?")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Paris", "Lyon"}, got)
+
+	require.Len(t, llm.opts, 2)
+	schema := llm.opts[0].ResponseSchema
+	require.NotNil(t, schema)
+	assert.Equal(t, jsonschema.Object, schema.Type)
+	assert.Equal(t, []string{"value"}, schema.Required)
+	assert.Equal(t, jsonschema.Array, schema.Properties["value"].Type)
+}
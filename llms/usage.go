@@ -0,0 +1,45 @@
+package llms
+
+// Usage is the number of tokens used by a GenerateContent call, as reported by
+// the provider. Providers that do not report usage leave it zero.
+type Usage struct {
+	// PromptTokens is the number of tokens of the prompt.
+	PromptTokens int
+	// CompletionTokens is the number of tokens generated.
+	CompletionTokens int
+	// CachedTokens is the part of PromptTokens read from the prompt cache of
+	// the provider.
+	CachedTokens int
+	// ReasoningTokens is the part of CompletionTokens the model used for
+	// reasoning, which is not part of the content.
+	ReasoningTokens int
+	// TotalTokens is the total number of tokens used. Providers that do not
+	// report it get the sum of PromptTokens and CompletionTokens.
+	TotalTokens int
+}
+
+// NewUsage returns the usage of a call with the given prompt and completion
+// tokens.
+func NewUsage(promptTokens, completionTokens int) Usage {
+	return Usage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		TotalTokens:      promptTokens + completionTokens,
+	}
+}
+
+// Add returns the sum of two usages.
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		CachedTokens:     u.CachedTokens + other.CachedTokens,
+		ReasoningTokens:  u.ReasoningTokens + other.ReasoningTokens,
+		TotalTokens:      u.TotalTokens + other.TotalTokens,
+	}
+}
+
+// IsZero tells whether no usage was reported.
+func (u Usage) IsZero() bool {
+	return u == Usage{}
+}
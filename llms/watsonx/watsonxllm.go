@@ -6,6 +6,7 @@ import (
 
 	wx "github.com/IBM/watsonx-go/pkg/models"
 	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/jsonschema"
 	"github.com/tmc/langchaingo/llms"
 )
 
@@ -16,11 +17,16 @@ var (
 
 type LLM struct {
 	CallbacksHandler callbacks.Handler
-	client           *wx.Client
+	client           textGenerator
 
 	modelID string
 }
 
+// textGenerator is the part of the watsonx client used by the LLM.
+type textGenerator interface {
+	GenerateText(model, prompt string, options ...wx.GenerateOption) (wx.GenerateTextResult, error)
+}
+
 var _ llms.Model = (*LLM)(nil)
 
 // Call implements the LLM interface.
@@ -35,7 +41,12 @@ func (wx *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConte
 		wx.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
 	}
 
-	prompt, err := getPrompt(messages)
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	prompt, err := getPrompt(messages, opts.ResponseSchema)
 	if err != nil {
 		return nil, err
 	}
@@ -62,6 +73,12 @@ func (wx *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConte
 				Content: result.Text,
 			},
 		},
+		Usage: llms.NewUsage(result.InputTokenCount, result.GeneratedTokenCount),
+		Model: wx.modelID,
+	}
+
+	if wx.CallbacksHandler != nil {
+		wx.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
 	}
 	return resp, nil
 }
@@ -78,7 +95,9 @@ func New(modelID string, opts ...wx.ClientOption) (*LLM, error) {
 	}, nil
 }
 
-func getPrompt(messages []llms.MessageContent) (string, error) {
+// getPrompt returns the prompt of the messages, with the response schema if
+// any, as watsonx has no native support for response schemas.
+func getPrompt(messages []llms.MessageContent, schema *jsonschema.Definition) (string, error) {
 	// Assume we get a single text message
 	msg0 := messages[0]
 	part := msg0.Parts[0]
@@ -87,7 +106,7 @@ func getPrompt(messages []llms.MessageContent) (string, error) {
 		return "", ErrInvalidPrompt
 	}
 
-	return prompt.Text, nil
+	return llms.AppendResponseSchemaPrompt(prompt.Text, schema)
 }
 
 func getDefaultCallOptions() *llms.CallOptions {
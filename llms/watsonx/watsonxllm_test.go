@@ -0,0 +1,73 @@
+package watsonx
+
+import (
+	"context"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/llms"
+)
+
+type fakeClient struct {
+	result wx.GenerateTextResult
+	prompt *string
+}
+
+func (c fakeClient) GenerateText(_ string, prompt string, _ ...wx.GenerateOption) (wx.GenerateTextResult, error) {
+	if c.prompt != nil {
+		*c.prompt = prompt
+	}
+	return c.result, nil
+}
+
+func TestGenerateContentUsage(t *testing.T) {
+	t.Parallel()
+
+	tracker := callbacks.NewUsageTracker(nil)
+	llm := &LLM{
+		CallbacksHandler: tracker,
+		client: fakeClient{result: wx.GenerateTextResult{
+			Text:                "yes",
+			InputTokenCount:     10,
+			GeneratedTokenCount: 3,
+		}},
+		modelID: "ibm/granite-13b-chat-v2",
+	}
+
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Brazil is a country?"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "yes", resp.Choices[0].Content)
+
+	total := tracker.Total()
+	assert.Equal(t, 1, total.Calls)
+	assert.Equal(t, llms.NewUsage(10, 3), total.Usage)
+}
+
+func TestGenerateContentResponseSchema(t *testing.T) {
+	t.Parallel()
+
+	var prompt string
+	llm := &LLM{
+		client:  fakeClient{result: wx.GenerateTextResult{Text: `{"name":"Paris"}`}, prompt: &prompt},
+		modelID: "ibm/granite-13b-chat-v2",
+	}
+	schema := jsonschema.Definition{
+		Type:       jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{"name": {Type: jsonschema.String}},
+	}
+
+	resp, err := llm.GenerateContent(context.Background(), []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "Name the capital of France."),
+	}, llms.WithResponseSchema(schema))
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"Paris"}`, resp.Choices[0].Content)
+	expected, err := llms.AppendResponseSchemaPrompt("Name the capital of France.", &schema)
+	require.NoError(t, err)
+	assert.Equal(t, expected, prompt)
+}
@@ -0,0 +1,62 @@
+package outputparser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// _numberedItemRegex matches the start of an item of a numbered list, like
+// "1." or "2)", optionally in bold or prefixed with "Step".
+var _numberedItemRegex = regexp.MustCompile(`^\s*(?:\*\*)?(?:[Ss]tep\s*)?(\d+)[.):](?:\*\*)?\s+`)
+
+// NumberedList is an output parser used to parse the output of an LLM as a
+// string slice. Every line starting with a number followed by a dot or a
+// parenthesis starts a new item. Lines that do not start an item are added to
+// the previous item, and text before the first item is ignored.
+type NumberedList struct{}
+
+// NewNumberedList creates a new NumberedList.
+func NewNumberedList() NumberedList {
+	return NumberedList{}
+}
+
+// Statically assert that NumberedList implement the OutputParser interface.
+var _ schema.OutputParser[[]string] = NumberedList{}
+
+// GetFormatInstructions returns the format instruction.
+func (p NumberedList) GetFormatInstructions() string {
+	return "Your response should be a numbered list with one item per line, eg:\n1. foo\n2. bar\n3. baz"
+}
+
+// Parse parses the output of an LLM into a string slice.
+func (p NumberedList) Parse(text string) ([]string, error) {
+	var items []string
+	for _, line := range strings.Split(text, "\n") {
+		if loc := _numberedItemRegex.FindStringIndex(line); loc != nil {
+			items = append(items, strings.TrimSpace(line[loc[1]:]))
+			continue
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || len(items) == 0 {
+			continue
+		}
+		items[len(items)-1] += "\n" + line
+	}
+
+	if len(items) == 0 {
+		return nil, ParseError{Text: text, Reason: "no numbered list in output"}
+	}
+	return items, nil
+}
+
+// ParseWithPrompt with prompts does the same as Parse.
+func (p NumberedList) ParseWithPrompt(text string, _ llms.PromptValue) ([]string, error) {
+	return p.Parse(text)
+}
+
+func (p NumberedList) Type() string {
+	return "numbered_list_parser"
+}
@@ -0,0 +1,56 @@
+package outputparser_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/outputparser"
+)
+
+func TestNumberedList(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "dots",
+			input:    "1. foo\n2. bar\n3. baz",
+			expected: []string{"foo", "bar", "baz"},
+		},
+		{
+			name:     "preamble and parentheses",
+			input:    "Here is the plan:\n\n1) foo\n  2) bar baz \n",
+			expected: []string{"foo", "bar baz"},
+		},
+		{
+			name:     "continued lines",
+			input:    "1. foo\n   with details\n\n2. bar",
+			expected: []string{"foo\nwith details", "bar"},
+		},
+		{
+			name:     "steps in bold",
+			input:    "**Step 1:** foo\n**Step 2:** bar",
+			expected: []string{"foo", "bar"},
+		},
+	}
+
+	parser := outputparser.NewNumberedList()
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			output, err := parser.Parse(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, output)
+		})
+	}
+
+	_, err := parser.Parse("no list here")
+	require.ErrorAs(t, err, &outputparser.ParseError{})
+}
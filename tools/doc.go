@@ -1,2 +1,7 @@
 // Package tools defines a standard interface for tools to be used by agents.
+//
+// A Tool takes a single string as input. A StructuredTool instead takes JSON
+// arguments described by a schema, which agents pass on to LLMs that support
+// tool calling with ToLLMTool. NewFunc creates a structured tool from a Go
+// function, generating the schema from the struct of its arguments.
 package tools
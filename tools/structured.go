@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ErrInvalidArguments is returned when the input of a structured tool does not
+// match its schema.
+var ErrInvalidArguments = errors.New("invalid tool arguments")
+
+// InputKey is the name of the single string argument of tools that are not
+// structured, when they are described to an LLM.
+const InputKey = "input"
+
+// StructuredTool is a tool whose input is a JSON object described by a schema.
+// The input passed to Call is the JSON encoding of the arguments.
+type StructuredTool interface {
+	Tool
+	// Schema returns the schema of the arguments of the tool, of type object.
+	Schema() jsonschema.Definition
+}
+
+// Func is a structured tool calling a Go function with its arguments decoded
+// into a struct.
+type Func[T any] struct {
+	name        string
+	description string
+	schema      jsonschema.Definition
+	fn          func(ctx context.Context, args T) (string, error)
+}
+
+var _ StructuredTool = (*Func[struct{}])(nil)
+
+// NewFunc creates a structured tool calling fn. The schema of the arguments is
+// generated from T, which must be a struct, as described for
+// jsonschema.GenerateSchemaForType.
+func NewFunc[T any](
+	name, description string,
+	fn func(ctx context.Context, args T) (string, error),
+) (*Func[T], error) {
+	var args T
+	schema, err := jsonschema.GenerateSchemaForType(args)
+	if err != nil {
+		return nil, fmt.Errorf("tool %s: %w", name, err)
+	}
+	if schema.Type != jsonschema.Object {
+		return nil, fmt.Errorf("tool %s: %w: arguments must be a struct, got %T",
+			name, jsonschema.ErrUnsupportedType, args)
+	}
+
+	return &Func[T]{name: name, description: description, schema: *schema, fn: fn}, nil
+}
+
+// Name returns the name of the tool.
+func (f *Func[T]) Name() string {
+	return f.name
+}
+
+// Description returns the description of the tool.
+func (f *Func[T]) Description() string {
+	return f.description
+}
+
+// Schema returns the schema of the arguments of the tool.
+func (f *Func[T]) Schema() jsonschema.Definition {
+	return f.schema
+}
+
+// Call validates the JSON arguments in input, decodes them and calls the
+// function of the tool.
+func (f *Func[T]) Call(ctx context.Context, input string) (string, error) {
+	if err := Validate(f, input); err != nil {
+		return "", err
+	}
+	var args T
+	if err := json.Unmarshal([]byte(input), &args); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidArguments, err)
+	}
+	return f.fn(ctx, args)
+}
+
+// Validate checks the input of a structured tool against its schema. The input
+// of other tools is always valid.
+func Validate(t Tool, input string) error {
+	st, ok := t.(StructuredTool)
+	if !ok {
+		return nil
+	}
+	if err := jsonschema.ValidateJSON(st.Schema(), []byte(input)); err != nil {
+		return fmt.Errorf("%w for %s: %w", ErrInvalidArguments, t.Name(), err)
+	}
+	return nil
+}
+
+// ToLLMTool describes a tool to an LLM. Structured tools are described by their
+// schema, other tools take a single string argument named InputKey.
+func ToLLMTool(t Tool) llms.Tool {
+	return llms.Tool{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name:        t.Name(),
+			Description: t.Description(),
+			Parameters:  schema(t),
+		},
+	}
+}
+
+// ToLLMTools describes tools to an LLM with ToLLMTool.
+func ToLLMTools(ts []Tool) []llms.Tool {
+	res := make([]llms.Tool, len(ts))
+	for i, t := range ts {
+		res[i] = ToLLMTool(t)
+	}
+	return res
+}
+
+func schema(t Tool) jsonschema.Definition {
+	if st, ok := t.(StructuredTool); ok {
+		return st.Schema()
+	}
+	return jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			InputKey: {Type: jsonschema.String},
+		},
+		Required: []string{InputKey},
+	}
+}
+
+// InputFromArguments turns the JSON arguments of a call the LLM made to the
+// tool into the input for Call. Structured tools get the arguments as they
+// are, other tools the value of their InputKey argument. A nil tool is treated
+// as a tool that is not structured.
+func InputFromArguments(t Tool, arguments string) (string, error) {
+	if _, ok := t.(StructuredTool); ok {
+		return arguments, nil
+	}
+	var args map[string]any
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidArguments, err)
+	}
+	if input, ok := args[InputKey].(string); ok {
+		return input, nil
+	}
+	return arguments, nil
+}
+
+// ArgumentsFromInput is the inverse of InputFromArguments.
+func ArgumentsFromInput(t Tool, input string) string {
+	if _, ok := t.(StructuredTool); ok {
+		return input
+	}
+	arguments, _ := json.Marshal(map[string]string{InputKey: input})
+	return string(arguments)
+}
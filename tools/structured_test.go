@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/llms"
+)
+
+type weatherArgs struct {
+	Location string `json:"location" description:"The city"`
+	Unit     string `json:"unit,omitempty" enum:"celsius,fahrenheit"`
+}
+
+func newWeatherTool(t *testing.T) *Func[weatherArgs] {
+	t.Helper()
+
+	tool, err := NewFunc("weather", "Gets the weather.", func(_ context.Context, args weatherArgs) (string, error) {
+		return fmt.Sprintf("20 degrees %s in %s", args.Unit, args.Location), nil
+	})
+	require.NoError(t, err)
+	return tool
+}
+
+func TestFunc(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tool := newWeatherTool(t)
+	assert.Equal(t, []string{"location"}, tool.Schema().Required)
+
+	output, err := tool.Call(ctx, `{"location": "Berlin", "unit": "celsius"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "20 degrees celsius in Berlin", output)
+
+	_, err = tool.Call(ctx, `{"unit": "kelvin"}`)
+	require.ErrorIs(t, err, ErrInvalidArguments)
+	require.ErrorIs(t, err, jsonschema.ErrInvalidValue)
+
+	_, err = NewFunc("bad", "", func(context.Context, string) (string, error) { return "", nil })
+	require.ErrorIs(t, err, jsonschema.ErrUnsupportedType)
+}
+
+func TestToLLMTool(t *testing.T) {
+	t.Parallel()
+
+	tool := newWeatherTool(t)
+	assert.Equal(t, llms.Tool{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name:        "weather",
+			Description: "Gets the weather.",
+			Parameters:  tool.Schema(),
+		},
+	}, ToLLMTool(tool))
+
+	calc := ToLLMTool(Calculator{})
+	assert.Equal(t, jsonschema.Definition{
+		Type:       jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{InputKey: {Type: jsonschema.String}},
+		Required:   []string{InputKey},
+	}, calc.Function.Parameters)
+}
+
+func TestArguments(t *testing.T) {
+	t.Parallel()
+
+	tool := newWeatherTool(t)
+	input, err := InputFromArguments(tool, `{"location": "Berlin"}`)
+	require.NoError(t, err)
+	assert.Equal(t, `{"location": "Berlin"}`, input)
+	assert.Equal(t, input, ArgumentsFromInput(tool, input))
+
+	input, err = InputFromArguments(Calculator{}, `{"input": "1 + 1"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "1 + 1", input)
+	assert.Equal(t, `{"input":"1 + 1"}`, ArgumentsFromInput(Calculator{}, input))
+
+	_, err = InputFromArguments(Calculator{}, `1 +`)
+	require.ErrorIs(t, err, ErrInvalidArguments)
+}